@@ -1,5 +1,13 @@
 package cache
 
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
 // Config - configuration
 type Config struct {
 	// Max. cache size (in bytes) of keys and values.  Default: unlimited
@@ -16,10 +24,36 @@ type Config struct {
 
 	// User callback function which is called after an element has been deleted automatically
 	OnDelete onDeleteType
+
+	// OnEvict is called after an element has been removed from the cache for
+	// any reason, including Del, Clear, and replacement by Set.  It is useful
+	// to release the resources tied to the elements.  It is called after
+	// OnDelete.
+	OnEvict onEvictType
+
+	// Clock is used to check the expiration of the elements set with
+	// ExpiringCache.SetWithTTL.  If nil, timeutil.SystemClock is used.
+	Clock timeutil.Clock
+
+	// CleanupInterval is the interval with which the expired elements are
+	// removed in the background.  If zero, the expired elements are only
+	// removed when they are accessed or when the cache is full.
+	// ExpiringCache.Close must be called to stop the background cleanup.  It
+	// is ignored by New.
+	CleanupInterval time.Duration
 }
 
 // New - create cache object
 func New(conf Config) Cache {
+	// Cache has no Close method, so the background cleanup could never be
+	// stopped.
+	conf.CleanupInterval = 0
+
+	return newCache(conf)
+}
+
+// NewExpiring returns a new cache that supports per-element TTLs.
+func NewExpiring(conf Config) (c ExpiringCache) {
 	return newCache(conf)
 }
 
@@ -43,10 +77,84 @@ type Cache interface {
 	Stats() Stats
 }
 
+// Persistent is a cache that can be saved and loaded.  The caches returned by
+// New and NewExpiring implement it.
+type Persistent interface {
+	// SaveTo writes all elements that haven't expired to w in a binary
+	// format suitable for LoadFrom.
+	SaveTo(w io.Writer) (err error)
+
+	// LoadFrom reads the elements written by SaveTo from r and adds them to
+	// the cache, skipping the ones that have expired since.  If the data is
+	// corrupted, LoadFrom returns an error and doesn't change the cache.
+	LoadFrom(r io.Reader) (err error)
+}
+
+// ExpiringCache is a Cache that supports per-element TTLs.
+type ExpiringCache interface {
+	Cache
+
+	// SetWithTTL sets data that expires after ttl.  An expired element is
+	// never returned.  A non-positive ttl means that the data never expires.
+	// Return FALSE if data was added;  TRUE if data was replaced
+	SetWithTTL(key []byte, val []byte, ttl time.Duration) bool
+
+	// Close stops the background cleanup, if any.  The cache must not be used
+	// after Close.  Calling Close more than once is safe.
+	Close() (err error)
+}
+
 // Stats - counters
 type Stats struct {
 	Count int
 	Size  int
 	Hit   int
 	Miss  int
+
+	// Evictions is the number of elements removed automatically, either
+	// because the cache was full or because they expired.
+	Evictions int
+}
+
+// EvictReason is the reason for the removal of an element from a cache.
+type EvictReason uint8
+
+// EvictReason values.
+const (
+	// EvictReasonCapacity means that the element has been removed because the
+	// cache was full.
+	EvictReasonCapacity EvictReason = iota + 1
+
+	// EvictReasonExpired means that the element has expired.
+	EvictReasonExpired
+
+	// EvictReasonReplaced means that the element has been replaced with a new
+	// one with the same key.
+	EvictReasonReplaced
+
+	// EvictReasonDeleted means that the element has been deleted explicitly
+	// with Del or Clear.
+	EvictReasonDeleted
+)
+
+// String implements the fmt.Stringer interface for EvictReason.
+func (r EvictReason) String() (s string) {
+	switch r {
+	case EvictReasonCapacity:
+		return "capacity"
+	case EvictReasonExpired:
+		return "expired"
+	case EvictReasonReplaced:
+		return "replaced"
+	case EvictReasonDeleted:
+		return "deleted"
+	default:
+		return fmt.Sprintf("!bad_evict_reason_%d", r)
+	}
+}
+
+// isAutomatic returns true if the removal for this reason is an eviction
+// performed by the cache itself.
+func (r EvictReason) isAutomatic() (ok bool) {
+	return r == EvictReasonCapacity || r == EvictReasonExpired
 }
@@ -1,13 +1,26 @@
 package cache
 
 import (
+	"container/heap"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
+
+	"github.com/AdguardTeam/golibs/timeutil"
 )
 
 type onDeleteType func(key []byte, val []byte)
 
+type onEvictType func(key []byte, val []byte, reason EvictReason)
+
+// evictedItem is an item removed from the cache along with the reason of the
+// removal.
+type evictedItem struct {
+	it     *item
+	reason EvictReason
+}
+
 type cache struct {
 	items map[string]*item
 
@@ -22,15 +35,42 @@ type cache struct {
 
 	conf Config
 
+	clock timeutil.Clock
+
+	// expiry contains the items that have a TTL ordered by their expiration
+	// time.
+	expiry expiryHeap
+
+	// done is closed to stop the background cleanup.  It is nil if there is
+	// no background cleanup.
+	done chan struct{}
+
+	// closeOnce makes sure that done is only closed once.
+	closeOnce sync.Once
+
 	// stats:
-	miss int32 // number of misses
-	hit  int32 // number of hits
+	miss    int32 // number of misses
+	hit     int32 // number of hits
+	evicted int32 // number of automatic evictions
 }
 
 type item struct {
 	key   []byte
 	value []byte
 	used  listItem
+
+	// expire is the time after which the item is expired.  It is zero if the
+	// item never expires.
+	expire time.Time
+
+	// heapIdx is the index of the item in the expiry heap of the cache.  It
+	// is only meaningful if expire is not zero.
+	heapIdx int
+}
+
+// isExpired returns true if it has expired by now.
+func (it *item) isExpired(now time.Time) (ok bool) {
+	return !it.expire.IsZero() && !now.Before(it.expire)
 }
 
 const maxUint = (1 << (unsafe.Sizeof(uint(0)) * 8)) - 1
@@ -52,77 +92,224 @@ func newCache(conf Config) *cache {
 	if c.conf.MaxElementSize > c.conf.MaxSize {
 		c.conf.MaxElementSize = c.conf.MaxSize
 	}
+
+	c.clock = c.conf.Clock
+	if c.clock == nil {
+		c.clock = timeutil.SystemClock{}
+	}
+
+	if c.conf.CleanupInterval > 0 {
+		c.done = make(chan struct{})
+		go c.cleanupLoop(c.clock.NewTicker(c.conf.CleanupInterval))
+	}
+
 	return &c
 }
 
+// cleanupLoop removes the expired items on every tick until c is closed.
+func (c *cache) cleanupLoop(ticker timeutil.Ticker) {
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C():
+			c.removeExpired()
+		}
+	}
+}
+
+// removeExpired removes all expired items and calls the callbacks for them.
+func (c *cache) removeExpired() {
+	c.lock.Lock()
+	removed := c.removeExpiredLocked(nil)
+	c.lock.Unlock()
+
+	c.notify(removed)
+}
+
+// removeExpiredLocked removes all expired items and appends them to removed.
+// c.lock must be locked.
+func (c *cache) removeExpiredLocked(removed []evictedItem) (res []evictedItem) {
+	if len(c.expiry) == 0 {
+		return removed
+	}
+
+	now := c.clock.Now()
+	for len(c.expiry) > 0 && c.expiry[0].isExpired(now) {
+		removed = c.removeItem(removed, c.expiry[0], EvictReasonExpired)
+	}
+
+	return removed
+}
+
+// notify updates the statistics and calls the callbacks for the removed items.
+// c.lock must not be locked.
+func (c *cache) notify(removed []evictedItem) {
+	for _, e := range removed {
+		if e.reason.isAutomatic() {
+			atomic.AddInt32(&c.evicted, 1)
+			if c.conf.OnDelete != nil {
+				c.conf.OnDelete(e.it.key, e.it.value)
+			}
+		}
+
+		if c.conf.OnEvict != nil {
+			c.conf.OnEvict(e.it.key, e.it.value, e.reason)
+		}
+	}
+}
+
+// removeItem removes it from the items map and the usage list and appends it to
+// removed.  c.lock must be locked.
+func (c *cache) removeItem(removed []evictedItem, it *item, reason EvictReason) (res []evictedItem) {
+	if it.used.next != nil {
+		listUnlink(&it.used)
+	}
+	c.size -= uint(len(it.key) + len(it.value))
+	delete(c.items, string(it.key))
+	if !it.expire.IsZero() {
+		heap.Remove(&c.expiry, it.heapIdx)
+	}
+
+	return append(removed, evictedItem{it: it, reason: reason})
+}
+
+// Close implements the ExpiringCache interface for *cache.  It is safe to call
+// it more than once.
+func (c *cache) Close() (err error) {
+	if c.done != nil {
+		c.closeOnce.Do(func() { close(c.done) })
+	}
+
+	return nil
+}
+
 func (c *cache) Clear() {
+	var removed []evictedItem
 	c.lock.Lock()
+	if c.conf.OnEvict != nil {
+		for _, it := range c.items {
+			removed = append(removed, evictedItem{it: it, reason: EvictReasonDeleted})
+		}
+	}
 	c.items = make(map[string]*item)
 	listInit(&c.usage)
 	c.size = 0
+	c.expiry = nil
 	c.lock.Unlock()
 	atomic.StoreInt32(&c.hit, 0)
 	atomic.StoreInt32(&c.miss, 0)
+	atomic.StoreInt32(&c.evicted, 0)
+
+	c.notify(removed)
 }
 
 // Set value
 func (c *cache) Set(key []byte, val []byte) bool {
+	return c.SetWithTTL(key, val, 0)
+}
+
+// SetWithTTL implements the ExpiringCache interface for *cache.
+func (c *cache) SetWithTTL(key []byte, val []byte, ttl time.Duration) bool {
+	var expire time.Time
+	if ttl > 0 {
+		expire = c.clock.Now().Add(ttl)
+	}
+
+	return c.set(key, val, expire)
+}
+
+// set sets the value which expires at expire, unless expire is zero.
+func (c *cache) set(key []byte, val []byte, expire time.Time) (exists bool) {
 	addSize := uint(len(key) + len(val))
 	if addSize > c.conf.MaxElementSize {
 		return false // too large data
 	}
 
-	it := item{}
-	it.key = key
-	it.value = val
+	it := &item{
+		key:    key,
+		value:  val,
+		expire: expire,
+	}
 
 	c.lock.Lock()
+	removed, ok := c.makeRoom(nil, addSize)
+	if ok {
+		removed, exists = c.insert(removed, it)
+	}
+	c.lock.Unlock()
 
-	if !c.conf.EnableLRU &&
-		(c.size+addSize > c.conf.MaxSize || uint(len(c.items)) == c.conf.MaxCount) {
-		c.lock.Unlock()
-		return false // cache is full
+	c.notify(removed)
+
+	return exists
+}
+
+// isFull returns true if an element of addSize bytes doesn't fit into the
+// cache.  c.lock must be locked.
+func (c *cache) isFull(addSize uint) (ok bool) {
+	return c.size+addSize > c.conf.MaxSize || uint(len(c.items)) == c.conf.MaxCount
+}
+
+// makeRoom removes the expired items and, if LRU is enabled, the least
+// recently used ones until an element of addSize bytes fits into the cache.
+// The removed items are appended to removed.  ok is false if the cache is
+// still full.  c.lock must be locked.
+func (c *cache) makeRoom(removed []evictedItem, addSize uint) (res []evictedItem, ok bool) {
+	if c.isFull(addSize) {
+		// Try to free some space by removing the expired items first.
+		removed = c.removeExpiredLocked(removed)
 	}
 
-	for c.size+addSize > c.conf.MaxSize || uint(len(c.items)) == c.conf.MaxCount {
+	if !c.conf.EnableLRU {
+		return removed, !c.isFull(addSize)
+	}
+
+	for c.isFull(addSize) {
 		first := listFirst(&c.usage)
-		it := (*item)(structPtr(unsafe.Pointer(first), unsafe.Offsetof(item{}.used)))
-		c.size -= uint(len(it.key) + len(it.value))
-		listUnlink(first)
-		delete(c.items, string(it.key))
-
-		if c.conf.OnDelete != nil {
-			c.lock.Unlock()
-			c.conf.OnDelete(it.key, it.value)
-			c.lock.Lock()
-		}
+		lru := (*item)(structPtr(unsafe.Pointer(first), unsafe.Offsetof(item{}.used)))
+		removed = c.removeItem(removed, lru, EvictReasonCapacity)
 	}
 
+	return removed, true
+}
+
+// insert adds it to the cache, replacing the item with the same key, if any.
+// The replaced item is appended to removed.  c.lock must be locked.
+func (c *cache) insert(removed []evictedItem, it *item) (res []evictedItem, exists bool) {
 	if c.conf.EnableLRU {
 		listAppend(&it.used, listLast(&c.usage))
 	}
 
-	it2, exists := c.items[string(key)]
+	old, exists := c.items[string(it.key)]
 	if exists {
-		listUnlink(&it2.used)
-		c.size -= uint(len(it2.key) + len(it2.value))
+		removed = c.removeItem(removed, old, EvictReasonReplaced)
 	}
-	c.items[string(key)] = &it
-	c.size += addSize
-	c.lock.Unlock()
 
-	return exists
+	c.items[string(it.key)] = it
+	c.size += uint(len(it.key) + len(it.value))
+	if !it.expire.IsZero() {
+		heap.Push(&c.expiry, it)
+	}
+
+	return removed, exists
 }
 
 // Get value
 func (c *cache) Get(key []byte) []byte {
+	var removed []evictedItem
 	c.lock.Lock()
 	val, ok := c.items[string(key)]
-	if ok && c.conf.EnableLRU {
+	if ok && val.isExpired(c.clock.Now()) {
+		removed = c.removeItem(removed, val, EvictReasonExpired)
+		ok = false
+	} else if ok && c.conf.EnableLRU {
 		listUnlink(&val.used)
 		listAppend(&val.used, listLast(&c.usage))
 	}
 	c.lock.Unlock()
+	c.notify(removed)
 	if !ok {
 		atomic.AddInt32(&c.miss, 1)
 		return nil
@@ -139,18 +326,21 @@ func (c *cache) Del(key []byte) {
 		c.lock.Unlock()
 		return
 	}
-	listUnlink(&it.used)
-	c.size -= uint(len(it.key) + len(it.value))
-	delete(c.items, string(key))
+	removed := c.removeItem(nil, it, EvictReasonDeleted)
 	c.lock.Unlock()
+
+	c.notify(removed)
 }
 
 // GetStats - get counters
 func (c *cache) Stats() Stats {
 	s := Stats{}
+	c.lock.Lock()
 	s.Count = len(c.items)
 	s.Size = int(c.size)
+	c.lock.Unlock()
 	s.Hit = int(atomic.LoadInt32(&c.hit))
 	s.Miss = int(atomic.LoadInt32(&c.miss))
+	s.Evictions = int(atomic.LoadInt32(&c.evicted))
 	return s
 }
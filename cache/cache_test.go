@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/AdguardTeam/golibs/timeutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -110,3 +113,146 @@ func TestParallel(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestCache_SetWithTTL(t *testing.T) {
+	t.Parallel()
+
+	clock := timeutil.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var deleted []string
+	c := NewExpiring(Config{
+		OnDelete: func(key, _ []byte) {
+			deleted = append(deleted, string(key))
+		},
+		Clock:     clock,
+		MaxCount:  2,
+		EnableLRU: true,
+	})
+
+	assert.False(t, c.SetWithTTL([]byte("k1"), []byte("v1"), time.Minute))
+	assert.False(t, c.Set([]byte("k2"), []byte("v2")))
+
+	clock.Advance(time.Minute - time.Second)
+	assert.Equal(t, []byte("v1"), c.Get([]byte("k1")))
+
+	clock.Advance(time.Second)
+	assert.Nil(t, c.Get([]byte("k1")))
+	assert.Equal(t, []string{"k1"}, deleted)
+	assert.Equal(t, []byte("v2"), c.Get([]byte("k2")))
+
+	s := c.Stats()
+	assert.Equal(t, 1, s.Count)
+	assert.Equal(t, 4, s.Size)
+	assert.Equal(t, 2, s.Hit)
+	assert.Equal(t, 1, s.Miss)
+
+	// The expired element is removed instead of the least recently used one.
+	assert.False(t, c.SetWithTTL([]byte("k3"), []byte("v3"), time.Minute))
+	clock.Advance(time.Minute)
+	assert.False(t, c.Set([]byte("k4"), []byte("v4")))
+
+	assert.Equal(t, []string{"k1", "k3"}, deleted)
+	assert.Equal(t, []byte("v2"), c.Get([]byte("k2")))
+	assert.Equal(t, []byte("v4"), c.Get([]byte("k4")))
+}
+
+func TestCache_SetWithTTL_order(t *testing.T) {
+	t.Parallel()
+
+	clock := timeutil.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var deleted []string
+	c := NewExpiring(Config{
+		OnDelete: func(key, _ []byte) {
+			deleted = append(deleted, string(key))
+		},
+		Clock:    clock,
+		MaxCount: 3,
+	})
+
+	c.SetWithTTL([]byte("k1"), []byte("v1"), 3*time.Second)
+	c.SetWithTTL([]byte("k2"), []byte("v2"), time.Second)
+	c.SetWithTTL([]byte("k3"), []byte("v3"), 2*time.Second)
+
+	clock.Advance(time.Second)
+	assert.False(t, c.Set([]byte("k4"), []byte("v4")))
+	assert.Equal(t, []string{"k2"}, deleted)
+
+	clock.Advance(time.Second)
+	c.Del([]byte("k1"))
+	assert.False(t, c.Set([]byte("k5"), []byte("v5")))
+	assert.Equal(t, []string{"k2"}, deleted)
+
+	assert.False(t, c.Set([]byte("k6"), []byte("v6")))
+	assert.Equal(t, []string{"k2", "k3"}, deleted)
+}
+
+func TestCache_cleanup(t *testing.T) {
+	t.Parallel()
+
+	clock := timeutil.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	deleted := make(chan string, 2)
+	c := NewExpiring(Config{
+		OnDelete: func(key, _ []byte) {
+			deleted <- string(key)
+		},
+		Clock:           clock,
+		CleanupInterval: time.Minute,
+	})
+	testutil.CleanupAndRequireSuccess(t, c.Close)
+
+	// Closing twice must not panic.
+	testutil.CleanupAndRequireSuccess(t, c.Close)
+
+	c.SetWithTTL([]byte("k1"), []byte("v1"), time.Second)
+	c.Set([]byte("k2"), []byte("v2"))
+
+	clock.Advance(time.Minute)
+
+	assert.Equal(t, "k1", <-deleted)
+	assert.Eventually(t, func() (ok bool) {
+		return c.Stats().Count == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []byte("v2"), c.Get([]byte("k2")))
+}
+
+func TestCache_OnEvict(t *testing.T) {
+	t.Parallel()
+
+	clock := timeutil.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var got []string
+	c := NewExpiring(Config{
+		OnEvict: func(key, _ []byte, reason EvictReason) {
+			got = append(got, string(key)+":"+reason.String())
+		},
+		Clock:     clock,
+		MaxCount:  2,
+		EnableLRU: true,
+	})
+
+	c.Set([]byte("k1"), []byte("v1"))
+	c.Set([]byte("k1"), []byte("v1"))
+	c.SetWithTTL([]byte("k2"), []byte("v2"), time.Second)
+	c.Set([]byte("k3"), []byte("v3"))
+
+	clock.Advance(time.Second)
+	assert.Nil(t, c.Get([]byte("k2")))
+
+	c.Del([]byte("k3"))
+	c.Set([]byte("k4"), []byte("v4"))
+
+	assert.Equal(t, Stats{Count: 1, Size: 4, Hit: 0, Miss: 1, Evictions: 2}, c.Stats())
+
+	c.Clear()
+
+	assert.Equal(t, []string{
+		"k1:replaced",
+		"k1:capacity",
+		"k2:expired",
+		"k3:deleted",
+		"k4:deleted",
+	}, got)
+	assert.Equal(t, Stats{}, c.Stats())
+}
@@ -0,0 +1,46 @@
+package cache
+
+import "container/heap"
+
+// expiryHeap is a min-heap of the items that have a TTL ordered by their
+// expiration time.  It allows removing the expired items without scanning the
+// whole cache.
+type expiryHeap []*item
+
+// type check
+var _ heap.Interface = (*expiryHeap)(nil)
+
+// Len implements the heap.Interface interface for *expiryHeap.
+func (h *expiryHeap) Len() (n int) { return len(*h) }
+
+// Less implements the heap.Interface interface for *expiryHeap.
+func (h *expiryHeap) Less(i, j int) (ok bool) {
+	return (*h)[i].expire.Before((*h)[j].expire)
+}
+
+// Swap implements the heap.Interface interface for *expiryHeap.
+func (h *expiryHeap) Swap(i, j int) {
+	s := *h
+	s[i], s[j] = s[j], s[i]
+	s[i].heapIdx = i
+	s[j].heapIdx = j
+}
+
+// Push implements the heap.Interface interface for *expiryHeap.  x must be an
+// *item.
+func (h *expiryHeap) Push(x any) {
+	it := x.(*item)
+	it.heapIdx = len(*h)
+	*h = append(*h, it)
+}
+
+// Pop implements the heap.Interface interface for *expiryHeap.
+func (h *expiryHeap) Pop() (x any) {
+	s := *h
+	n := len(s) - 1
+	it := s[n]
+	s[n] = nil
+	*h = s[:n]
+
+	return it
+}
@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+	"unsafe"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// Persistence errors.
+const (
+	// ErrBadMagic is returned by LoadFrom when the data doesn't start with the
+	// expected signature.
+	ErrBadMagic errors.Error = "bad magic"
+
+	// ErrUnsupportedVersion is returned by LoadFrom when the data has a format
+	// version it doesn't support.
+	ErrUnsupportedVersion errors.Error = "unsupported format version"
+
+	// ErrChecksum is returned by LoadFrom when the checksum of the data doesn't
+	// match.
+	ErrChecksum errors.Error = "checksum mismatch"
+)
+
+// persistMagic is the signature of the persisted cache data.
+const persistMagic = "AGCACHE\x00"
+
+// persistVersion is the current version of the persisted cache data format.
+const persistVersion uint16 = 1
+
+// The format of the persisted data is:
+//
+//   magic    [8]byte
+//   version  uint16
+//   count    uint32
+//   entries  [count]entry
+//   checksum uint32
+//
+// where each entry is:
+//
+//   expire   int64   // Unix time in nanoseconds or zero.
+//   keyLen   uint32
+//   key      [keyLen]byte
+//   valLen   uint32
+//   val      [valLen]byte
+//
+// All integers are big-endian.  The checksum is the IEEE CRC-32 of all
+// preceding data, including the magic.
+
+// type check
+var _ Persistent = (*cache)(nil)
+
+// SaveTo implements the Persistent interface for *cache.
+func (c *cache) SaveTo(w io.Writer) (err error) {
+	defer func() { err = errors.Annotate(err, "saving cache: %w") }()
+
+	now := c.clock.Now()
+	items := c.snapshot(now)
+
+	crc := crc32.NewIEEE()
+	bw := bufio.NewWriter(io.MultiWriter(w, crc))
+
+	hdr := make([]byte, 0, len(persistMagic)+2+4)
+	hdr = append(hdr, persistMagic...)
+	hdr = binary.BigEndian.AppendUint16(hdr, persistVersion)
+	hdr = binary.BigEndian.AppendUint32(hdr, uint32(len(items)))
+	_, _ = bw.Write(hdr)
+
+	var buf []byte
+	for _, it := range items {
+		buf = buf[:0]
+
+		var expire int64
+		if !it.expire.IsZero() {
+			expire = it.expire.UnixNano()
+		}
+
+		buf = binary.BigEndian.AppendUint64(buf, uint64(expire))
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(it.key)))
+		buf = append(buf, it.key...)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(it.value)))
+		buf = append(buf, it.value...)
+
+		_, _ = bw.Write(buf)
+	}
+
+	// Flush before computing the checksum so that crc has seen all data.
+	err = bw.Flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(binary.BigEndian.AppendUint32(nil, crc.Sum32()))
+
+	return err
+}
+
+// snapshot returns the items that haven't expired by now, from the least
+// recently used to the most recently used one if LRU is enabled.
+func (c *cache) snapshot(now time.Time) (items []*item) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	items = make([]*item, 0, len(c.items))
+	if c.conf.EnableLRU {
+		for l := listFirst(&c.usage); l != &c.usage; l = l.next {
+			it := (*item)(structPtr(unsafe.Pointer(l), unsafe.Offsetof(item{}.used)))
+			if !it.isExpired(now) {
+				items = append(items, it)
+			}
+		}
+
+		return items
+	}
+
+	for _, it := range c.items {
+		if !it.isExpired(now) {
+			items = append(items, it)
+		}
+	}
+
+	return items
+}
+
+// LoadFrom implements the Persistent interface for *cache.
+func (c *cache) LoadFrom(r io.Reader) (err error) {
+	defer func() { err = errors.Annotate(err, "loading cache: %w") }()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("reading: %w", err)
+	}
+
+	const hdrLen = len(persistMagic) + 2 + 4
+	const sumLen = 4
+	if len(data) < hdrLen+sumLen {
+		return fmt.Errorf("%w: data too short", ErrChecksum)
+	}
+
+	if !bytes.Equal(data[:len(persistMagic)], []byte(persistMagic)) {
+		return ErrBadMagic
+	}
+
+	if v := binary.BigEndian.Uint16(data[len(persistMagic):]); v != persistVersion {
+		return fmt.Errorf("%w: got %d, want %d", ErrUnsupportedVersion, v, persistVersion)
+	}
+
+	body, sum := data[:len(data)-sumLen], binary.BigEndian.Uint32(data[len(data)-sumLen:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return ErrChecksum
+	}
+
+	count := binary.BigEndian.Uint32(body[len(persistMagic)+2:])
+	items, err := parseItems(body[hdrLen:], count)
+	if err != nil {
+		return err
+	}
+
+	now := c.clock.Now()
+	for _, it := range items {
+		if !it.isExpired(now) {
+			c.set(it.key, it.value, it.expire)
+		}
+	}
+
+	return nil
+}
+
+// parseItems parses count entries from b, which must contain exactly that many
+// entries.
+func parseItems(b []byte, count uint32) (items []*item, err error) {
+	for i := uint32(0); i < count; i++ {
+		it := &item{}
+
+		var expire uint64
+		expire, b, err = readUint64(b)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: expire: %w", i, err)
+		}
+
+		if expire != 0 {
+			it.expire = time.Unix(0, int64(expire))
+		}
+
+		it.key, b, err = readBytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: key: %w", i, err)
+		}
+
+		it.value, b, err = readBytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: value: %w", i, err)
+		}
+
+		items = append(items, it)
+	}
+
+	if len(b) != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after entries", len(b))
+	}
+
+	return items, nil
+}
+
+// errUnexpectedEnd is returned when the data ends in the middle of an entry.
+const errUnexpectedEnd errors.Error = "unexpected end of data"
+
+// readUint64 reads a big-endian uint64 from b.
+func readUint64(b []byte) (n uint64, rest []byte, err error) {
+	if len(b) < 8 {
+		return 0, nil, errUnexpectedEnd
+	}
+
+	return binary.BigEndian.Uint64(b), b[8:], nil
+}
+
+// readBytes reads a length-prefixed byte slice from b.  The returned slice is a
+// copy.
+func readBytes(b []byte) (data, rest []byte, err error) {
+	if len(b) < 4 {
+		return nil, nil, errUnexpectedEnd
+	}
+
+	l := binary.BigEndian.Uint32(b)
+	b = b[4:]
+	if uint64(len(b)) < uint64(l) {
+		return nil, nil, errUnexpectedEnd
+	}
+
+	return bytes.Clone(b[:l]), b[l:], nil
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_SaveTo(t *testing.T) {
+	t.Parallel()
+
+	clock := timeutil.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	conf := Config{
+		Clock:     clock,
+		MaxCount:  3,
+		EnableLRU: true,
+	}
+
+	_, ok := New(conf).(Persistent)
+	require.True(t, ok)
+
+	src := newCache(conf)
+	src.Set([]byte("k1"), []byte("v1"))
+	src.SetWithTTL([]byte("k2"), []byte("v2"), time.Minute)
+	src.SetWithTTL([]byte("k3"), []byte("v3"), time.Hour)
+
+	// Make "k1" the most recently used element.
+	require.Equal(t, []byte("v1"), src.Get([]byte("k1")))
+
+	buf := &bytes.Buffer{}
+	err := src.SaveTo(buf)
+	require.NoError(t, err)
+
+	data := buf.Bytes()
+
+	clock.Advance(time.Minute)
+
+	dst := newCache(conf)
+	err = dst.LoadFrom(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, dst.Stats().Count)
+	assert.Nil(t, dst.Get([]byte("k2")))
+
+	// "k3" is the least recently used element, so it is evicted first.
+	dst.Set([]byte("k4"), []byte("v4"))
+	dst.Set([]byte("k5"), []byte("v5"))
+	assert.Nil(t, dst.Get([]byte("k3")))
+	assert.Equal(t, []byte("v1"), dst.Get([]byte("k1")))
+
+	clock.Advance(time.Hour)
+	err = dst.LoadFrom(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	assert.Nil(t, dst.Get([]byte("k3")))
+}
+
+func TestCache_LoadFrom_errors(t *testing.T) {
+	t.Parallel()
+
+	src := newCache(Config{})
+	src.Set([]byte("key"), []byte("value"))
+
+	buf := &bytes.Buffer{}
+	err := src.SaveTo(buf)
+	require.NoError(t, err)
+
+	data := buf.Bytes()
+
+	testCases := []struct {
+		wantErr error
+		modify  func(b []byte) (res []byte)
+		name    string
+	}{{
+		wantErr: ErrBadMagic,
+		modify: func(b []byte) (res []byte) {
+			b[0] = 'X'
+
+			return b
+		},
+		name: "bad_magic",
+	}, {
+		wantErr: ErrUnsupportedVersion,
+		modify: func(b []byte) (res []byte) {
+			b[len(persistMagic)+1] = 2
+
+			return b
+		},
+		name: "bad_version",
+	}, {
+		wantErr: ErrChecksum,
+		modify: func(b []byte) (res []byte) {
+			b[len(b)-6]++
+
+			return b
+		},
+		name: "corrupted",
+	}, {
+		wantErr: ErrChecksum,
+		modify: func(b []byte) (res []byte) {
+			return b[:len(b)-1]
+		},
+		name: "truncated",
+	}, {
+		wantErr: ErrChecksum,
+		modify: func(b []byte) (res []byte) {
+			return b[:5]
+		},
+		name: "too_short",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := tc.modify(bytes.Clone(data))
+
+			c := newCache(Config{})
+			err := c.LoadFrom(bytes.NewReader(b))
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, 0, c.Stats().Count)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"hash/maphash"
+	"time"
+)
+
+// ShardedConfig is the configuration for a *Sharded cache.
+type ShardedConfig[K comparable, V any] struct {
+	// Hash returns the hash of the key, which is used to choose the shard.  It
+	// must not be nil.  See HashString and HashBytes.
+	Hash func(key K) (h uint64)
+
+	// Typed is the configuration of the shards.  MaxSize and MaxCount are
+	// divided evenly between the shards, rounding up, so the eviction happens
+	// per shard.
+	Typed TypedConfig[K, V]
+
+	// Shards is the number of shards.  If zero, 16 is used.
+	Shards int
+}
+
+// Sharded is a cache with keys and values of arbitrary types, which is split
+// into several independent *Typed caches, each with its own lock, so that the
+// operations on the keys from different shards don't wait for each other.  It
+// is safe for concurrent use.
+type Sharded[K comparable, V any] struct {
+	hash   func(key K) (h uint64)
+	shards []*Typed[K, V]
+}
+
+// defaultShards is the default number of shards of a *Sharded cache.
+const defaultShards = 16
+
+// NewSharded returns a new properly initialized *Sharded cache.
+func NewSharded[K comparable, V any](conf ShardedConfig[K, V]) (c *Sharded[K, V]) {
+	n := conf.Shards
+	if n <= 0 {
+		n = defaultShards
+	}
+
+	shardConf := conf.Typed
+	shardConf.MaxSize = divCeil(shardConf.MaxSize, uint(n))
+	shardConf.MaxCount = divCeil(shardConf.MaxCount, uint(n))
+
+	c = &Sharded[K, V]{
+		hash:   conf.Hash,
+		shards: make([]*Typed[K, V], n),
+	}
+
+	for i := range c.shards {
+		c.shards[i] = NewTyped(shardConf)
+	}
+
+	return c
+}
+
+// divCeil returns a divided by b, rounded up.
+func divCeil(a, b uint) (res uint) {
+	res = a / b
+	if a%b != 0 {
+		res++
+	}
+
+	return res
+}
+
+// shard returns the shard for the key.
+func (c *Sharded[K, V]) shard(key K) (s *Typed[K, V]) {
+	return c.shards[c.hash(key)%uint64(len(c.shards))]
+}
+
+// Set sets the value for the key.  It returns true if an existing value has
+// been replaced.
+func (c *Sharded[K, V]) Set(key K, val V) (replaced bool) {
+	return c.shard(key).Set(key, val)
+}
+
+// SetWithTTL sets the value for the key, which expires after ttl.  See
+// Typed.SetWithTTL.
+func (c *Sharded[K, V]) SetWithTTL(key K, val V, ttl time.Duration) (replaced bool) {
+	return c.shard(key).SetWithTTL(key, val, ttl)
+}
+
+// Get returns the value for the key, if there is one and it has not expired.
+func (c *Sharded[K, V]) Get(key K) (val V, ok bool) {
+	return c.shard(key).Get(key)
+}
+
+// Del deletes the value for the key, if any.
+func (c *Sharded[K, V]) Del(key K) {
+	c.shard(key).Del(key)
+}
+
+// Clear deletes all values and resets the statistics.
+func (c *Sharded[K, V]) Clear() {
+	for _, s := range c.shards {
+		s.Clear()
+	}
+}
+
+// Stats returns the sum of the statistics of all shards.
+func (c *Sharded[K, V]) Stats() (s Stats) {
+	for _, sh := range c.shards {
+		ss := sh.Stats()
+		s.Count += ss.Count
+		s.Size += ss.Size
+		s.Hit += ss.Hit
+		s.Miss += ss.Miss
+		s.Evictions += ss.Evictions
+	}
+
+	return s
+}
+
+// hashSeed is the seed used by HashString and HashBytes.
+var hashSeed = maphash.MakeSeed()
+
+// HashString is a hash function for string keys of a *Sharded cache.
+func HashString(s string) (h uint64) {
+	return maphash.String(hashSeed, s)
+}
+
+// HashBytes is a hash function for byte slice keys.  It is useful for keys of
+// array types.
+func HashBytes(b []byte) (h uint64) {
+	return maphash.Bytes(hashSeed, b)
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharded(t *testing.T) {
+	t.Parallel()
+
+	c := NewSharded(ShardedConfig[string, int]{
+		Hash: HashString,
+		Typed: TypedConfig[string, int]{
+			MaxCount:  100,
+			EnableLRU: true,
+		},
+		Shards: 4,
+	})
+
+	for i := 0; i < 1000; i++ {
+		c.Set(strconv.Itoa(i), i)
+	}
+
+	s := c.Stats()
+	assert.LessOrEqual(t, s.Count, 100)
+	assert.Equal(t, 1000-s.Count, s.Evictions)
+
+	v, ok := c.Get("999")
+	assert.True(t, ok)
+	assert.Equal(t, 999, v)
+
+	c.Del("999")
+	_, ok = c.Get("999")
+	assert.False(t, ok)
+
+	c.Clear()
+	assert.Equal(t, Stats{}, c.Stats())
+}
+
+// BenchmarkSharded compares the throughput of a single-lock cache with the one
+// of a sharded cache under parallel load.  The difference only shows on
+// multi-core machines, so run it there with, for example, -cpu=1,4,8.
+func BenchmarkSharded(b *testing.B) {
+	const maxCount = 512
+
+	b.Run("typed", func(b *testing.B) {
+		c := NewTyped(TypedConfig[string, int]{
+			MaxCount:  maxCount,
+			EnableLRU: true,
+		})
+
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				k := benchKeys[i%len(benchKeys)]
+				c.Set(k, i)
+
+				_, _ = c.Get(k)
+			}
+		})
+	})
+
+	b.Run("sharded", func(b *testing.B) {
+		c := NewSharded(ShardedConfig[string, int]{
+			Hash: HashString,
+			Typed: TypedConfig[string, int]{
+				MaxCount:  maxCount,
+				EnableLRU: true,
+			},
+		})
+
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				k := benchKeys[i%len(benchKeys)]
+				c.Set(k, i)
+
+				_, _ = c.Get(k)
+			}
+		})
+	})
+}
@@ -0,0 +1,374 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// TypedConfig is the configuration for a *Typed cache.
+type TypedConfig[K comparable, V any] struct {
+	// OnDelete is called after an element has been deleted automatically,
+	// either because the cache is full or because the element has expired.
+	OnDelete func(key K, val V)
+
+	// OnEvict is called after an element has been removed from the cache for
+	// any reason, including Del, Clear, and replacement by Set.  It is useful
+	// to release the resources tied to the elements.  It is called after
+	// OnDelete.
+	OnEvict func(key K, val V, reason EvictReason)
+
+	// Size returns the size of an element, which is used to check the MaxSize
+	// and MaxElementSize limits.  If nil, the size of every element is zero and
+	// only MaxCount limits the cache.
+	Size func(key K, val V) (n uint)
+
+	// Clock is used to check the expiration of the elements set with
+	// SetWithTTL.  If nil, timeutil.SystemClock is used.
+	Clock timeutil.Clock
+
+	// MaxSize is the maximum total size of the elements.  If zero, the size is
+	// not limited.
+	MaxSize uint
+
+	// MaxElementSize is the maximum size of a single element.  If zero,
+	// MaxSize is used.
+	MaxElementSize uint
+
+	// MaxCount is the maximum number of elements.  If zero, the number is not
+	// limited.
+	MaxCount uint
+
+	// EnableLRU, if true, makes the cache delete the least recently used
+	// elements when it is full.  Otherwise, new elements aren't added to a
+	// full cache.
+	EnableLRU bool
+}
+
+// Typed is a cache with keys and values of arbitrary types.  It has the same
+// eviction semantics as the cache returned by New but doesn't require
+// converting the keys and values to byte slices.  It is safe for concurrent
+// use.
+type Typed[K comparable, V any] struct {
+	onDelete func(key K, val V)
+	onEvict  func(key K, val V, reason EvictReason)
+	sizeOf   func(key K, val V) (n uint)
+	clock    timeutil.Clock
+
+	// mu protects items, usage, size, and the statistics.
+	mu    *sync.Mutex
+	items map[K]*list.Element
+
+	// usage contains *typedItem values.  If LRU is enabled, the least
+	// recently used item is at the front.
+	usage *list.List
+
+	size    uint
+	hit     int
+	miss    int
+	evicted int
+
+	// expiring is the number of items that have a TTL.  It allows skipping
+	// the search for the expired items when there are none.
+	expiring int
+
+	maxSize        uint
+	maxElementSize uint
+	maxCount       uint
+	enableLRU      bool
+}
+
+// typedItem is an element of a *Typed cache.
+type typedItem[K comparable, V any] struct {
+	key K
+	val V
+
+	// expire is the time after which the item is expired.  It is zero if the
+	// item never expires.
+	expire time.Time
+
+	size uint
+}
+
+// typedEvicted is an item removed from a *Typed cache along with the reason of
+// the removal.
+type typedEvicted[K comparable, V any] struct {
+	it     *typedItem[K, V]
+	reason EvictReason
+}
+
+// isExpired returns true if it has expired by now.
+func (it *typedItem[K, V]) isExpired(now time.Time) (ok bool) {
+	return !it.expire.IsZero() && !now.Before(it.expire)
+}
+
+// NewTyped returns a new properly initialized *Typed cache.
+func NewTyped[K comparable, V any](conf TypedConfig[K, V]) (c *Typed[K, V]) {
+	c = &Typed[K, V]{
+		onDelete:       conf.OnDelete,
+		onEvict:        conf.OnEvict,
+		sizeOf:         conf.Size,
+		clock:          conf.Clock,
+		mu:             &sync.Mutex{},
+		items:          map[K]*list.Element{},
+		usage:          list.New(),
+		maxSize:        conf.MaxSize,
+		maxElementSize: conf.MaxElementSize,
+		maxCount:       conf.MaxCount,
+		enableLRU:      conf.EnableLRU,
+	}
+
+	if c.clock == nil {
+		c.clock = timeutil.SystemClock{}
+	}
+
+	if c.maxSize == 0 {
+		c.maxSize = maxUint
+	}
+
+	if c.maxCount == 0 {
+		c.maxCount = maxUint
+	}
+
+	if c.maxElementSize == 0 || c.maxElementSize > c.maxSize {
+		c.maxElementSize = c.maxSize
+	}
+
+	return c
+}
+
+// Set sets the value for the key.  It returns true if an existing value has
+// been replaced.
+func (c *Typed[K, V]) Set(key K, val V) (replaced bool) {
+	return c.SetWithTTL(key, val, 0)
+}
+
+// SetWithTTL sets the value for the key, which expires after ttl.  A
+// non-positive ttl means that the value never expires.  It returns true if an
+// existing value has been replaced.
+func (c *Typed[K, V]) SetWithTTL(key K, val V, ttl time.Duration) (replaced bool) {
+	var size uint
+	if c.sizeOf != nil {
+		size = c.sizeOf(key, val)
+	}
+
+	if size > c.maxElementSize {
+		return false
+	}
+
+	it := &typedItem[K, V]{
+		key:  key,
+		val:  val,
+		size: size,
+	}
+
+	if ttl > 0 {
+		it.expire = c.clock.Now().Add(ttl)
+	}
+
+	var removed []typedEvicted[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed, ok := c.makeRoom(removed, key, size)
+	if !ok {
+		return false
+	}
+
+	if e, ok := c.items[key]; ok {
+		removed = c.remove(removed, e, EvictReasonReplaced)
+		replaced = true
+	}
+
+	c.items[key] = c.usage.PushBack(it)
+	c.size += size
+	if !it.expire.IsZero() {
+		c.expiring++
+	}
+
+	return replaced
+}
+
+// fits returns true if there is space for an element of the given size for
+// key, taking into account that the existing element for key, if any, is
+// replaced.  c.mu must be locked.
+func (c *Typed[K, V]) fits(key K, size uint) (ok bool) {
+	curSize, count := c.size, uint(len(c.items))
+	if e, ok := c.items[key]; ok {
+		curSize -= e.Value.(*typedItem[K, V]).size
+		count--
+	}
+
+	return curSize+size <= c.maxSize && count < c.maxCount
+}
+
+// makeRoom removes the expired items and, if LRU is enabled, the least
+// recently used ones until an element of the given size for key fits into the
+// cache.  The removed items are appended to removed.  ok is false if the cache
+// is still full, in which case the existing element for key is kept.  c.mu
+// must be locked.
+func (c *Typed[K, V]) makeRoom(
+	removed []typedEvicted[K, V],
+	key K,
+	size uint,
+) (res []typedEvicted[K, V], ok bool) {
+	if !c.fits(key, size) && c.expiring > 0 {
+		removed = c.removeExpired(removed, c.clock.Now())
+	}
+
+	if c.fits(key, size) {
+		return removed, true
+	} else if !c.enableLRU {
+		return removed, false
+	}
+
+	// Move the element being replaced to the back so that it's never evicted,
+	// since a new element always fits into a cache that contains nothing but
+	// the element it replaces.
+	if e, ok := c.items[key]; ok {
+		c.usage.MoveToBack(e)
+	}
+
+	for !c.fits(key, size) {
+		removed = c.remove(removed, c.usage.Front(), EvictReasonCapacity)
+	}
+
+	return removed, true
+}
+
+// Get returns the value for the key, if there is one and it has not expired.
+func (c *Typed[K, V]) Get(key K) (val V, ok bool) {
+	var removed []typedEvicted[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok {
+		c.miss++
+
+		return val, false
+	}
+
+	it := e.Value.(*typedItem[K, V])
+	if it.isExpired(c.clock.Now()) {
+		removed = c.remove(removed, e, EvictReasonExpired)
+		c.miss++
+
+		return val, false
+	}
+
+	if c.enableLRU {
+		c.usage.MoveToBack(e)
+	}
+
+	c.hit++
+
+	return it.val, true
+}
+
+// Del deletes the value for the key, if any.  OnDelete is not called.
+func (c *Typed[K, V]) Del(key K) {
+	var removed []typedEvicted[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.items[key]; ok {
+		removed = c.remove(removed, e, EvictReasonDeleted)
+	}
+}
+
+// Clear deletes all values and resets the statistics.  OnDelete is not called.
+func (c *Typed[K, V]) Clear() {
+	var removed []typedEvicted[K, V]
+	defer func() { c.notify(removed) }()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.onEvict != nil {
+		for e := c.usage.Front(); e != nil; e = e.Next() {
+			removed = append(removed, typedEvicted[K, V]{
+				it:     e.Value.(*typedItem[K, V]),
+				reason: EvictReasonDeleted,
+			})
+		}
+	}
+
+	c.items = map[K]*list.Element{}
+	c.usage.Init()
+	c.size, c.hit, c.miss, c.evicted, c.expiring = 0, 0, 0, 0, 0
+}
+
+// Stats returns the statistics of the cache.
+func (c *Typed[K, V]) Stats() (s Stats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{
+		Count:     len(c.items),
+		Size:      int(c.size),
+		Hit:       c.hit,
+		Miss:      c.miss,
+		Evictions: c.evicted,
+	}
+}
+
+// remove removes e from the cache and appends its item to removed.  c.mu must
+// be locked.
+func (c *Typed[K, V]) remove(
+	removed []typedEvicted[K, V],
+	e *list.Element,
+	reason EvictReason,
+) (res []typedEvicted[K, V]) {
+	it := c.usage.Remove(e).(*typedItem[K, V])
+	delete(c.items, it.key)
+	c.size -= it.size
+	if !it.expire.IsZero() {
+		c.expiring--
+	}
+
+	if reason.isAutomatic() {
+		c.evicted++
+	}
+
+	return append(removed, typedEvicted[K, V]{it: it, reason: reason})
+}
+
+// removeExpired removes all items that have expired by now and appends them to
+// removed.  c.mu must be locked.
+func (c *Typed[K, V]) removeExpired(
+	removed []typedEvicted[K, V],
+	now time.Time,
+) (res []typedEvicted[K, V]) {
+	for e := c.usage.Front(); e != nil; {
+		next := e.Next()
+		if e.Value.(*typedItem[K, V]).isExpired(now) {
+			removed = c.remove(removed, e, EvictReasonExpired)
+		}
+
+		e = next
+	}
+
+	return removed
+}
+
+// notify calls the callbacks for the removed items.  c.mu must not be locked.
+func (c *Typed[K, V]) notify(removed []typedEvicted[K, V]) {
+	for _, e := range removed {
+		if c.onDelete != nil && e.reason.isAutomatic() {
+			c.onDelete(e.it.key, e.it.val)
+		}
+
+		if c.onEvict != nil {
+			c.onEvict(e.it.key, e.it.val, e.reason)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTyped(t *testing.T) {
+	t.Parallel()
+
+	var deleted []string
+	c := NewTyped(TypedConfig[string, int]{
+		OnDelete: func(key string, _ int) {
+			deleted = append(deleted, key)
+		},
+		Size: func(key string, _ int) (n uint) {
+			return uint(len(key))
+		},
+		MaxSize:   6,
+		MaxCount:  2,
+		EnableLRU: true,
+	})
+
+	_, ok := c.Get("k1")
+	assert.False(t, ok)
+
+	assert.False(t, c.Set("k1", 1))
+	assert.False(t, c.Set("k2", 2))
+	assert.True(t, c.Set("k1", 11))
+
+	v, ok := c.Get("k1")
+	assert.True(t, ok)
+	assert.Equal(t, 11, v)
+
+	// MaxCount limit, "k2" is the least recently used.
+	assert.False(t, c.Set("k3", 3))
+	assert.Equal(t, []string{"k2"}, deleted)
+
+	// MaxSize limit.
+	assert.False(t, c.Set("k4444", 4))
+	assert.Equal(t, []string{"k2", "k1", "k3"}, deleted)
+
+	// MaxElementSize limit.
+	assert.False(t, c.Set("k555555", 5))
+
+	_, ok = c.Get("k555555")
+	assert.False(t, ok)
+
+	c.Del("k4444")
+	assert.Equal(t, Stats{Count: 0, Size: 0, Hit: 1, Miss: 2, Evictions: 3}, c.Stats())
+
+	c.Clear()
+	assert.Equal(t, Stats{}, c.Stats())
+}
+
+func TestTyped_noLRU(t *testing.T) {
+	t.Parallel()
+
+	c := NewTyped(TypedConfig[int, int]{
+		MaxCount: 1,
+	})
+
+	assert.False(t, c.Set(1, 1))
+	assert.False(t, c.Set(2, 2))
+
+	_, ok := c.Get(2)
+	assert.False(t, ok)
+
+	assert.True(t, c.Set(1, 11))
+
+	v, ok := c.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, 11, v)
+}
+
+func TestTyped_noLRU_replaceTooLarge(t *testing.T) {
+	t.Parallel()
+
+	c := NewTyped(TypedConfig[string, string]{
+		Size: func(_, val string) (n uint) {
+			return uint(len(val))
+		},
+		MaxSize: 4,
+	})
+
+	assert.False(t, c.Set("k1", "v1"))
+	assert.False(t, c.Set("k2", "v2"))
+
+	// The new value doesn't fit even after the old one is removed, so the
+	// old value must be kept.
+	assert.False(t, c.Set("k1", "v111"))
+
+	v, ok := c.Get("k1")
+	assert.True(t, ok)
+	assert.Equal(t, "v1", v)
+
+	// The new value fits in place of the old one.
+	assert.True(t, c.Set("k2", "v3"))
+
+	v, ok = c.Get("k2")
+	assert.True(t, ok)
+	assert.Equal(t, "v3", v)
+}
+
+func TestTyped_SetWithTTL(t *testing.T) {
+	t.Parallel()
+
+	clock := timeutil.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var deleted []int
+	c := NewTyped(TypedConfig[int, string]{
+		OnDelete: func(key int, _ string) {
+			deleted = append(deleted, key)
+		},
+		Clock:     clock,
+		MaxCount:  2,
+		EnableLRU: true,
+	})
+
+	c.SetWithTTL(1, "v1", time.Minute)
+	c.Set(2, "v2")
+
+	clock.Advance(time.Minute)
+
+	_, ok := c.Get(1)
+	assert.False(t, ok)
+	assert.Equal(t, []int{1}, deleted)
+
+	// The expired element is removed instead of the least recently used one.
+	c.SetWithTTL(3, "v3", time.Minute)
+	clock.Advance(time.Minute)
+	c.Set(4, "v4")
+
+	assert.Equal(t, []int{1, 3}, deleted)
+
+	v, ok := c.Get(2)
+	assert.True(t, ok)
+	assert.Equal(t, "v2", v)
+}
+
+func TestTyped_OnEvict(t *testing.T) {
+	t.Parallel()
+
+	clock := timeutil.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	var got []string
+	c := NewTyped(TypedConfig[string, int]{
+		OnEvict: func(key string, _ int, reason EvictReason) {
+			got = append(got, key+":"+reason.String())
+		},
+		Clock:     clock,
+		MaxCount:  2,
+		EnableLRU: true,
+	})
+
+	c.Set("k1", 1)
+	c.Set("k1", 1)
+	c.SetWithTTL("k2", 2, time.Second)
+	c.Set("k3", 3)
+
+	clock.Advance(time.Second)
+	_, ok := c.Get("k2")
+	assert.False(t, ok)
+
+	c.Del("k3")
+	c.Set("k4", 4)
+
+	assert.Equal(t, Stats{Count: 1, Size: 0, Hit: 0, Miss: 1, Evictions: 2}, c.Stats())
+
+	c.Clear()
+
+	assert.Equal(t, []string{
+		"k1:replaced",
+		"k1:capacity",
+		"k2:expired",
+		"k3:deleted",
+		"k4:deleted",
+	}, got)
+	assert.Equal(t, Stats{}, c.Stats())
+}
+
+func TestEvictReason_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "capacity", EvictReasonCapacity.String())
+	assert.Equal(t, "!bad_evict_reason_0", EvictReason(0).String())
+}
+
+// benchKeys are the keys for the benchmarks.
+var benchKeys = func() (keys []string) {
+	keys = make([]string, 1024)
+	for i := range keys {
+		keys[i] = "key-" + strconv.Itoa(i)
+	}
+
+	return keys
+}()
+
+func BenchmarkTyped(b *testing.B) {
+	b.Run("bytes", func(b *testing.B) {
+		c := New(Config{
+			MaxCount:  512,
+			EnableLRU: true,
+		})
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			k := benchKeys[i%len(benchKeys)]
+			c.Set([]byte(k), []byte(strconv.Itoa(i)))
+
+			v := c.Get([]byte(k))
+			_, _ = strconv.Atoi(string(v))
+		}
+	})
+
+	b.Run("typed", func(b *testing.B) {
+		c := NewTyped(TypedConfig[string, int]{
+			MaxCount:  512,
+			EnableLRU: true,
+		})
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			k := benchKeys[i%len(benchKeys)]
+			c.Set(k, i)
+
+			_, _ = c.Get(k)
+		}
+	})
+
+	// Most recent result:
+	//
+	//	goos: linux
+	//	goarch: amd64
+	//	pkg: github.com/AdguardTeam/golibs/cache
+	//	cpu: Intel(R) Xeon(R) Processor
+	//	BenchmarkTyped/bytes         	 2391248	       478.9 ns/op	     128 B/op	       4 allocs/op
+	//	BenchmarkTyped/typed         	 2801658	       459.9 ns/op	     120 B/op	       2 allocs/op
+}
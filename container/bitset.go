@@ -0,0 +1,179 @@
+package container
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// wordBits is the number of bits in a word of a *BitSet.
+const wordBits = 64
+
+// BitSet is a dense set of non-negative integers, which uses one bit per
+// integer up to the largest one ever added.  It is far more compact than a map
+// when the integers are dense, for example for IDs.  The zero value is an empty
+// set ready to use.  A nil *BitSet is an empty set for all methods that don't
+// modify it.  It is not safe for concurrent use.
+type BitSet struct {
+	words []uint64
+}
+
+// NewBitSet returns a new empty *BitSet with the space preallocated for the
+// integers in the range [0, n).
+func NewBitSet(n uint64) (b *BitSet) {
+	return &BitSet{
+		words: make([]uint64, 0, wordIndex(n+wordBits-1)),
+	}
+}
+
+// wordIndex returns the index of the word containing bit i.
+func wordIndex(i uint64) (idx uint64) {
+	return i / wordBits
+}
+
+// bitMask returns the mask of bit i within its word.
+func bitMask(i uint64) (mask uint64) {
+	return 1 << (i % wordBits)
+}
+
+// Set adds i to the set, growing it if necessary.
+func (b *BitSet) Set(i uint64) {
+	idx := wordIndex(i)
+	if idx >= uint64(len(b.words)) {
+		b.words = slices.Grow(b.words, int(idx)+1-len(b.words))
+		b.words = b.words[:idx+1]
+	}
+
+	b.words[idx] |= bitMask(i)
+}
+
+// Clear removes i from the set.
+func (b *BitSet) Clear(i uint64) {
+	if b == nil {
+		return
+	}
+
+	idx := wordIndex(i)
+	if idx < uint64(len(b.words)) {
+		b.words[idx] &^= bitMask(i)
+	}
+}
+
+// Test returns true if i is in the set.
+func (b *BitSet) Test(i uint64) (ok bool) {
+	if b == nil {
+		return false
+	}
+
+	idx := wordIndex(i)
+
+	return idx < uint64(len(b.words)) && b.words[idx]&bitMask(i) != 0
+}
+
+// Count returns the number of integers in the set.
+func (b *BitSet) Count() (n int) {
+	if b == nil {
+		return 0
+	}
+
+	for _, w := range b.words {
+		n += bits.OnesCount64(w)
+	}
+
+	return n
+}
+
+// Range calls f with each integer in the set in the ascending order.  If cont is
+// false, Range stops the iteration.
+func (b *BitSet) Range(f func(i uint64) (cont bool)) {
+	if b == nil {
+		return
+	}
+
+	for idx, w := range b.words {
+		for w != 0 {
+			tz := bits.TrailingZeros64(w)
+			if !f(uint64(idx)*wordBits + uint64(tz)) {
+				return
+			}
+
+			// Clear the lowest set bit.
+			w &= w - 1
+		}
+	}
+}
+
+// Clone returns a copy of the set.  Clone returns nil if the set is nil.
+func (b *BitSet) Clone() (clone *BitSet) {
+	if b == nil {
+		return nil
+	}
+
+	return &BitSet{
+		words: slices.Clone(b.words),
+	}
+}
+
+// Equal returns true if b and other contain the same integers.  Nil and empty
+// sets are equal.
+func (b *BitSet) Equal(other *BitSet) (ok bool) {
+	bw, ow := b.trimmed(), other.trimmed()
+
+	return slices.Equal(bw, ow)
+}
+
+// trimmed returns the words of the set without the trailing zero words.
+func (b *BitSet) trimmed() (words []uint64) {
+	if b == nil {
+		return nil
+	}
+
+	words = b.words
+	for len(words) > 0 && words[len(words)-1] == 0 {
+		words = words[:len(words)-1]
+	}
+
+	return words
+}
+
+// And removes the integers that are not in other from b.
+func (b *BitSet) And(other *BitSet) {
+	var ow []uint64
+	if other != nil {
+		ow = other.words
+	}
+
+	for idx := range b.words {
+		if idx < len(ow) {
+			b.words[idx] &= ow[idx]
+		} else {
+			b.words[idx] = 0
+		}
+	}
+}
+
+// Or adds the integers from other to b.
+func (b *BitSet) Or(other *BitSet) {
+	if other == nil {
+		return
+	}
+
+	if len(other.words) > len(b.words) {
+		b.words = append(b.words, make([]uint64, len(other.words)-len(b.words))...)
+	}
+
+	for idx, w := range other.words {
+		b.words[idx] |= w
+	}
+}
+
+// AndNot removes the integers that are in other from b.
+func (b *BitSet) AndNot(other *BitSet) {
+	if other == nil {
+		return
+	}
+
+	n := min(len(b.words), len(other.words))
+	for idx := 0; idx < n; idx++ {
+		b.words[idx] &^= other.words[idx]
+	}
+}
@@ -0,0 +1,52 @@
+package container_test
+
+import (
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/container"
+)
+
+func ExampleBitSet() {
+	printAll := func(b *container.BitSet) {
+		var ids []uint64
+		b.Range(func(i uint64) (cont bool) {
+			ids = append(ids, i)
+
+			return true
+		})
+
+		fmt.Println(ids, b.Count())
+	}
+
+	a := container.NewBitSet(256)
+	a.Set(1)
+	a.Set(64)
+	a.Set(200)
+	printAll(a)
+
+	fmt.Println(a.Test(64), a.Test(65))
+
+	b := &container.BitSet{}
+	b.Set(64)
+	b.Set(1000)
+
+	or := a.Clone()
+	or.Or(b)
+	printAll(or)
+
+	and := a.Clone()
+	and.And(b)
+	printAll(and)
+
+	andNot := a.Clone()
+	andNot.AndNot(b)
+	printAll(andNot)
+
+	// Output:
+	//
+	// [1 64 200] 3
+	// true false
+	// [1 64 200 1000] 4
+	// [64] 1
+	// [1 200] 2
+}
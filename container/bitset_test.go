@@ -0,0 +1,68 @@
+package container_test
+
+import (
+	"testing"
+
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitSet(t *testing.T) {
+	t.Parallel()
+
+	b := &container.BitSet{}
+	assert.False(t, b.Test(0))
+
+	for _, i := range []uint64{0, 63, 64, 127, 128, 1 << 20} {
+		b.Set(i)
+		assert.True(t, b.Test(i), "i=%d", i)
+		assert.False(t, b.Test(i+1), "i=%d", i+1)
+	}
+
+	assert.Equal(t, 6, b.Count())
+
+	b.Clear(63)
+	b.Clear(1 << 30)
+	assert.False(t, b.Test(63))
+	assert.Equal(t, 5, b.Count())
+
+	var got []uint64
+	b.Range(func(i uint64) (cont bool) {
+		got = append(got, i)
+
+		return len(got) < 3
+	})
+
+	assert.Equal(t, []uint64{0, 64, 127}, got)
+}
+
+func TestBitSet_Equal(t *testing.T) {
+	t.Parallel()
+
+	var nilSet *container.BitSet
+
+	a := container.NewBitSet(1024)
+	assert.True(t, a.Equal(nilSet))
+
+	a.Set(500)
+	assert.False(t, a.Equal(nilSet))
+
+	b := &container.BitSet{}
+	b.Set(500)
+	b.Set(1000)
+	b.Clear(1000)
+	assert.True(t, a.Equal(b))
+
+	// Operations with nil sets.
+	a.Or(nilSet)
+	a.AndNot(nilSet)
+	assert.True(t, a.Equal(b))
+
+	a.And(nilSet)
+	assert.Equal(t, 0, a.Count())
+
+	assert.False(t, nilSet.Test(1))
+	assert.Equal(t, 0, nilSet.Count())
+	assert.Nil(t, nilSet.Clone())
+	assert.NotPanics(t, func() { nilSet.Clear(1) })
+}
@@ -0,0 +1,6 @@
+// Package container contains generic container types, such as sets and
+// ordered maps.
+package container
+
+// unit is a convenient alias for struct{}.
+type unit = struct{}
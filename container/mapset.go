@@ -0,0 +1,323 @@
+package container
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// MapSet is a set of values backed by a map.  A nil MapSet is an empty set for
+// all methods except Add and the in-place operations, which panic, just like
+// writing to a nil map does.  It is not safe for concurrent use.
+//
+// MapSet marshals to JSON as an array and to text as a comma-separated list.
+// In both cases the elements are sorted, so the result is deterministic.
+// Elements of string, integer, and floating-point kinds are sorted by their
+// values, other elements are sorted by their encoded form.
+type MapSet[T comparable] map[T]unit
+
+// NewMapSet returns a new MapSet containing values.
+func NewMapSet[T comparable](values ...T) (set MapSet[T]) {
+	set = make(MapSet[T], len(values))
+	for _, v := range values {
+		set.Add(v)
+	}
+
+	return set
+}
+
+// Add adds v to the set.
+func (set MapSet[T]) Add(v T) {
+	set[v] = unit{}
+}
+
+// Delete removes v from the set.
+func (set MapSet[T]) Delete(v T) {
+	delete(set, v)
+}
+
+// Has returns true if v is in the set.
+func (set MapSet[T]) Has(v T) (ok bool) {
+	_, ok = set[v]
+
+	return ok
+}
+
+// Len returns the number of elements in the set.
+func (set MapSet[T]) Len() (n int) {
+	return len(set)
+}
+
+// Clear removes all elements from the set.
+func (set MapSet[T]) Clear() {
+	clear(set)
+}
+
+// Clone returns a copy of the set.  Clone returns nil if the set is nil.
+func (set MapSet[T]) Clone() (clone MapSet[T]) {
+	if set == nil {
+		return nil
+	}
+
+	clone = make(MapSet[T], len(set))
+	for v := range set {
+		clone[v] = unit{}
+	}
+
+	return clone
+}
+
+// Range calls f with each value of the set in an undefined order.  If cont is
+// false, Range stops the iteration.
+func (set MapSet[T]) Range(f func(v T) (cont bool)) {
+	for v := range set {
+		if !f(v) {
+			return
+		}
+	}
+}
+
+// Values returns all values of the set in an undefined order.  Values returns
+// nil if the set is nil.
+func (set MapSet[T]) Values() (values []T) {
+	if set == nil {
+		return nil
+	}
+
+	values = make([]T, 0, len(set))
+	for v := range set {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// Equal returns true if set and other contain the same elements.  Nil and empty
+// sets are equal.
+func (set MapSet[T]) Equal(other MapSet[T]) (ok bool) {
+	return len(set) == len(other) && set.SubsetOf(other)
+}
+
+// SubsetOf returns true if all elements of set are in other.
+func (set MapSet[T]) SubsetOf(other MapSet[T]) (ok bool) {
+	if len(set) > len(other) {
+		return false
+	}
+
+	for v := range set {
+		if !other.Has(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Union returns a new set containing the elements of both set and other.
+func (set MapSet[T]) Union(other MapSet[T]) (res MapSet[T]) {
+	res = make(MapSet[T], max(len(set), len(other)))
+	res.UnionInPlace(set)
+	res.UnionInPlace(other)
+
+	return res
+}
+
+// UnionInPlace adds all elements of other to set.
+func (set MapSet[T]) UnionInPlace(other MapSet[T]) {
+	for v := range other {
+		set[v] = unit{}
+	}
+}
+
+// Intersection returns a new set containing the elements that are in both set
+// and other.
+func (set MapSet[T]) Intersection(other MapSet[T]) (res MapSet[T]) {
+	if len(set) > len(other) {
+		set, other = other, set
+	}
+
+	res = MapSet[T]{}
+	for v := range set {
+		if other.Has(v) {
+			res[v] = unit{}
+		}
+	}
+
+	return res
+}
+
+// IntersectionInPlace removes the elements that are not in other from set.
+func (set MapSet[T]) IntersectionInPlace(other MapSet[T]) {
+	for v := range set {
+		if !other.Has(v) {
+			delete(set, v)
+		}
+	}
+}
+
+// Difference returns a new set containing the elements of set that are not in
+// other.
+func (set MapSet[T]) Difference(other MapSet[T]) (res MapSet[T]) {
+	res = MapSet[T]{}
+	for v := range set {
+		if !other.Has(v) {
+			res[v] = unit{}
+		}
+	}
+
+	return res
+}
+
+// DifferenceInPlace removes the elements of other from set.
+func (set MapSet[T]) DifferenceInPlace(other MapSet[T]) {
+	for v := range other {
+		delete(set, v)
+	}
+}
+
+// String implements the fmt.Stringer interface for MapSet.  The values are
+// sorted as in MarshalJSON.
+func (set MapSet[T]) String() (s string) {
+	return fmt.Sprint(sortedValues(set, func(v any) (s string) { return fmt.Sprint(v) }))
+}
+
+// MarshalJSON implements the json.Marshaler interface for MapSet.  A nil set is
+// marshaled as null.
+func (set MapSet[T]) MarshalJSON() (b []byte, err error) {
+	if set == nil {
+		return []byte("null"), nil
+	}
+
+	encoded := make(map[T][]byte, len(set))
+	for v := range set {
+		encoded[v], err = json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling value %v: %w", v, err)
+		}
+	}
+
+	values := sortedValues(set, func(v any) (s string) { return string(encoded[v.(T)]) })
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte('[')
+	for i, v := range values {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		buf.Write(encoded[v])
+	}
+
+	buf.WriteByte(']')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for *MapSet.  The
+// elements are added to the set, which is allocated if necessary.  JSON null is
+// a no-op.
+func (set *MapSet[T]) UnmarshalJSON(b []byte) (err error) {
+	var values []T
+	err = json.Unmarshal(b, &values)
+	if err != nil {
+		return err
+	} else if values == nil {
+		return nil
+	}
+
+	set.addAll(values)
+
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for MapSet.  The
+// elements must be of a string or integer kind or implement
+// encoding.TextMarshaler.  The elements must not contain commas.
+func (set MapSet[T]) MarshalText() (text []byte, err error) {
+	encoded := make(map[T]string, len(set))
+	for v := range set {
+		encoded[v], err = marshalKey(v)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling value %v: %w", v, err)
+		}
+	}
+
+	values := sortedValues(set, func(v any) (s string) { return encoded[v.(T)] })
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		strs = append(strs, encoded[v])
+	}
+
+	return []byte(strings.Join(strs, ",")), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for *MapSet.
+// See MarshalText.  The elements are added to the set, which is allocated if
+// necessary.  Empty text is an empty set.
+func (set *MapSet[T]) UnmarshalText(text []byte) (err error) {
+	var values []T
+	if len(text) > 0 {
+		for _, s := range strings.Split(string(text), ",") {
+			var v T
+			err = unmarshalKey(s, &v)
+			if err != nil {
+				return fmt.Errorf("unmarshaling value %q: %w", s, err)
+			}
+
+			values = append(values, v)
+		}
+	}
+
+	set.addAll(values)
+
+	return nil
+}
+
+// addAll adds values to the set, allocating it if necessary.
+func (set *MapSet[T]) addAll(values []T) {
+	if *set == nil {
+		*set = make(MapSet[T], len(values))
+	}
+
+	for _, v := range values {
+		(*set)[v] = unit{}
+	}
+}
+
+// sortedValues returns the values of set sorted by their values if they are of
+// an ordered kind and by their representation returned by repr otherwise.
+func sortedValues[T comparable](set MapSet[T], repr func(v any) (s string)) (values []T) {
+	values = set.Values()
+	slices.SortFunc(values, func(a, b T) (res int) {
+		return compareAny(a, b, repr)
+	})
+
+	return values
+}
+
+// compareAny compares a and b by their kinds first, then by their values if
+// they are of an ordered kind and by their representations otherwise.  The
+// kinds may differ if the type of the set is an interface type.
+func compareAny(a, b any, repr func(v any) (s string)) (res int) {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if ka, kb := va.Kind(), vb.Kind(); ka != kb {
+		return cmp.Compare(ka, kb)
+	}
+
+	switch va.Kind() {
+	case reflect.String:
+		return cmp.Compare(va.String(), vb.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(va.Int(), vb.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(va.Uint(), vb.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(va.Float(), vb.Float())
+	default:
+		return cmp.Compare(repr(a), repr(b))
+	}
+}
@@ -0,0 +1,46 @@
+package container_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/container"
+)
+
+func ExampleMapSet() {
+	allow := container.NewMapSet("example.com", "example.org", "example.net")
+	block := container.NewMapSet("example.net", "bad.example")
+
+	fmt.Println(allow.Union(block))
+	fmt.Println(allow.Intersection(block))
+	fmt.Println(allow.Difference(block))
+	fmt.Println(container.NewMapSet("example.com").SubsetOf(allow))
+
+	allow.DifferenceInPlace(block)
+	fmt.Println(allow)
+
+	b, err := json.Marshal(allow)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(string(b))
+
+	var set container.MapSet[int]
+	err = json.Unmarshal([]byte(`[10, 9, 10]`), &set)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(set)
+
+	// Output:
+	//
+	// [bad.example example.com example.net example.org]
+	// [example.net]
+	// [example.com example.org]
+	// true
+	// [example.com example.org]
+	// ["example.com","example.org"]
+	// [9 10]
+}
@@ -0,0 +1,197 @@
+package container_test
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapSet_algebra(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		a         []int
+		b         []int
+		wantUnion []int
+		wantInter []int
+		wantDiff  []int
+		wantSub   bool
+		wantEqual bool
+	}{{
+		name:      "empty",
+		a:         nil,
+		b:         nil,
+		wantUnion: nil,
+		wantInter: nil,
+		wantDiff:  nil,
+		wantSub:   true,
+		wantEqual: true,
+	}, {
+		name:      "disjoint",
+		a:         []int{1, 3},
+		b:         []int{2, 4},
+		wantUnion: []int{1, 2, 3, 4},
+		wantInter: nil,
+		wantDiff:  []int{1, 3},
+		wantSub:   false,
+		wantEqual: false,
+	}, {
+		name:      "overlap",
+		a:         []int{1, 2, 3, 5},
+		b:         []int{3, 4, 5},
+		wantUnion: []int{1, 2, 3, 4, 5},
+		wantInter: []int{3, 5},
+		wantDiff:  []int{1, 2},
+		wantSub:   false,
+		wantEqual: false,
+	}, {
+		name:      "subset",
+		a:         []int{2, 3},
+		b:         []int{1, 2, 3},
+		wantUnion: []int{1, 2, 3},
+		wantInter: []int{2, 3},
+		wantDiff:  nil,
+		wantSub:   true,
+		wantEqual: false,
+	}, {
+		name:      "equal",
+		a:         []int{1, 2},
+		b:         []int{2, 1},
+		wantUnion: []int{1, 2},
+		wantInter: []int{1, 2},
+		wantDiff:  nil,
+		wantSub:   true,
+		wantEqual: true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := container.NewMapSet(tc.a...)
+			b := container.NewMapSet(tc.b...)
+
+			assert.ElementsMatch(t, tc.wantUnion, a.Union(b).Values())
+			assert.ElementsMatch(t, tc.wantInter, a.Intersection(b).Values())
+			assert.ElementsMatch(t, tc.wantDiff, a.Difference(b).Values())
+			assert.Equal(t, tc.wantSub, a.SubsetOf(b))
+			assert.Equal(t, tc.wantEqual, a.Equal(b))
+
+			inPlace := a.Clone()
+			inPlace.UnionInPlace(b)
+			assert.ElementsMatch(t, tc.wantUnion, inPlace.Values())
+
+			inPlace = a.Clone()
+			inPlace.IntersectionInPlace(b)
+			assert.ElementsMatch(t, tc.wantInter, inPlace.Values())
+
+			inPlace = a.Clone()
+			inPlace.DifferenceInPlace(b)
+			assert.ElementsMatch(t, tc.wantDiff, inPlace.Values())
+
+			// The operands must not change.
+			assert.ElementsMatch(t, tc.a, a.Values())
+			assert.ElementsMatch(t, tc.b, b.Values())
+		})
+	}
+}
+
+func TestMapSet_nil(t *testing.T) {
+	t.Parallel()
+
+	var set container.MapSet[string]
+
+	assert.False(t, set.Has("a"))
+	assert.Equal(t, 0, set.Len())
+	assert.Nil(t, set.Values())
+	assert.Nil(t, set.Clone())
+	assert.True(t, set.Equal(container.NewMapSet[string]()))
+	assert.NotPanics(t, func() { set.Delete("a") })
+	assert.NotPanics(t, set.Clear)
+	assert.Panics(t, func() { set.Add("a") })
+
+	b, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	assert.Equal(t, "null", string(b))
+}
+
+func TestMapSet_encoding(t *testing.T) {
+	t.Parallel()
+
+	t.Run("int", func(t *testing.T) {
+		set := container.NewMapSet(10, -1, 2)
+
+		text, err := set.MarshalText()
+		require.NoError(t, err)
+
+		assert.Equal(t, "-1,2,10", string(text))
+
+		var got container.MapSet[int]
+		err = got.UnmarshalText(text)
+		require.NoError(t, err)
+
+		assert.True(t, set.Equal(got))
+
+		b, err := json.Marshal(set)
+		require.NoError(t, err)
+
+		assert.Equal(t, "[-1,2,10]", string(b))
+	})
+
+	t.Run("text", func(t *testing.T) {
+		set := container.NewMapSet(
+			netip.MustParseAddr("192.0.2.2"),
+			netip.MustParseAddr("192.0.2.1"),
+		)
+
+		text, err := set.MarshalText()
+		require.NoError(t, err)
+
+		assert.Equal(t, "192.0.2.1,192.0.2.2", string(text))
+
+		b, err := json.Marshal(set)
+		require.NoError(t, err)
+
+		assert.Equal(t, `["192.0.2.1","192.0.2.2"]`, string(b))
+
+		var got container.MapSet[netip.Addr]
+		err = json.Unmarshal(b, &got)
+		require.NoError(t, err)
+
+		assert.True(t, set.Equal(got))
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		set := container.NewMapSet[any]("a", 2.5, 1, nil)
+
+		assert.Equal(t, "[<nil> 1 2.5 a]", set.String())
+
+		b, err := json.Marshal(set)
+		require.NoError(t, err)
+
+		assert.Equal(t, `[null,1,2.5,"a"]`, string(b))
+	})
+
+	t.Run("bad", func(t *testing.T) {
+		var set container.MapSet[int8]
+		err := set.UnmarshalText([]byte("1,300"))
+		testutil.AssertErrorMsg(
+			t,
+			`unmarshaling value "300": strconv.ParseInt: parsing "300": value out of range`,
+			err,
+		)
+
+		err = set.UnmarshalText(nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, set.Len())
+	})
+}
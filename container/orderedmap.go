@@ -0,0 +1,314 @@
+package container
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// OrderedMap is a map that preserves the order in which the keys have been
+// first added.  The zero value is an empty map ready to use.  It is not safe
+// for concurrent use.
+//
+// OrderedMap marshals to and from a JSON object with the keys in the insertion
+// order.  The keys must be of a string or integer kind or implement
+// encoding.TextMarshaler and encoding.TextUnmarshaler, like the keys of Go maps
+// in encoding/json.
+type OrderedMap[K comparable, V any] struct {
+	entries map[K]*orderedMapEntry[K, V]
+
+	// head and tail are the oldest and the newest entries.
+	head *orderedMapEntry[K, V]
+	tail *orderedMapEntry[K, V]
+}
+
+// orderedMapEntry is an entry of an *OrderedMap.
+type orderedMapEntry[K comparable, V any] struct {
+	prev *orderedMapEntry[K, V]
+	next *orderedMapEntry[K, V]
+	key  K
+	val  V
+}
+
+// NewOrderedMap returns a new empty *OrderedMap.
+func NewOrderedMap[K comparable, V any]() (m *OrderedMap[K, V]) {
+	return &OrderedMap[K, V]{}
+}
+
+// Get returns the value for the key.  Calling Get on a nil map returns the
+// zero value and false, just like indexing on a nil Go map does.
+func (m *OrderedMap[K, V]) Get(key K) (val V, ok bool) {
+	if m == nil {
+		return val, false
+	}
+
+	e, ok := m.entries[key]
+	if !ok {
+		return val, false
+	}
+
+	return e.val, true
+}
+
+// Set sets the value for the key.  If the key is already in the map, its
+// position is not changed.  Set panics if the map is nil, just like a nil Go
+// map does.
+func (m *OrderedMap[K, V]) Set(key K, val V) {
+	if e, ok := m.entries[key]; ok {
+		e.val = val
+
+		return
+	}
+
+	if m.entries == nil {
+		m.entries = map[K]*orderedMapEntry[K, V]{}
+	}
+
+	e := &orderedMapEntry[K, V]{
+		prev: m.tail,
+		key:  key,
+		val:  val,
+	}
+
+	if m.tail == nil {
+		m.head = e
+	} else {
+		m.tail.next = e
+	}
+
+	m.tail = e
+	m.entries[key] = e
+}
+
+// Delete removes the key from the map and returns true if it has been there.
+// Calling Delete on a nil map has no effect.
+func (m *OrderedMap[K, V]) Delete(key K) (ok bool) {
+	if m == nil {
+		return false
+	}
+
+	e, ok := m.entries[key]
+	if !ok {
+		return false
+	}
+
+	if e.prev == nil {
+		m.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+
+	if e.next == nil {
+		m.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+
+	delete(m.entries, key)
+
+	return true
+}
+
+// Len returns the number of entries in the map.  A nil map has a length of
+// zero.
+func (m *OrderedMap[K, V]) Len() (n int) {
+	if m == nil {
+		return 0
+	}
+
+	return len(m.entries)
+}
+
+// Clear removes all entries from the map.  Calling Clear on a nil map has no
+// effect.
+func (m *OrderedMap[K, V]) Clear() {
+	if m == nil {
+		return
+	}
+
+	*m = OrderedMap[K, V]{}
+}
+
+// Range calls f with each key and value in the insertion order.  If cont is
+// false, Range stops the iteration.  f must not add or remove entries.
+// Calling Range on a nil map has no effect.
+func (m *OrderedMap[K, V]) Range(f func(key K, val V) (cont bool)) {
+	if m == nil {
+		return
+	}
+
+	for e := m.head; e != nil; e = e.next {
+		if !f(e.key, e.val) {
+			return
+		}
+	}
+}
+
+// Keys returns the keys of the map in the insertion order.  It returns nil if
+// the map is nil.
+func (m *OrderedMap[K, V]) Keys() (keys []K) {
+	if m == nil {
+		return nil
+	}
+
+	keys = make([]K, 0, len(m.entries))
+	for e := m.head; e != nil; e = e.next {
+		keys = append(keys, e.key)
+	}
+
+	return keys
+}
+
+// type check
+var _ json.Marshaler = (*OrderedMap[string, int])(nil)
+
+// MarshalJSON implements the json.Marshaler interface for *OrderedMap.  A nil
+// map is marshaled as null.
+func (m *OrderedMap[K, V]) MarshalJSON() (b []byte, err error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for e := m.head; e != nil; e = e.next {
+		if e != m.head {
+			buf.WriteByte(',')
+		}
+
+		var ks string
+		ks, err = marshalKey(e.key)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling key %v: %w", e.key, err)
+		}
+
+		// Marshaling a string never fails.
+		kb, _ := json.Marshal(ks)
+		buf.Write(kb)
+		buf.WriteByte(':')
+
+		var vb []byte
+		vb, err = json.Marshal(e.val)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling value for key %q: %w", ks, err)
+		}
+
+		buf.Write(vb)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// type check
+var _ json.Unmarshaler = (*OrderedMap[string, int])(nil)
+
+// UnmarshalJSON implements the json.Unmarshaler interface for *OrderedMap.  The
+// entries are added to the map in the order of the JSON object.  JSON null is
+// a no-op.
+func (m *OrderedMap[K, V]) UnmarshalJSON(b []byte) (err error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	} else if tok == nil {
+		return nil
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("unexpected token %v, want an object", tok)
+	}
+
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+
+		// Object keys are always strings.
+		ks := tok.(string)
+
+		var key K
+		err = unmarshalKey(ks, &key)
+		if err != nil {
+			return fmt.Errorf("unmarshaling key %q: %w", ks, err)
+		}
+
+		var val V
+		err = dec.Decode(&val)
+		if err != nil {
+			return fmt.Errorf("unmarshaling value for key %q: %w", ks, err)
+		}
+
+		m.Set(key, val)
+	}
+
+	// Consume the closing brace.
+	_, err = dec.Token()
+
+	return err
+}
+
+// errBadKeyType is returned when the type of an OrderedMap key isn't supported
+// for JSON encoding.
+const errBadKeyType errors.Error = "unsupported key type"
+
+// marshalKey returns the JSON object key for key.
+func marshalKey(key any) (s string, err error) {
+	if tm, ok := key.(encoding.TextMarshaler); ok {
+		var b []byte
+		b, err = tm.MarshalText()
+
+		return string(b), err
+	}
+
+	v := reflect.ValueOf(key)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	default:
+		return "", fmt.Errorf("%w %T", errBadKeyType, key)
+	}
+}
+
+// unmarshalKey parses the JSON object key s into key.
+func unmarshalKey(s string, key any) (err error) {
+	if tu, ok := key.(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(s))
+	}
+
+	v := reflect.ValueOf(key).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		n, err = strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(n)
+	default:
+		return fmt.Errorf("%w %s", errBadKeyType, v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package container_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/container"
+)
+
+func ExampleOrderedMap() {
+	m := container.NewOrderedMap[string, int]()
+	m.Set("c", 3)
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("a", 10)
+
+	v, ok := m.Get("a")
+	fmt.Println(v, ok)
+
+	m.Delete("c")
+	m.Set("c", 30)
+
+	m.Range(func(k string, v int) (cont bool) {
+		fmt.Println(k, v)
+
+		return true
+	})
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(string(b))
+
+	// Output:
+	//
+	// 10 true
+	// a 10
+	// b 2
+	// c 30
+	// {"a":10,"b":2,"c":30}
+}
@@ -0,0 +1,149 @@
+package container_test
+
+import (
+	"encoding/json"
+	"net/netip"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedMap_nil(t *testing.T) {
+	t.Parallel()
+
+	var m *container.OrderedMap[string, int]
+
+	_, ok := m.Get("a")
+	assert.False(t, ok)
+	assert.False(t, m.Delete("a"))
+	assert.Equal(t, 0, m.Len())
+	assert.Nil(t, m.Keys())
+	assert.NotPanics(t, m.Clear)
+	assert.Panics(t, func() { m.Set("a", 1) })
+
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	assert.Equal(t, "null", string(b))
+}
+
+func TestOrderedMap_Delete(t *testing.T) {
+	t.Parallel()
+
+	m := &container.OrderedMap[int, int]{}
+	for i := 0; i < 5; i++ {
+		m.Set(i, i)
+	}
+
+	assert.True(t, m.Delete(0))
+	assert.True(t, m.Delete(2))
+	assert.True(t, m.Delete(4))
+	assert.False(t, m.Delete(4))
+	assert.Equal(t, []int{1, 3}, m.Keys())
+
+	m.Set(0, 0)
+	assert.Equal(t, []int{1, 3, 0}, m.Keys())
+	assert.Equal(t, 3, m.Len())
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len())
+	assert.Empty(t, m.Keys())
+}
+
+func TestOrderedMap_json(t *testing.T) {
+	t.Parallel()
+
+	t.Run("string", func(t *testing.T) {
+		const data = `{"z":{"b":2,"a":1},"y":null,"x":{}}`
+
+		m := container.NewOrderedMap[string, *container.OrderedMap[string, int]]()
+		err := json.Unmarshal([]byte(data), m)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"z", "y", "x"}, m.Keys())
+
+		b, err := json.Marshal(m)
+		require.NoError(t, err)
+
+		assert.Equal(t, data, string(b))
+	})
+
+	t.Run("int", func(t *testing.T) {
+		const data = `{"3":"c","-1":"a"}`
+
+		m := container.NewOrderedMap[int8, string]()
+		err := json.Unmarshal([]byte(data), m)
+		require.NoError(t, err)
+
+		assert.Equal(t, []int8{3, -1}, m.Keys())
+
+		b, err := json.Marshal(m)
+		require.NoError(t, err)
+
+		assert.Equal(t, data, string(b))
+	})
+
+	t.Run("text", func(t *testing.T) {
+		const data = `{"192.0.2.2":true,"192.0.2.1":false}`
+
+		m := container.NewOrderedMap[netip.Addr, bool]()
+		err := json.Unmarshal([]byte(data), m)
+		require.NoError(t, err)
+
+		assert.Equal(t, []netip.Addr{
+			netip.MustParseAddr("192.0.2.2"),
+			netip.MustParseAddr("192.0.2.1"),
+		}, m.Keys())
+
+		b, err := json.Marshal(m)
+		require.NoError(t, err)
+
+		assert.Equal(t, data, string(b))
+	})
+}
+
+func TestOrderedMap_UnmarshalJSON_errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		in      string
+		wantErr string
+	}{{
+		name:    "not_object",
+		in:      `[1]`,
+		wantErr: "unexpected token [, want an object",
+	}, {
+		name:    "bad_key",
+		in:      `{"300":1}`,
+		wantErr: `unmarshaling key "300": strconv.ParseInt: parsing "300": value out of range`,
+	}, {
+		name:    "bad_value",
+		in:      `{"1":"x"}`,
+		wantErr: `unmarshaling value for key "1": json: cannot unmarshal string into Go value of type int`,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m := container.NewOrderedMap[int8, int]()
+			err := m.UnmarshalJSON([]byte(tc.in))
+			testutil.AssertErrorMsg(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestOrderedMap_MarshalJSON_badKey(t *testing.T) {
+	t.Parallel()
+
+	m := container.NewOrderedMap[float64, int]()
+	m.Set(1.5, 1)
+
+	_, err := m.MarshalJSON()
+	testutil.AssertErrorMsg(t, "marshaling key 1.5: unsupported key type float64", err)
+}
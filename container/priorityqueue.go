@@ -0,0 +1,148 @@
+package container
+
+import (
+	"container/heap"
+)
+
+// PriorityQueueItem is a handle of a value in a *PriorityQueue.  It allows
+// changing the priority of the value or removing it from the queue.
+type PriorityQueueItem[T any] struct {
+	// Value is the value in the queue.  Call PriorityQueue.Fix after changing
+	// it in a way that changes its priority.
+	Value T
+
+	// index is the index of the item in the heap or -1 if the item is not in
+	// a queue.
+	index int
+}
+
+// PriorityQueue is a priority queue based on a binary heap.  The value with the
+// highest priority, which is the least value according to the comparison
+// function, is popped first.  It is not safe for concurrent use.
+type PriorityQueue[T any] struct {
+	heap *priorityHeap[T]
+}
+
+// NewPriorityQueue returns a new empty *PriorityQueue.  less must return true
+// if a has a higher priority than b.
+func NewPriorityQueue[T any](less func(a, b T) (ok bool)) (q *PriorityQueue[T]) {
+	return &PriorityQueue[T]{
+		heap: &priorityHeap[T]{
+			less: less,
+		},
+	}
+}
+
+// Push adds v to the queue and returns its handle.
+func (q *PriorityQueue[T]) Push(v T) (item *PriorityQueueItem[T]) {
+	item = &PriorityQueueItem[T]{
+		Value: v,
+	}
+
+	heap.Push(q.heap, item)
+
+	return item
+}
+
+// Pop removes and returns the value with the highest priority.  ok is false if
+// the queue is empty.
+func (q *PriorityQueue[T]) Pop() (v T, ok bool) {
+	if q.Len() == 0 {
+		return v, false
+	}
+
+	item := heap.Pop(q.heap).(*PriorityQueueItem[T])
+
+	return item.Value, true
+}
+
+// Peek returns the value with the highest priority without removing it.  ok is
+// false if the queue is empty.
+func (q *PriorityQueue[T]) Peek() (v T, ok bool) {
+	if q.Len() == 0 {
+		return v, false
+	}
+
+	return q.heap.items[0].Value, true
+}
+
+// Fix restores the order of the queue after the value of item has been changed.
+// It returns false if item is not in the queue.
+func (q *PriorityQueue[T]) Fix(item *PriorityQueueItem[T]) (ok bool) {
+	if !q.contains(item) {
+		return false
+	}
+
+	heap.Fix(q.heap, item.index)
+
+	return true
+}
+
+// Remove removes item from the queue.  It returns false if item is not in the
+// queue.
+func (q *PriorityQueue[T]) Remove(item *PriorityQueueItem[T]) (ok bool) {
+	if !q.contains(item) {
+		return false
+	}
+
+	heap.Remove(q.heap, item.index)
+
+	return true
+}
+
+// contains returns true if item is in q.
+func (q *PriorityQueue[T]) contains(item *PriorityQueueItem[T]) (ok bool) {
+	items := q.heap.items
+
+	return item.index >= 0 && item.index < len(items) && items[item.index] == item
+}
+
+// Len returns the number of values in the queue.
+func (q *PriorityQueue[T]) Len() (n int) {
+	return len(q.heap.items)
+}
+
+// priorityHeap is the heap.Interface implementation for *PriorityQueue.
+type priorityHeap[T any] struct {
+	less  func(a, b T) (ok bool)
+	items []*PriorityQueueItem[T]
+}
+
+// type check
+var _ heap.Interface = (*priorityHeap[int])(nil)
+
+// Len implements the heap.Interface interface for *priorityHeap.
+func (h *priorityHeap[T]) Len() (n int) {
+	return len(h.items)
+}
+
+// Less implements the heap.Interface interface for *priorityHeap.
+func (h *priorityHeap[T]) Less(i, j int) (ok bool) {
+	return h.less(h.items[i].Value, h.items[j].Value)
+}
+
+// Swap implements the heap.Interface interface for *priorityHeap.
+func (h *priorityHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+// Push implements the heap.Interface interface for *priorityHeap.  x must be a
+// *PriorityQueueItem[T].
+func (h *priorityHeap[T]) Push(x any) {
+	item := x.(*PriorityQueueItem[T])
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+// Pop implements the heap.Interface interface for *priorityHeap.
+func (h *priorityHeap[T]) Pop() (x any) {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items[n-1] = nil
+	h.items = h.items[:n-1]
+	item.index = -1
+
+	return item
+}
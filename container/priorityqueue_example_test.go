@@ -0,0 +1,44 @@
+package container_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AdguardTeam/golibs/container"
+)
+
+func ExamplePriorityQueue() {
+	type job struct {
+		name string
+		next time.Duration
+	}
+
+	q := container.NewPriorityQueue(func(a, b job) (ok bool) {
+		return a.next < b.next
+	})
+
+	q.Push(job{name: "filters", next: 2 * time.Hour})
+	q.Push(job{name: "stats", next: time.Minute})
+	upstreams := q.Push(job{name: "upstreams", next: time.Hour})
+	tmp := q.Push(job{name: "tmp", next: 0})
+
+	j, _ := q.Peek()
+	fmt.Println("next:", j.name)
+
+	q.Remove(tmp)
+
+	upstreams.Value.next = time.Second
+	q.Fix(upstreams)
+
+	for q.Len() > 0 {
+		j, _ = q.Pop()
+		fmt.Println(j.name, j.next)
+	}
+
+	// Output:
+	//
+	// next: tmp
+	// upstreams 1s
+	// stats 1m0s
+	// filters 2h0m0s
+}
@@ -0,0 +1,60 @@
+package container_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	t.Parallel()
+
+	q := container.NewPriorityQueue(func(a, b int) (ok bool) { return a < b })
+
+	_, ok := q.Pop()
+	assert.False(t, ok)
+
+	_, ok = q.Peek()
+	assert.False(t, ok)
+
+	r := rand.New(rand.NewSource(1))
+	want := make([]int, 0, 100)
+	items := make([]*container.PriorityQueueItem[int], 0, 100)
+	for i := 0; i < 100; i++ {
+		v := r.Intn(1000)
+		want = append(want, v)
+		items = append(items, q.Push(v))
+	}
+
+	// Remove every tenth value and change every fifth one.
+	for i := len(items) - 1; i >= 0; i-- {
+		switch {
+		case i%10 == 0:
+			assert.True(t, q.Remove(items[i]))
+			assert.False(t, q.Remove(items[i]))
+			assert.False(t, q.Fix(items[i]))
+			want = append(want[:i], want[i+1:]...)
+		case i%5 == 0:
+			items[i].Value = -items[i].Value
+			assert.True(t, q.Fix(items[i]))
+			want[i] = -want[i]
+		}
+	}
+
+	sort.Ints(want)
+	assert.Equal(t, len(want), q.Len())
+
+	got := make([]int, 0, len(want))
+	for q.Len() > 0 {
+		peeked, _ := q.Peek()
+		v, _ := q.Pop()
+		assert.Equal(t, peeked, v)
+
+		got = append(got, v)
+	}
+
+	assert.Equal(t, want, got)
+}
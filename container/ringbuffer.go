@@ -0,0 +1,101 @@
+package container
+
+// RingBuffer is a fixed-capacity buffer that keeps the most recently pushed
+// elements, overwriting the oldest ones when it is full, so its memory usage
+// never grows after creation.  Use NewRingBuffer to create one, the zero value
+// is not usable.  It is not safe for concurrent use.
+type RingBuffer[T any] struct {
+	buf []T
+
+	// cur is the index at which the next element is written.
+	cur int
+
+	// full is true if the buffer has been filled at least once.
+	full bool
+}
+
+// NewRingBuffer returns a new empty *RingBuffer with the given capacity.  size
+// must be greater than zero.
+func NewRingBuffer[T any](size int) (rb *RingBuffer[T]) {
+	if size <= 0 {
+		panic("container: non-positive ring buffer size")
+	}
+
+	return &RingBuffer[T]{
+		buf: make([]T, size),
+	}
+}
+
+// Push adds an element to the buffer, overwriting the oldest one if the buffer
+// is full.
+func (rb *RingBuffer[T]) Push(e T) {
+	rb.buf[rb.cur] = e
+	rb.cur = (rb.cur + 1) % len(rb.buf)
+	if rb.cur == 0 {
+		rb.full = true
+	}
+}
+
+// Range calls f with each element from the oldest to the newest one.  If cont
+// is false, Range stops the iteration.
+func (rb *RingBuffer[T]) Range(f func(e T) (cont bool)) {
+	before, after := rb.parts()
+	for _, e := range before {
+		if !f(e) {
+			return
+		}
+	}
+
+	for _, e := range after {
+		if !f(e) {
+			return
+		}
+	}
+}
+
+// ReverseRange calls f with each element from the newest to the oldest one.  If
+// cont is false, ReverseRange stops the iteration.
+func (rb *RingBuffer[T]) ReverseRange(f func(e T) (cont bool)) {
+	before, after := rb.parts()
+	for i := len(after) - 1; i >= 0; i-- {
+		if !f(after[i]) {
+			return
+		}
+	}
+
+	for i := len(before) - 1; i >= 0; i-- {
+		if !f(before[i]) {
+			return
+		}
+	}
+}
+
+// parts returns the older and the newer parts of the buffer.
+func (rb *RingBuffer[T]) parts() (older, newer []T) {
+	if !rb.full {
+		return nil, rb.buf[:rb.cur]
+	}
+
+	return rb.buf[rb.cur:], rb.buf[:rb.cur]
+}
+
+// Len returns the number of elements in the buffer.
+func (rb *RingBuffer[T]) Len() (n int) {
+	if rb.full {
+		return len(rb.buf)
+	}
+
+	return rb.cur
+}
+
+// Cap returns the capacity of the buffer.
+func (rb *RingBuffer[T]) Cap() (n int) {
+	return len(rb.buf)
+}
+
+// Clear removes all elements from the buffer.
+func (rb *RingBuffer[T]) Clear() {
+	clear(rb.buf)
+	rb.cur = 0
+	rb.full = false
+}
@@ -0,0 +1,50 @@
+package container_test
+
+import (
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/container"
+)
+
+func ExampleRingBuffer() {
+	rb := container.NewRingBuffer[int](3)
+
+	printAll := func() {
+		var vals []int
+		rb.Range(func(v int) (cont bool) {
+			vals = append(vals, v)
+
+			return true
+		})
+
+		fmt.Println(vals, rb.Len())
+	}
+
+	printAll()
+
+	rb.Push(1)
+	rb.Push(2)
+	printAll()
+
+	rb.Push(3)
+	rb.Push(4)
+	printAll()
+
+	rb.ReverseRange(func(v int) (cont bool) {
+		fmt.Println(v)
+
+		return v != 3
+	})
+
+	rb.Clear()
+	printAll()
+
+	// Output:
+	//
+	// [] 0
+	// [1 2] 2
+	// [2 3 4] 3
+	// 4
+	// 3
+	// [] 0
+}
@@ -0,0 +1,48 @@
+package container_test
+
+import (
+	"testing"
+
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	const size = 4
+
+	rb := container.NewRingBuffer[int](size)
+	assert.Equal(t, size, rb.Cap())
+
+	for n := 0; n <= 2*size+1; n++ {
+		if n > 0 {
+			rb.Push(n)
+		}
+
+		want := make([]int, 0, size)
+		for i := max(1, n-size+1); i <= n; i++ {
+			want = append(want, i)
+		}
+
+		got := []int{}
+		rb.Range(func(e int) (cont bool) {
+			got = append(got, e)
+
+			return true
+		})
+
+		gotRev := []int{}
+		rb.ReverseRange(func(e int) (cont bool) {
+			gotRev = append([]int{e}, gotRev...)
+
+			return true
+		})
+
+		assert.Equal(t, want, got, "n=%d", n)
+		assert.Equal(t, want, gotRev, "n=%d", n)
+		assert.Equal(t, len(want), rb.Len())
+	}
+
+	assert.Panics(t, func() { container.NewRingBuffer[int](0) })
+}
@@ -0,0 +1,202 @@
+package container
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// SortedSliceSet is a set of values stored in a sorted slice.  It uses less
+// memory than a map-based set and is well suited for large sets that are
+// mostly read, since Has takes O(log n) time while Add and Delete take O(n)
+// time.  A nil *SortedSliceSet is an empty set for all methods except Add.
+// It is not safe for concurrent use.
+type SortedSliceSet[T cmp.Ordered] struct {
+	elems []T
+}
+
+// NewSortedSliceSet returns a new *SortedSliceSet containing elems, which may be
+// unsorted and may contain duplicates.  elems are copied.
+func NewSortedSliceSet[T cmp.Ordered](elems ...T) (set *SortedSliceSet[T]) {
+	elems = slices.Clone(elems)
+	slices.Sort(elems)
+
+	return &SortedSliceSet[T]{
+		elems: slices.Clip(slices.Compact(elems)),
+	}
+}
+
+// Add adds e to the set.  Add panics if the set is nil, just like a nil map
+// does.
+func (set *SortedSliceSet[T]) Add(e T) {
+	i, ok := slices.BinarySearch(set.elems, e)
+	if !ok {
+		set.elems = slices.Insert(set.elems, i, e)
+	}
+}
+
+// Delete removes e from the set.  Calling Delete on a nil set has no effect.
+func (set *SortedSliceSet[T]) Delete(e T) {
+	if set == nil {
+		return
+	}
+
+	i, ok := slices.BinarySearch(set.elems, e)
+	if ok {
+		set.elems = slices.Delete(set.elems, i, i+1)
+	}
+}
+
+// Has returns true if e is in the set.
+func (set *SortedSliceSet[T]) Has(e T) (ok bool) {
+	if set == nil {
+		return false
+	}
+
+	_, ok = slices.BinarySearch(set.elems, e)
+
+	return ok
+}
+
+// Len returns the number of elements in the set.
+func (set *SortedSliceSet[T]) Len() (n int) {
+	if set == nil {
+		return 0
+	}
+
+	return len(set.elems)
+}
+
+// Range calls f with each element of the set in the ascending order.  If cont
+// is false, Range stops the iteration.
+func (set *SortedSliceSet[T]) Range(f func(e T) (cont bool)) {
+	if set == nil {
+		return
+	}
+
+	for _, e := range set.elems {
+		if !f(e) {
+			return
+		}
+	}
+}
+
+// Values returns a copy of the elements of the set in the ascending order.  It
+// returns nil if the set is nil.
+func (set *SortedSliceSet[T]) Values() (elems []T) {
+	if set == nil {
+		return nil
+	}
+
+	return slices.Clone(set.elems)
+}
+
+// Clone returns a deep copy of the set.  Clone returns nil if the set is nil.
+func (set *SortedSliceSet[T]) Clone() (clone *SortedSliceSet[T]) {
+	if set == nil {
+		return nil
+	}
+
+	return &SortedSliceSet[T]{
+		elems: slices.Clone(set.elems),
+	}
+}
+
+// Equal returns true if set and other contain the same elements.  A nil set is
+// only equal to another nil set.
+func (set *SortedSliceSet[T]) Equal(other *SortedSliceSet[T]) (ok bool) {
+	if set == nil || other == nil {
+		return set == other
+	}
+
+	return slices.Equal(set.elems, other.elems)
+}
+
+// SubsetOf returns true if all elements of set are in other.
+func (set *SortedSliceSet[T]) SubsetOf(other *SortedSliceSet[T]) (ok bool) {
+	return len(set.Difference(other).elems) == 0
+}
+
+// Union returns a new set containing the elements of both set and other.
+func (set *SortedSliceSet[T]) Union(other *SortedSliceSet[T]) (res *SortedSliceSet[T]) {
+	a, b := set.values(), other.values()
+	elems := make([]T, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		switch c := cmp.Compare(a[0], b[0]); {
+		case c < 0:
+			elems, a = append(elems, a[0]), a[1:]
+		case c > 0:
+			elems, b = append(elems, b[0]), b[1:]
+		default:
+			elems, a, b = append(elems, a[0]), a[1:], b[1:]
+		}
+	}
+
+	elems = append(elems, a...)
+	elems = append(elems, b...)
+
+	return &SortedSliceSet[T]{
+		elems: slices.Clip(elems),
+	}
+}
+
+// Intersection returns a new set containing the elements that are in both set
+// and other.
+func (set *SortedSliceSet[T]) Intersection(other *SortedSliceSet[T]) (res *SortedSliceSet[T]) {
+	a, b := set.values(), other.values()
+	var elems []T
+	for len(a) > 0 && len(b) > 0 {
+		switch c := cmp.Compare(a[0], b[0]); {
+		case c < 0:
+			a = a[1:]
+		case c > 0:
+			b = b[1:]
+		default:
+			elems, a, b = append(elems, a[0]), a[1:], b[1:]
+		}
+	}
+
+	return &SortedSliceSet[T]{
+		elems: elems,
+	}
+}
+
+// Difference returns a new set containing the elements of set that are not in
+// other.
+func (set *SortedSliceSet[T]) Difference(other *SortedSliceSet[T]) (res *SortedSliceSet[T]) {
+	a, b := set.values(), other.values()
+	var elems []T
+	for len(a) > 0 && len(b) > 0 {
+		switch c := cmp.Compare(a[0], b[0]); {
+		case c < 0:
+			elems, a = append(elems, a[0]), a[1:]
+		case c > 0:
+			b = b[1:]
+		default:
+			a, b = a[1:], b[1:]
+		}
+	}
+
+	elems = append(elems, a...)
+
+	return &SortedSliceSet[T]{
+		elems: elems,
+	}
+}
+
+// values returns the underlying slice of the set or nil if the set is nil.
+func (set *SortedSliceSet[T]) values() (elems []T) {
+	if set == nil {
+		return nil
+	}
+
+	return set.elems
+}
+
+// type check
+var _ fmt.Stringer = (*SortedSliceSet[int])(nil)
+
+// String implements the fmt.Stringer interface for *SortedSliceSet.
+func (set *SortedSliceSet[T]) String() (s string) {
+	return fmt.Sprint(set.values())
+}
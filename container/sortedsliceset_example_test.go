@@ -0,0 +1,32 @@
+package container_test
+
+import (
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/container"
+)
+
+func ExampleSortedSliceSet() {
+	set := container.NewSortedSliceSet("c", "a", "b", "a")
+	fmt.Println(set, set.Len())
+
+	fmt.Println(set.Has("a"), set.Has("d"))
+
+	set.Add("d")
+	set.Delete("a")
+	fmt.Println(set)
+
+	other := container.NewSortedSliceSet("a", "b", "e")
+	fmt.Println(set.Union(other))
+	fmt.Println(set.Intersection(other))
+	fmt.Println(set.Difference(other))
+
+	// Output:
+	//
+	// [a b c] 3
+	// true false
+	// [b c d]
+	// [a b c d e]
+	// [b]
+	// [c d]
+}
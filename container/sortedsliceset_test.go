@@ -0,0 +1,108 @@
+package container_test
+
+import (
+	"testing"
+
+	"github.com/AdguardTeam/golibs/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortedSliceSet_nil(t *testing.T) {
+	t.Parallel()
+
+	var set *container.SortedSliceSet[int]
+
+	assert.False(t, set.Has(1))
+	assert.Equal(t, 0, set.Len())
+	assert.Nil(t, set.Values())
+	assert.Nil(t, set.Clone())
+	assert.True(t, set.Equal(nil))
+	assert.True(t, set.SubsetOf(nil))
+	assert.Equal(t, "[]", set.String())
+	assert.NotPanics(t, func() { set.Delete(1) })
+	assert.Panics(t, func() { set.Add(1) })
+
+	other := container.NewSortedSliceSet(1, 2)
+	assert.False(t, set.Equal(other))
+	assert.True(t, set.SubsetOf(other))
+	assert.Equal(t, []int{1, 2}, set.Union(other).Values())
+	assert.Equal(t, 0, set.Intersection(other).Len())
+	assert.Equal(t, 0, set.Difference(other).Len())
+	assert.Equal(t, []int{1, 2}, other.Difference(set).Values())
+}
+
+func TestSortedSliceSet_algebra(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		a         []int
+		b         []int
+		wantUnion []int
+		wantInter []int
+		wantDiff  []int
+		wantSub   bool
+	}{{
+		name:      "empty",
+		a:         nil,
+		b:         nil,
+		wantUnion: []int{},
+		wantInter: nil,
+		wantDiff:  nil,
+		wantSub:   true,
+	}, {
+		name:      "disjoint",
+		a:         []int{1, 3},
+		b:         []int{2, 4},
+		wantUnion: []int{1, 2, 3, 4},
+		wantInter: nil,
+		wantDiff:  []int{1, 3},
+		wantSub:   false,
+	}, {
+		name:      "overlap",
+		a:         []int{5, 1, 2, 3},
+		b:         []int{3, 4, 5},
+		wantUnion: []int{1, 2, 3, 4, 5},
+		wantInter: []int{3, 5},
+		wantDiff:  []int{1, 2},
+		wantSub:   false,
+	}, {
+		name:      "subset",
+		a:         []int{2, 3},
+		b:         []int{1, 2, 3},
+		wantUnion: []int{1, 2, 3},
+		wantInter: []int{2, 3},
+		wantDiff:  nil,
+		wantSub:   true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := container.NewSortedSliceSet(tc.a...)
+			b := container.NewSortedSliceSet(tc.b...)
+
+			assert.Equal(t, tc.wantUnion, a.Union(b).Values())
+			assert.Equal(t, tc.wantInter, a.Intersection(b).Values())
+			assert.Equal(t, tc.wantDiff, a.Difference(b).Values())
+			assert.Equal(t, tc.wantSub, a.SubsetOf(b))
+		})
+	}
+}
+
+func TestSortedSliceSet_Clone(t *testing.T) {
+	t.Parallel()
+
+	elems := []int{3, 1, 2}
+	set := container.NewSortedSliceSet(elems...)
+	assert.Equal(t, []int{3, 1, 2}, elems)
+
+	clone := set.Clone()
+	assert.True(t, set.Equal(clone))
+
+	clone.Add(4)
+	assert.False(t, set.Equal(clone))
+	assert.False(t, set.Has(4))
+}
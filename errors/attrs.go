@@ -0,0 +1,85 @@
+package errors
+
+import (
+	"log/slog"
+)
+
+// attrError is an error with structured attributes attached to it.
+type attrError struct {
+	err   error
+	attrs []slog.Attr
+}
+
+// type check
+var _ slog.LogValuer = (*attrError)(nil)
+
+// WithAttrs returns err with attrs attached to it, unless err is nil or attrs
+// are empty, in which case it returns err as is.  The attributes can be
+// retrieved using Attrs.
+//
+// The result implements slog.LogValuer, so that logging it with a structured
+// logger adds the message of the error as well as all attributes from its
+// chain.  Since the errors wrapping the result, for example the ones returned
+// by Annotate, don't implement slog.LogValuer, use slogutil.ErrorAttr to log
+// errors that might have been wrapped:
+//
+//   err = errors.WithAttrs(err, slog.String("upstream", addr))
+//
+//   // …
+//
+//   logger.Error("exchanging", slogutil.ErrorAttr(err))
+func WithAttrs(err error, attrs ...slog.Attr) (withAttrs error) {
+	if err == nil || len(attrs) == 0 {
+		return err
+	}
+
+	return &attrError{
+		err:   err,
+		attrs: attrs,
+	}
+}
+
+// Attrs returns the attributes attached to err and to all errors in its chain
+// using WithAttrs, starting with the outermost ones.  If there are none, Attrs
+// returns nil.
+func Attrs(err error) (attrs []slog.Attr) {
+	for err != nil {
+		switch e := err.(type) {
+		case *attrError:
+			attrs = append(attrs, e.attrs...)
+		case WrapperSlice:
+			for _, inner := range e.Unwrap() {
+				attrs = append(attrs, Attrs(inner)...)
+			}
+
+			return attrs
+		}
+
+		err = Unwrap(err)
+	}
+
+	return attrs
+}
+
+// Error implements the error interface for *attrError.
+func (err *attrError) Error() (msg string) {
+	return err.err.Error()
+}
+
+// Unwrap implements the Wrapper interface for *attrError.
+func (err *attrError) Unwrap() (unwrapped error) {
+	return err.err
+}
+
+// LogValue implements the slog.LogValuer interface for *attrError.  It returns
+// a group containing the message of the error with the key "msg" as well as
+// all attributes from the chain of err.
+func (err *attrError) LogValue() (v slog.Value) {
+	attrs := Attrs(err)
+
+	groupAttrs := make([]slog.Attr, 0, len(attrs)+1)
+	groupAttrs = append(groupAttrs, slog.String("msg", err.Error()))
+	groupAttrs = append(groupAttrs, attrs...)
+
+	return slog.GroupValue(groupAttrs...)
+}
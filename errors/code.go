@@ -0,0 +1,298 @@
+package errors
+
+import "sync"
+
+// Code is a machine-readable code of a domain error, which is usually mapped
+// to transport-level statuses, such as HTTP status codes and DNS response
+// codes.  See CodeRegistry.
+type Code string
+
+// Common codes.  All of them are registered in the default code registry.
+const (
+	// CodeUnknown is returned from CodeOf for non-nil errors that have no
+	// code.
+	CodeUnknown Code = "unknown"
+
+	// CodeInvalidArgument means that the input is invalid.
+	CodeInvalidArgument Code = "invalid_argument"
+
+	// CodeNotFound means that the requested entity does not exist.
+	CodeNotFound Code = "not_found"
+
+	// CodeAlreadyExists means that the entity being created already exists.
+	CodeAlreadyExists Code = "already_exists"
+
+	// CodePermissionDenied means that the caller is not allowed to perform the
+	// operation.
+	CodePermissionDenied Code = "permission_denied"
+
+	// CodeUnavailable means that the service or one of its dependencies is
+	// currently unavailable.
+	CodeUnavailable Code = "unavailable"
+
+	// CodeTimeout means that the operation has not been finished in time.
+	CodeTimeout Code = "timeout"
+
+	// CodeNotImplemented means that the operation is not supported.
+	CodeNotImplemented Code = "not_implemented"
+
+	// CodeInternal means that an internal invariant has been broken.
+	CodeInternal Code = "internal"
+)
+
+// DNS response codes used in the default code registry.  They are defined here
+// to avoid depending on a DNS library.
+//
+// See RFC 1035 Section 4.1.1.
+const (
+	RCodeSuccess        = 0
+	RCodeFormatError    = 1
+	RCodeServerFailure  = 2
+	RCodeNameError      = 3
+	RCodeNotImplemented = 4
+	RCodeRefused        = 5
+)
+
+// HTTP status codes used in the default code registry.  They are defined here to
+// avoid depending on package net/http, which is large.
+const (
+	httpStatusOK                  = 200
+	httpStatusBadRequest          = 400
+	httpStatusForbidden           = 403
+	httpStatusNotFound            = 404
+	httpStatusConflict            = 409
+	httpStatusInternalServerError = 500
+	httpStatusNotImplemented      = 501
+	httpStatusServiceUnavailable  = 503
+	httpStatusGatewayTimeout      = 504
+)
+
+// CodeInfo is the transport-level information about a Code.
+type CodeInfo struct {
+	// HTTPStatus is the HTTP status code for the errors with this code.
+	HTTPStatus int
+
+	// RCode is the DNS response code for the errors with this code.
+	RCode int
+}
+
+// Coder is the interface for errors that contain their own code.
+type Coder interface {
+	error
+	Code() (c Code)
+}
+
+// codeError is an error with a code.
+type codeError struct {
+	err  error
+	code Code
+}
+
+// type check
+var _ Coder = (*codeError)(nil)
+
+// WithCode returns err with the code c attached to it, unless err is nil.  The
+// result implements Coder.
+func WithCode(err error, c Code) (withCode error) {
+	if err == nil {
+		return nil
+	}
+
+	return &codeError{
+		err:  err,
+		code: c,
+	}
+}
+
+// Error implements the error interface for *codeError.
+func (err *codeError) Error() (msg string) {
+	return err.err.Error()
+}
+
+// Unwrap implements the Wrapper interface for *codeError.
+func (err *codeError) Unwrap() (unwrapped error) {
+	return err.err
+}
+
+// Code implements the Coder interface for *codeError.
+func (err *codeError) Code() (c Code) {
+	return err.code
+}
+
+// codedSentinel is a sentinel error with a code.
+type codedSentinel struct {
+	target error
+	code   Code
+}
+
+// CodeRegistry maps codes to their transport-level information and sentinel
+// errors to codes.  It is safe for concurrent use.
+type CodeRegistry struct {
+	mu        *sync.RWMutex
+	infos     map[Code]CodeInfo
+	sentinels []codedSentinel
+}
+
+// NewCodeRegistry returns a new properly initialized *CodeRegistry containing
+// all common codes, such as CodeNotFound.
+func NewCodeRegistry() (r *CodeRegistry) {
+	return &CodeRegistry{
+		mu: &sync.RWMutex{},
+		infos: map[Code]CodeInfo{
+			CodeUnknown: {
+				HTTPStatus: httpStatusInternalServerError,
+				RCode:      RCodeServerFailure,
+			},
+			CodeInvalidArgument: {
+				HTTPStatus: httpStatusBadRequest,
+				RCode:      RCodeFormatError,
+			},
+			CodeNotFound: {
+				HTTPStatus: httpStatusNotFound,
+				RCode:      RCodeNameError,
+			},
+			CodeAlreadyExists: {
+				HTTPStatus: httpStatusConflict,
+				RCode:      RCodeRefused,
+			},
+			CodePermissionDenied: {
+				HTTPStatus: httpStatusForbidden,
+				RCode:      RCodeRefused,
+			},
+			CodeUnavailable: {
+				HTTPStatus: httpStatusServiceUnavailable,
+				RCode:      RCodeServerFailure,
+			},
+			CodeTimeout: {
+				HTTPStatus: httpStatusGatewayTimeout,
+				RCode:      RCodeServerFailure,
+			},
+			CodeNotImplemented: {
+				HTTPStatus: httpStatusNotImplemented,
+				RCode:      RCodeNotImplemented,
+			},
+			CodeInternal: {
+				HTTPStatus: httpStatusInternalServerError,
+				RCode:      RCodeServerFailure,
+			},
+		},
+	}
+}
+
+// Register sets the transport-level information for c and associates
+// sentinels with it, so that CodeOf returns c for all errors that match any of
+// them according to Is.  Sentinels are checked in the order of registration.
+func (r *CodeRegistry) Register(c Code, info CodeInfo, sentinels ...error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.infos[c] = info
+	for _, target := range sentinels {
+		r.sentinels = append(r.sentinels, codedSentinel{
+			target: target,
+			code:   c,
+		})
+	}
+}
+
+// CodeOf returns the code of err.  If err is nil, c is empty.  If err or any
+// error in its chain implements Coder, the code of the first such error is
+// returned.  Otherwise, the code of the first matching registered sentinel is
+// returned.  If there is none, c is CodeUnknown.
+func (r *CodeRegistry) CodeOf(err error) (c Code) {
+	if err == nil {
+		return ""
+	}
+
+	var coder Coder
+	if As(err, &coder) {
+		return coder.Code()
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, s := range r.sentinels {
+		if Is(err, s.target) {
+			return s.code
+		}
+	}
+
+	return CodeUnknown
+}
+
+// Info returns the transport-level information for c.  ok is false if c is
+// not registered.
+func (r *CodeRegistry) Info(c Code) (info CodeInfo, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	info, ok = r.infos[c]
+
+	return info, ok
+}
+
+// infoOf returns the transport-level information for err.  Errors with
+// unregistered codes are treated as the ones with CodeUnknown.
+func (r *CodeRegistry) infoOf(err error) (info CodeInfo) {
+	info, ok := r.Info(r.CodeOf(err))
+	if !ok {
+		info, _ = r.Info(CodeUnknown)
+	}
+
+	return info
+}
+
+// HTTPStatus returns the HTTP status code for err.  If err is nil, status is
+// httpStatusOK.
+func (r *CodeRegistry) HTTPStatus(err error) (status int) {
+	if err == nil {
+		return httpStatusOK
+	}
+
+	return r.infoOf(err).HTTPStatus
+}
+
+// RCode returns the DNS response code for err.  If err is nil, rcode is
+// RCodeSuccess.
+func (r *CodeRegistry) RCode(err error) (rcode int) {
+	if err == nil {
+		return RCodeSuccess
+	}
+
+	return r.infoOf(err).RCode
+}
+
+// defaultCodeRegistry is the code registry used by the package-level
+// functions.
+var defaultCodeRegistry = NewCodeRegistry()
+
+// DefaultCodeRegistry returns the code registry used by CodeOf, RegisterCode,
+// HTTPStatus, and RCode.
+func DefaultCodeRegistry() (r *CodeRegistry) {
+	return defaultCodeRegistry
+}
+
+// RegisterCode registers c in the default code registry.  See
+// CodeRegistry.Register.
+func RegisterCode(c Code, info CodeInfo, sentinels ...error) {
+	defaultCodeRegistry.Register(c, info, sentinels...)
+}
+
+// CodeOf returns the code of err using the default code registry.  See
+// CodeRegistry.CodeOf.
+func CodeOf(err error) (c Code) {
+	return defaultCodeRegistry.CodeOf(err)
+}
+
+// HTTPStatus returns the HTTP status code for err using the default code
+// registry.  See CodeRegistry.HTTPStatus.
+func HTTPStatus(err error) (status int) {
+	return defaultCodeRegistry.HTTPStatus(err)
+}
+
+// RCode returns the DNS response code for err using the default code registry.
+// See CodeRegistry.RCode.
+func RCode(err error) (rcode int) {
+	return defaultCodeRegistry.RCode(err)
+}
@@ -5,6 +5,7 @@ package errors
 import (
 	stderrors "errors"
 	"fmt"
+	"io"
 	"strings"
 )
 
@@ -18,12 +19,25 @@ func (err Error) Error() (msg string) {
 	return string(err)
 }
 
+// ErrUnsupported indicates that a requested operation cannot be performed,
+// because it is unsupported.
+//
+// It is the same value as errors.ErrUnsupported from the Go standard library.
+// See go doc errors.ErrUnsupported for the full documentation.
+var ErrUnsupported = stderrors.ErrUnsupported
+
 // Wrapper is a copy of the hidden wrapper interface from the Go standard
 // library.  It is added here for tests, linting, etc.
 type Wrapper interface {
 	Unwrap() error
 }
 
+// WrapperSlice is a copy of the hidden wrapper interface added to the Go
+// standard library in Go 1.20.  It is added here for tests, linting, etc.
+type WrapperSlice interface {
+	Unwrap() []error
+}
+
 // As finds the first error in err's chain that matches target, and if so, sets
 // target to that error value and returns true.  Otherwise, it returns false.
 //
@@ -167,6 +181,59 @@ func WithDeferred(returned error, deferred error) (result error) {
 	}
 }
 
+// DeferredCollector collects errors returned by several cleanup functions, such
+// as Close, and merges them with the returned error.  This is useful in
+// functions that have several deferred cleanups, since chaining WithDeferred
+// produces nested pairs.  For example, replace this:
+//
+//   defer func() { err = errors.WithDeferred(err, f.Close()) }()
+//   defer func() { err = errors.WithDeferred(err, conn.Close()) }()
+//
+// With this:
+//
+//   dc := &errors.DeferredCollector{}
+//   defer func() { err = dc.Merge(err) }()
+//
+//   // …
+//
+//   defer dc.Close("closing file", f)
+//   defer dc.Close("closing conn", conn)
+//
+// The deferred function calling Merge must be the first one, so that it is
+// called after all cleanups.  A DeferredCollector is not safe for concurrent
+// use.
+type DeferredCollector struct {
+	errs []error
+}
+
+// Add adds a non-nil err returned by a cleanup function to c, annotating it
+// with name, which describes the cleanup.  If err is nil, c is not changed.
+func (c *DeferredCollector) Add(name string, err error) {
+	if err != nil {
+		c.errs = append(c.errs, fmt.Errorf("%s: %w", name, err))
+	}
+}
+
+// Close calls closer.Close and adds the error it returns, if any, to c.  If
+// closer is nil, it is simply ignored.
+func (c *DeferredCollector) Close(name string, closer io.Closer) {
+	if closer != nil {
+		c.Add(name, closer.Close())
+	}
+}
+
+// Func calls f and adds the error it returns, if any, to c.
+func (c *DeferredCollector) Func(name string, f func() (err error)) {
+	c.Add(name, f())
+}
+
+// Merge returns the returned error merged with the collected errors in the
+// same way WithDeferred does.  The collected errors are joined using Join, so
+// the deferred error contains all of them in the order of the cleanups.
+func (c *DeferredCollector) Merge(returned error) (result error) {
+	return WithDeferred(returned, Join(c.errs...))
+}
+
 // listError is an error containing several wrapped errors.
 type listError struct {
 	msg  string
@@ -216,8 +283,85 @@ func (err *listError) Unwrap() (unwrapped error) {
 	return err.errs[0]
 }
 
+// joinError is an error containing several errors, none of which is more
+// important than the others.
+type joinError struct {
+	errs []error
+}
+
+// type check
+var _ WrapperSlice = (*joinError)(nil)
+
+// Join returns an error that wraps errs.  Nil errors are discarded.  Join
+// returns nil if there are no non-nil errors in errs.  If there is only one
+// non-nil error, Join returns it as is.
+//
+// The result formats the errors in the same way as List does, and implements
+// the WrapperSlice interface, so that Is and As check all of the errors.  Use
+// Errors to get them back.
+func Join(errs ...error) (err error) {
+	var nonNil []error
+	for _, e := range errs {
+		if e != nil {
+			nonNil = append(nonNil, e)
+		}
+	}
+
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	default:
+		return &joinError{
+			errs: nonNil,
+		}
+	}
+}
+
+// Error implements the error interface for *joinError.
+func (err *joinError) Error() (msg string) {
+	b := &strings.Builder{}
+
+	// Here and further, ignore the errors since they are known to be nil.
+	l := len(err.errs)
+	_, _ = fmt.Fprintf(b, "%d errors: ", l)
+
+	for i, e := range err.errs {
+		if i == l-1 {
+			_, _ = fmt.Fprintf(b, "%q", e)
+		} else {
+			_, _ = fmt.Fprintf(b, "%q, ", e)
+		}
+	}
+
+	return b.String()
+}
+
+// Unwrap implements the WrapperSlice interface for *joinError.
+func (err *joinError) Unwrap() (errs []error) {
+	return err.errs
+}
+
+// Errors returns the errors wrapped by err, if err implements WrapperSlice.  If
+// err is nil, Errors returns nil.  Otherwise, Errors returns a slice with err
+// as its only element.  Callers must not modify the result.
+func Errors(err error) (errs []error) {
+	if err == nil {
+		return nil
+	}
+
+	if w, ok := err.(WrapperSlice); ok {
+		return w.Unwrap()
+	}
+
+	return []error{err}
+}
+
 // Annotate annotates the error with the message, unless the error is nil.  The
 // last verb in format must be a verb compatible with errors, for example "%w".
+// If err's chain contains a stack trace recorded by WithStack, it is printed
+// when the result is formatted using the "%+v" verb.
 //
 // In Defers
 //
@@ -273,5 +417,63 @@ func Annotate(err error, format string, args ...interface{}) (annotated error) {
 		return nil
 	}
 
-	return fmt.Errorf(format, append(args, err)...)
+	annotated = fmt.Errorf(format, append(args, err)...)
+
+	if hasStack(err) {
+		return &stackAnnotatedError{err: annotated}
+	}
+
+	return annotated
+}
+
+// lazyAnnotatedError is an annotated error that formats its message only when
+// it is requested.
+type lazyAnnotatedError struct {
+	err    error
+	format string
+	args   []interface{}
+}
+
+// AnnotateLazy is like Annotate but defers formatting the message until the
+// Error method of the result is called.  This saves allocations and CPU time
+// in hot paths where errors are often inspected using Is or As or simply
+// counted but rarely printed.  The result always unwraps to err.
+//
+// Since the message is formatted on every call to Error, args must not be
+// mutated after the call to AnnotateLazy, and values that are expensive to
+// format should be avoided.  The same warning about the "err" variable as in
+// the documentation of Annotate applies.
+func AnnotateLazy(err error, format string, args ...interface{}) (annotated error) {
+	if err == nil {
+		return nil
+	}
+
+	return &lazyAnnotatedError{
+		err:    err,
+		format: format,
+		// Copy the arguments so that the slice created by the caller doesn't
+		// escape to the heap when err is nil.
+		args: append([]interface{}(nil), args...),
+	}
+}
+
+// Error implements the error interface for *lazyAnnotatedError.
+func (err *lazyAnnotatedError) Error() (msg string) {
+	// Use a full slice expression to make sure that the arguments are never
+	// overwritten by concurrent calls.
+	args := err.args[:len(err.args):len(err.args)]
+
+	return fmt.Errorf(err.format, append(args, err.err)...).Error()
+}
+
+// Unwrap implements the Wrapper interface for *lazyAnnotatedError.
+func (err *lazyAnnotatedError) Unwrap() (unwrapped error) {
+	return err.err
+}
+
+// Format implements the fmt.Formatter interface for *lazyAnnotatedError.  The
+// "%+v" verb prints the message followed by the stack trace found in the chain,
+// if any.
+func (err *lazyAnnotatedError) Format(s fmt.State, verb rune) {
+	formatWithStack(s, verb, err)
 }
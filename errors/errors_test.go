@@ -0,0 +1,109 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errSink is a typed sink for benchmarks.
+var errSink error
+
+// errBench is the error for benchmarks.
+const errBench errors.Error = "test error"
+
+func BenchmarkAnnotate(b *testing.B) {
+	const domain = "example.com"
+	const qtype = 28
+
+	b.Run("nil", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			errSink = errors.Annotate(nil, "resolving %q of type %d: %w", domain, qtype)
+		}
+	})
+
+	b.Run("nil_lazy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			errSink = errors.AnnotateLazy(nil, "resolving %q of type %d: %w", domain, qtype)
+		}
+	})
+
+	b.Run("err", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			errSink = errors.Annotate(errBench, "resolving %q of type %d: %w", domain, qtype)
+		}
+	})
+
+	b.Run("err_lazy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			errSink = errors.AnnotateLazy(errBench, "resolving %q of type %d: %w", domain, qtype)
+		}
+	})
+
+	b.Run("err_lazy_printed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			errSink = errors.AnnotateLazy(errBench, "resolving %q of type %d: %w", domain, qtype)
+			_ = errSink.Error()
+		}
+	})
+
+	// Most recent result:
+	//
+	//	goos: linux
+	//	goarch: amd64
+	//	pkg: github.com/AdguardTeam/golibs/errors
+	//	cpu: Intel(R) Xeon(R) Processor
+	//	BenchmarkAnnotate/nil                 	273926940	         3.995 ns/op	       0 B/op	       0 allocs/op
+	//	BenchmarkAnnotate/nil_lazy            	1000000000	         1.060 ns/op	       0 B/op	       0 allocs/op
+	//	BenchmarkAnnotate/err                 	 1934506	       574.3 ns/op	     144 B/op	       3 allocs/op
+	//	BenchmarkAnnotate/err_lazy            	12058440	       121.7 ns/op	      96 B/op	       2 allocs/op
+	//	BenchmarkAnnotate/err_lazy_printed    	 1845782	       645.9 ns/op	     240 B/op	       5 allocs/op
+}
+
+func TestAnnotate_stack(t *testing.T) {
+	t.Parallel()
+
+	err := errors.WithStack(errBench)
+
+	testCases := []struct {
+		err  error
+		name string
+	}{{
+		err:  errors.Annotate(err, "first: %w"),
+		name: "annotate",
+	}, {
+		err:  errors.AnnotateLazy(err, "first: %w"),
+		name: "annotate_lazy",
+	}, {
+		err:  errors.Annotate(errors.Annotate(err, "first: %w"), "second: %w"),
+		name: "annotate_twice",
+	}, {
+		err:  errors.Annotate(errors.Join(errors.Error("other"), err), "joined: %w"),
+		name: "annotate_joined",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			msg := fmt.Sprintf("%+v", tc.err)
+			lines := strings.Split(msg, "\n")
+			require.Greater(t, len(lines), 2)
+
+			assert.Equal(t, tc.err.Error(), lines[0])
+			assert.Contains(t, lines[1], "TestAnnotate_stack")
+			assert.Equal(t, tc.err.Error(), fmt.Sprintf("%v", tc.err))
+			assert.ErrorIs(t, tc.err, errBench)
+		})
+	}
+}
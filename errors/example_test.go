@@ -1,8 +1,12 @@
 package errors_test
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"strings"
 
 	"github.com/AdguardTeam/golibs/errors"
 )
@@ -110,6 +114,27 @@ func ExampleAnnotate_end() {
 	// without err : <nil>
 }
 
+func ExampleAnnotateLazy() {
+	const errNotFound errors.Error = "not found"
+
+	f := func(fn string) (err error) {
+		defer func() { err = errors.AnnotateLazy(err, "reading %q: %w", fn) }()
+
+		return errNotFound
+	}
+
+	err := f("non-existing")
+	fmt.Println("is        :", errors.Is(err, errNotFound))
+	fmt.Println("with err  :", err)
+	fmt.Println("unwrapped :", errors.Unwrap(err))
+
+	// Output:
+	//
+	// is        : true
+	// with err  : reading "non-existing": not found
+	// unwrapped : not found
+}
+
 func ExampleAnnotate_bad() {
 	const errNotFound errors.Error = "not found"
 
@@ -139,6 +164,136 @@ func ExampleAnnotate_bad() {
 	// not found
 }
 
+func ExampleWithAttrs() {
+	const errTimeout errors.Error = "timeout"
+
+	err := errors.WithAttrs(errTimeout, slog.String("upstream", "1.2.3.4:53"))
+	err = errors.Annotate(err, "exchanging: %w")
+	err = errors.WithAttrs(err, slog.String("client", "5.6.7.8"))
+	fmt.Println("err   :", err)
+	fmt.Println("is    :", errors.Is(err, errTimeout))
+
+	for _, a := range errors.Attrs(err) {
+		fmt.Println("attr  :", a)
+	}
+
+	h := slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) (res slog.Attr) {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	})
+	slog.New(h).Error("handling query", "err", err)
+
+	// Output:
+	//
+	// err   : exchanging: timeout
+	// is    : true
+	// attr  : client=5.6.7.8
+	// attr  : upstream=1.2.3.4:53
+	// level=ERROR msg="handling query" err.msg="exchanging: timeout" err.client=5.6.7.8 err.upstream=1.2.3.4:53
+}
+
+func ExampleCodeRegistry() {
+	const (
+		errNoClient errors.Error = "no such client"
+		errBadQuery errors.Error = "bad query"
+
+		codeQuota errors.Code = "quota"
+	)
+
+	r := errors.NewCodeRegistry()
+	r.Register(errors.CodeNotFound, errors.CodeInfo{
+		HTTPStatus: http.StatusNotFound,
+		RCode:      errors.RCodeRefused,
+	}, errNoClient)
+	r.Register(errors.CodeInvalidArgument, errors.CodeInfo{
+		HTTPStatus: http.StatusBadRequest,
+		RCode:      errors.RCodeFormatError,
+	}, errBadQuery)
+	r.Register(codeQuota, errors.CodeInfo{
+		HTTPStatus: http.StatusTooManyRequests,
+		RCode:      errors.RCodeRefused,
+	})
+
+	errs := []error{
+		nil,
+		errors.Annotate(errNoClient, "finding client %q: %w", "abc"),
+		errBadQuery,
+		errors.WithCode(errors.Error("limit reached"), codeQuota),
+		errors.Error("something else"),
+	}
+
+	for _, err := range errs {
+		fmt.Printf("%v: code %q, http %d, rcode %d\n", err, r.CodeOf(err), r.HTTPStatus(err), r.RCode(err))
+	}
+
+	// Output:
+	//
+	// <nil>: code "", http 200, rcode 0
+	// finding client "abc": no such client: code "not_found", http 404, rcode 5
+	// bad query: code "invalid_argument", http 400, rcode 1
+	// limit reached: code "quota", http 429, rcode 5
+	// something else: code "unknown", http 500, rcode 2
+}
+
+func ExampleKind() {
+	const errNoUser errors.Error = "no such user"
+
+	err := errors.Annotate(errors.NotFound(errNoUser), "getting user %q: %w", "abc")
+	fmt.Println("err          :", err)
+	fmt.Println("kind         :", errors.KindOf(err))
+	fmt.Println("is not found :", errors.IsNotFound(err))
+	fmt.Println("is kind      :", errors.Is(err, errors.KindNotFound))
+	fmt.Println("is sentinel  :", errors.Is(err, errNoUser))
+	fmt.Println("is timeout   :", errors.IsTimeout(err))
+
+	var kindErr *errors.KindError
+	if errors.As(err, &kindErr) {
+		fmt.Println("as           :", kindErr.Kind, kindErr.Err)
+	}
+
+	// Output:
+	//
+	// err          : getting user "abc": no such user
+	// kind         : not found
+	// is not found : true
+	// is kind      : true
+	// is sentinel  : true
+	// is timeout   : false
+	// as           : not found no such user
+}
+
+func ExampleIsRetryable() {
+	const (
+		errBusy     errors.Error = "server busy"
+		errNotFound errors.Error = "not found"
+	)
+
+	errs := []error{
+		nil,
+		errNotFound,
+		errors.Annotate(errors.MarkRetryable(errBusy), "exchanging: %w"),
+		context.DeadlineExceeded,
+		errors.MarkPermanent(context.DeadlineExceeded),
+	}
+
+	for _, err := range errs {
+		fmt.Printf("%v: %t\n", err, errors.IsRetryable(err))
+	}
+
+	// Output:
+	//
+	// <nil>: false
+	// not found: false
+	// exchanging: server busy: true
+	// context deadline exceeded: true
+	// context deadline exceeded: false
+}
+
 func ExampleDeferred() {
 	const (
 		errClose    errors.Error = "close fail"
@@ -183,6 +338,42 @@ func ExampleDeferred() {
 	// warning: close fail
 }
 
+func ExampleDeferredCollector() {
+	const (
+		errCloseConn errors.Error = "conn close fail"
+		errCloseFile errors.Error = "file close fail"
+		errNotFound  errors.Error = "not found"
+	)
+
+	closeConn := func() (err error) { return errCloseConn }
+	closeFile := func() (err error) { return errCloseFile }
+
+	f := func(retErr error) (err error) {
+		dc := &errors.DeferredCollector{}
+		defer func() { err = dc.Merge(err) }()
+
+		defer dc.Func("closing file", closeFile)
+		defer dc.Func("closing conn", closeConn)
+
+		return retErr
+	}
+
+	err := f(nil)
+	fmt.Println("deferred only :", err)
+	fmt.Println("is conn err   :", errors.Is(err, errCloseConn))
+	fmt.Println("is file err   :", errors.Is(err, errCloseFile))
+
+	err = f(errNotFound)
+	fmt.Println("with returned :", err)
+
+	// Output:
+	//
+	// deferred only : deferred: 2 errors: "closing conn: conn close fail", "closing file: file close fail"
+	// is conn err   : true
+	// is file err   : true
+	// with returned : returned: "not found", deferred: "2 errors: \"closing conn: conn close fail\", \"closing file: file close fail\""
+}
+
 func ExampleError() {
 	const errNotFound errors.Error = "not found"
 
@@ -222,6 +413,37 @@ func ExampleList() {
 	// msg and errs : "fail: 2 errors: \"stage 1\", \"stage 2\"" "stage 1"
 }
 
+func ExampleJoin() {
+	const (
+		errBadName errors.Error = "bad name"
+		errBadPort errors.Error = "bad port"
+	)
+
+	fmt.Println("no errs   :", errors.Join(nil, nil))
+	fmt.Println("one err   :", errors.Join(nil, errBadName))
+
+	err := errors.Join(errBadName, nil, fmt.Errorf("port 0: %w", errBadPort))
+	fmt.Println("two errs  :", err)
+	fmt.Println("is name   :", errors.Is(err, errBadName))
+	fmt.Println("is port   :", errors.Is(err, errBadPort))
+	fmt.Println("unwrapped :", errors.Unwrap(err))
+
+	for i, e := range errors.Errors(err) {
+		fmt.Printf("err %d     : %s\n", i, e)
+	}
+
+	// Output:
+	//
+	// no errs   : <nil>
+	// one err   : bad name
+	// two errs  : 2 errors: "bad name", "port 0: bad port"
+	// is name   : true
+	// is port   : true
+	// unwrapped : <nil>
+	// err 0     : bad name
+	// err 1     : port 0: bad port
+}
+
 func ExamplePair() {
 	close := func(fn string) (err error) { return errors.Error("close fail") }
 	f := func(fn string) (err error) {
@@ -342,3 +564,31 @@ func ExampleWithDeferred_bad() {
 	//
 	// not found
 }
+
+func ExampleWithStack() {
+	const errNotFound errors.Error = "not found"
+
+	f := func(fn string) (err error) {
+		return errors.WithStack(errNotFound)
+	}
+
+	err := f("non-existing")
+	err = errors.Annotate(err, "reading: %w")
+	fmt.Println("err        :", err)
+	fmt.Println("is         :", errors.Is(err, errNotFound))
+
+	frames := errors.StackTrace(err)
+	fmt.Println("has frames :", len(frames) > 0)
+	fmt.Println("top frame  :", strings.HasSuffix(frames[0].Function, "ExampleWithStack.func1"))
+
+	msg := fmt.Sprintf("%+v", err)
+	fmt.Println("with stack :", strings.HasPrefix(msg, "reading: not found\n") && strings.Contains(msg, ".go:"))
+
+	// Output:
+	//
+	// err        : reading: not found
+	// is         : true
+	// has frames : true
+	// top frame  : true
+	// with stack : true
+}
@@ -0,0 +1,119 @@
+package errors
+
+// Kind is the class of an error, such as a validation error or a timeout.  It
+// allows handling whole classes of errors uniformly without defining parallel
+// sets of sentinel errors.  Kind also implements the error interface, so that
+// it can be used as a target for Is.
+type Kind string
+
+// Common error kinds.
+const (
+	KindValidation  Kind = "validation"
+	KindTimeout     Kind = "timeout"
+	KindNotFound    Kind = "not found"
+	KindPermission  Kind = "permission"
+	KindUnavailable Kind = "unavailable"
+	KindInternal    Kind = "internal"
+)
+
+// Error implements the error interface for Kind.
+func (k Kind) Error() (msg string) {
+	return string(k)
+}
+
+// KindError is an error of a particular kind.  Use As with a **KindError or Is
+// with a Kind to inspect it.
+type KindError struct {
+	// Err is the underlying error.
+	Err error
+
+	// Kind is the kind of the error.
+	Kind Kind
+}
+
+// type check
+var _ Iser = (*KindError)(nil)
+
+// Error implements the error interface for *KindError.  It returns the message
+// of the underlying error.
+func (err *KindError) Error() (msg string) {
+	return err.Err.Error()
+}
+
+// Unwrap implements the Wrapper interface for *KindError.
+func (err *KindError) Unwrap() (unwrapped error) {
+	return err.Err
+}
+
+// Is implements the Iser interface for *KindError.  It returns true if target
+// is a Kind equal to err.Kind.
+func (err *KindError) Is(target error) (ok bool) {
+	k, ok := target.(Kind)
+
+	return ok && k == err.Kind
+}
+
+// WithKind returns err with the kind k attached to it, unless err is nil.  The
+// result has the underlying type of *KindError.
+func WithKind(err error, k Kind) (withKind error) {
+	if err == nil {
+		return nil
+	}
+
+	return &KindError{
+		Err:  err,
+		Kind: k,
+	}
+}
+
+// KindOf returns the kind of the first *KindError in err's chain.  If there is
+// none, k is empty.
+func KindOf(err error) (k Kind) {
+	var kindErr *KindError
+	if As(err, &kindErr) {
+		return kindErr.Kind
+	}
+
+	return ""
+}
+
+// Validation returns err with the kind KindValidation, unless err is nil.
+func Validation(err error) (withKind error) { return WithKind(err, KindValidation) }
+
+// Timeout returns err with the kind KindTimeout, unless err is nil.
+func Timeout(err error) (withKind error) { return WithKind(err, KindTimeout) }
+
+// NotFound returns err with the kind KindNotFound, unless err is nil.
+func NotFound(err error) (withKind error) { return WithKind(err, KindNotFound) }
+
+// Permission returns err with the kind KindPermission, unless err is nil.
+func Permission(err error) (withKind error) { return WithKind(err, KindPermission) }
+
+// Unavailable returns err with the kind KindUnavailable, unless err is nil.
+func Unavailable(err error) (withKind error) { return WithKind(err, KindUnavailable) }
+
+// Internal returns err with the kind KindInternal, unless err is nil.
+func Internal(err error) (withKind error) { return WithKind(err, KindInternal) }
+
+// IsValidation returns true if any error in err's chain has the kind
+// KindValidation.
+func IsValidation(err error) (ok bool) { return Is(err, KindValidation) }
+
+// IsTimeout returns true if any error in err's chain has the kind KindTimeout.
+func IsTimeout(err error) (ok bool) { return Is(err, KindTimeout) }
+
+// IsNotFound returns true if any error in err's chain has the kind
+// KindNotFound.
+func IsNotFound(err error) (ok bool) { return Is(err, KindNotFound) }
+
+// IsPermission returns true if any error in err's chain has the kind
+// KindPermission.
+func IsPermission(err error) (ok bool) { return Is(err, KindPermission) }
+
+// IsUnavailable returns true if any error in err's chain has the kind
+// KindUnavailable.
+func IsUnavailable(err error) (ok bool) { return Is(err, KindUnavailable) }
+
+// IsInternal returns true if any error in err's chain has the kind
+// KindInternal.
+func IsInternal(err error) (ok bool) { return Is(err, KindInternal) }
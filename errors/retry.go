@@ -0,0 +1,87 @@
+package errors
+
+// Retryable is the interface for errors that report whether the failed
+// operation can safely be retried.
+//
+// Method Retryable returns a bool to mirror the behavior of types like
+// net.Error and allow implementations to decide if the operation can be
+// retried dynamically.  Prefer IsRetryable to checking it manually.
+type Retryable interface {
+	error
+	Retryable() (ok bool)
+}
+
+// retryableError is a helper to implement Retryable.
+type retryableError struct {
+	error
+	retryable bool
+}
+
+// type check
+var _ Retryable = retryableError{}
+
+// Retryable implements the Retryable interface for retryableError.
+func (err retryableError) Retryable() (ok bool) {
+	return err.retryable
+}
+
+// Unwrap implements the Wrapper interface for retryableError.
+func (err retryableError) Unwrap() (unwrapped error) {
+	return err.error
+}
+
+// MarkRetryable returns err marked as safe to retry, unless err is nil.  The
+// message of the error is not changed.
+func MarkRetryable(err error) (marked error) {
+	if err == nil {
+		return nil
+	}
+
+	return retryableError{
+		error:     err,
+		retryable: true,
+	}
+}
+
+// MarkPermanent returns err marked as not safe to retry, unless err is nil.
+// This is useful to override the retryability of an error further down the
+// chain, for example a timeout after which a retry makes no sense.
+func MarkPermanent(err error) (marked error) {
+	if err == nil {
+		return nil
+	}
+
+	return retryableError{
+		error:     err,
+		retryable: false,
+	}
+}
+
+// timeouter is the interface for errors that can be timeouts, for example
+// net.Error.
+type timeouter interface {
+	Timeout() (ok bool)
+}
+
+// IsRetryable returns true if the operation that failed with err can be
+// retried.  The first error in err's chain implementing Retryable decides.  If
+// there is none, errors that report a timeout through a Timeout method, such
+// as net.Error and context.DeadlineExceeded, are considered retryable.
+// IsRetryable returns false for nil errors.
+func IsRetryable(err error) (ok bool) {
+	if err == nil {
+		return false
+	}
+
+	var r Retryable
+	if As(err, &r) {
+		return r.Retryable()
+	}
+
+	var t timeouter
+	if As(err, &t) {
+		return t.Timeout()
+	}
+
+	return false
+}
@@ -0,0 +1,171 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// maxStackDepth is the maximum number of frames recorded by WithStack.
+const maxStackDepth = 32
+
+// StackTracer is the interface for errors that contain the stack trace of the
+// place where they have been created.
+type StackTracer interface {
+	error
+	StackTrace() (frames []runtime.Frame)
+}
+
+// stackError is an error with a recorded stack trace.
+type stackError struct {
+	err error
+	pcs []uintptr
+}
+
+// type check
+var _ StackTracer = (*stackError)(nil)
+
+// WithStack returns err with the stack trace of the caller recorded, unless
+// err is nil.  If err already contains a stack trace in its chain, it is
+// returned as is, since the deepest stack trace is usually the most useful one.
+//
+// The stack trace can be retrieved using StackTrace and is printed when the
+// result, or an error returned by Annotate or AnnotateLazy for it, is formatted
+// using the "%+v" verb.  Since recording the stack is
+// relatively expensive, WithStack should not be used in hot paths.
+func WithStack(err error) (withStack error) {
+	if err == nil {
+		return nil
+	}
+
+	if hasStack(err) {
+		return err
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+
+	// Skip runtime.Callers and WithStack itself.
+	n := runtime.Callers(2, pcs)
+
+	return &stackError{
+		err: err,
+		pcs: pcs[:n],
+	}
+}
+
+// StackTrace returns the stack trace of the first error in err's chain that
+// implements StackTracer.  If there is none, StackTrace returns nil.
+func StackTrace(err error) (frames []runtime.Frame) {
+	var st StackTracer
+	if !As(err, &st) {
+		return nil
+	}
+
+	return st.StackTrace()
+}
+
+// hasStack returns true if err's chain contains a StackTracer.  Unlike As, it
+// doesn't allocate, so it can be used in hot paths, such as Annotate.
+func hasStack(err error) (ok bool) {
+	for err != nil {
+		if _, ok = err.(StackTracer); ok {
+			return true
+		}
+
+		switch e := err.(type) {
+		case Wrapper:
+			err = e.Unwrap()
+		case WrapperSlice:
+			for _, wrapped := range e.Unwrap() {
+				if hasStack(wrapped) {
+					return true
+				}
+			}
+
+			return false
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// Error implements the error interface for *stackError.
+func (err *stackError) Error() (msg string) {
+	return err.err.Error()
+}
+
+// Unwrap implements the Wrapper interface for *stackError.
+func (err *stackError) Unwrap() (unwrapped error) {
+	return err.err
+}
+
+// StackTrace implements the StackTracer interface for *stackError.
+func (err *stackError) StackTrace() (frames []runtime.Frame) {
+	callersFrames := runtime.CallersFrames(err.pcs)
+	for {
+		f, more := callersFrames.Next()
+		frames = append(frames, f)
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// Format implements the fmt.Formatter interface for *stackError.  The "%+v"
+// verb prints the message followed by the stack trace, one frame per two
+// lines.
+func (err *stackError) Format(s fmt.State, verb rune) {
+	formatWithStack(s, verb, err)
+}
+
+// stackAnnotatedError is an error returned by Annotate when the chain of the
+// annotated error contains a stack trace.  It only exists to print the stack
+// trace with the "%+v" verb, which the errors returned by fmt.Errorf don't.
+type stackAnnotatedError struct {
+	err error
+}
+
+// Error implements the error interface for *stackAnnotatedError.
+func (err *stackAnnotatedError) Error() (msg string) {
+	return err.err.Error()
+}
+
+// Unwrap implements the Wrapper interface for *stackAnnotatedError.
+func (err *stackAnnotatedError) Unwrap() (unwrapped error) {
+	return err.err
+}
+
+// Format implements the fmt.Formatter interface for *stackAnnotatedError.  The
+// "%+v" verb prints the message followed by the stack trace found in the chain.
+func (err *stackAnnotatedError) Format(s fmt.State, verb rune) {
+	formatWithStack(s, verb, err)
+}
+
+// formatWithStack formats err for the fmt.Formatter interface.  The "%+v" verb
+// prints the message followed by the first stack trace in err's chain, if any,
+// one frame per two lines.
+func formatWithStack(s fmt.State, verb rune, err error) {
+	// Here and further, ignore the errors since there is nothing that can be
+	// done with them.
+	switch verb {
+	case 'v':
+		_, _ = io.WriteString(s, err.Error())
+		if !s.Flag('+') {
+			return
+		}
+
+		for _, f := range StackTrace(err) {
+			_, _ = fmt.Fprintf(s, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+		}
+	case 's':
+		_, _ = io.WriteString(s, err.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", err.Error())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(%s)", verb, err.Error())
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/AdguardTeam/golibs
 
-go 1.16
+go 1.21
 
 require (
 	github.com/stretchr/testify v1.7.0
@@ -9,5 +9,7 @@ require (
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/text v0.3.7 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
 )
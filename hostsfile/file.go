@@ -0,0 +1,316 @@
+package hostsfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/netutil"
+)
+
+// File is a hosts file which can be modified and written back while
+// preserving the comments, the empty lines, the invalid lines, and the order of
+// the lines.  The lines that haven't been modified are written exactly as they
+// have been read.
+type File struct {
+	lines   []*fileLine
+	errs    []error
+	newline string
+
+	// noFinalNewline is true if the last line of the data has had no line
+	// terminator.
+	noFinalNewline bool
+}
+
+// fileLine is a single line of a *File.
+type fileLine struct {
+	// rec is the record of the line.  It is nil for the empty, comment, and
+	// invalid lines.
+	rec *Record
+
+	// raw is the original text of the line without the line terminator.  It
+	// is empty if the line has been added or modified.
+	raw string
+
+	// comment is the inline comment of a record line including the leading
+	// "#", if any.
+	comment string
+}
+
+// type check
+var _ io.WriterTo = (*File)(nil)
+
+// ReadFile reads and parses a hosts file from src.  source is used as the
+// source name of the records and errors.  err is only returned for the errors
+// of reading src, the invalid lines are preserved and reported by
+// File.Errors.
+func ReadFile(src io.Reader, source string) (f *File, err error) {
+	f = &File{
+		newline: "\n",
+	}
+
+	r := bufio.NewReader(src)
+	for lineNum := 1; ; lineNum++ {
+		var line string
+		line, err = r.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("reading %s: %w", source, err)
+		} else if line == "" {
+			return f, nil
+		}
+
+		if lineNum == 1 && strings.HasSuffix(line, "\r\n") {
+			f.newline = "\r\n"
+		}
+
+		f.noFinalNewline = !strings.HasSuffix(line, "\n")
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		f.lines = append(f.lines, f.parseLine(line, source, lineNum))
+	}
+}
+
+// parseLine parses a single line of f.
+func (f *File) parseLine(line, source string, lineNum int) (fl *fileLine) {
+	fl = &fileLine{
+		raw: line,
+	}
+
+	rec := &Record{
+		Source: source,
+		Line:   lineNum,
+	}
+
+	err := parseLine(rec, []byte(line))
+	if err != nil {
+		f.errs = append(f.errs, &LineError{
+			Err:    err,
+			Source: source,
+			Line:   lineNum,
+		})
+	} else if rec.Names != nil {
+		fl.rec = rec
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			fl.comment = line[i:]
+		}
+	}
+
+	return fl
+}
+
+// Errors returns the errors for the invalid lines, all of which have the
+// underlying type of *LineError.
+func (f *File) Errors() (errs []error) {
+	return f.errs
+}
+
+// Records returns the records of f in the order of their appearance.  The
+// records must not be modified.
+func (f *File) Records() (recs []*Record) {
+	for _, l := range f.lines {
+		if l.rec != nil {
+			recs = append(recs, l.rec)
+		}
+	}
+
+	return recs
+}
+
+// Add appends a new record line with addr and names to the end of f.  names
+// must not be empty and must be valid hostnames.
+func (f *File) Add(addr netip.Addr, names ...string) (err error) {
+	err = validateNames(names)
+	if err != nil {
+		return err
+	}
+
+	f.lines = append(f.lines, newRecordLine(addr, names))
+	f.noFinalNewline = false
+
+	return nil
+}
+
+// Update replaces the hostnames of all records with addr with names, keeping
+// their inline comments, and returns the number of updated records.  names
+// must not be empty and must be valid hostnames.
+func (f *File) Update(addr netip.Addr, names ...string) (n int, err error) {
+	err = validateNames(names)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, l := range f.lines {
+		if l.rec != nil && l.rec.Addr == addr {
+			l.rec.Names = append([]string(nil), names...)
+			l.raw = ""
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// validateNames returns an error if names can't be used in a record line, so
+// that the written file can be read back without errors.
+func validateNames(names []string) (err error) {
+	if len(names) == 0 {
+		return ErrNoHosts
+	}
+
+	for i, name := range names {
+		err = netutil.ValidateDomainName(name)
+		if err != nil {
+			return fmt.Errorf("name at index %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Remove removes all record lines for which remove returns true and returns
+// the number of removed lines.
+func (f *File) Remove(remove func(rec *Record) (ok bool)) (n int) {
+	lines := f.lines[:0]
+	for _, l := range f.lines {
+		if l.rec != nil && remove(l.rec) {
+			n++
+
+			continue
+		}
+
+		lines = append(lines, l)
+	}
+
+	f.lines = lines
+
+	return n
+}
+
+// SetBlock replaces the contents of the block of lines between the
+// "# BEGIN name" and "# END name" comment lines with the records recs.  If
+// there is no such block, it is appended to the end of f.  If recs is empty,
+// the block is removed.  Blocks allow tools to manage a part of the hosts file
+// without touching the entries added by the user.
+//
+// The records must have valid addresses and hostnames.  If f contains the
+// beginning line of the block but no ending line after it, SetBlock returns
+// ErrUnterminatedBlock, since it's unclear which lines belong to the block.  f
+// isn't changed if an error is returned.
+func (f *File) SetBlock(name string, recs []*Record) (err error) {
+	err = validateRecords(recs)
+	if err != nil {
+		return err
+	}
+
+	begin, end := "# BEGIN "+name, "# END "+name
+	beginIdx, endIdx := f.findBlock(begin, end)
+	if beginIdx >= 0 && endIdx < 0 {
+		return fmt.Errorf("block %q: %w", name, ErrUnterminatedBlock)
+	}
+
+	block := newBlock(begin, end, recs)
+	if beginIdx < 0 {
+		if len(block) > 0 {
+			f.lines = append(f.lines, block...)
+			f.noFinalNewline = false
+		}
+
+		return nil
+	}
+
+	tail := append(block, f.lines[endIdx+1:]...)
+	f.lines = append(f.lines[:beginIdx], tail...)
+
+	return nil
+}
+
+// validateRecords returns an error if any of recs can't be written as a valid
+// record line.
+func validateRecords(recs []*Record) (err error) {
+	for i, rec := range recs {
+		if !rec.Addr.IsValid() {
+			err = errors.Error("invalid address")
+		} else {
+			err = validateNames(rec.Names)
+		}
+
+		if err != nil {
+			return fmt.Errorf("record at index %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// findBlock returns the indexes of the first begin line and the first end line
+// after it.  The indexes are -1 if there are no such lines.
+func (f *File) findBlock(begin, end string) (beginIdx, endIdx int) {
+	beginIdx, endIdx = -1, -1
+	for i, l := range f.lines {
+		switch strings.TrimSpace(l.raw) {
+		case begin:
+			if beginIdx < 0 {
+				beginIdx = i
+			}
+		case end:
+			if beginIdx >= 0 {
+				return beginIdx, i
+			}
+		}
+	}
+
+	return beginIdx, endIdx
+}
+
+// newBlock returns the lines of a block with recs between the begin and end
+// lines.  It returns nil if recs is empty.
+func newBlock(begin, end string, recs []*Record) (block []*fileLine) {
+	if len(recs) == 0 {
+		return nil
+	}
+
+	block = append(block, &fileLine{raw: begin})
+	for _, rec := range recs {
+		block = append(block, newRecordLine(rec.Addr, rec.Names))
+	}
+
+	return append(block, &fileLine{raw: end})
+}
+
+// newRecordLine returns a new record line.
+func newRecordLine(addr netip.Addr, names []string) (l *fileLine) {
+	return &fileLine{
+		rec: &Record{
+			Addr:  addr,
+			Names: append([]string(nil), names...),
+		},
+	}
+}
+
+// WriteTo implements the io.WriterTo interface for *File.  The line terminators
+// are the same as the ones of the first line of the original data.
+func (f *File) WriteTo(w io.Writer) (n int64, err error) {
+	b := &strings.Builder{}
+
+	// Here and further, ignore the errors since they are known to be nil.
+	for i, l := range f.lines {
+		switch {
+		case l.raw != "", l.rec == nil:
+			_, _ = b.WriteString(l.raw)
+		case l.comment != "":
+			_, _ = b.WriteString(l.rec.String() + " " + l.comment)
+		default:
+			_, _ = b.WriteString(l.rec.String())
+		}
+
+		if i < len(f.lines)-1 || !f.noFinalNewline {
+			_, _ = b.WriteString(f.newline)
+		}
+	}
+
+	written, err := io.WriteString(w, b.String())
+
+	return int64(written), err
+}
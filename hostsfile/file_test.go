@@ -0,0 +1,204 @@
+package hostsfile_test
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/hostsfile"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readFile is a helper that reads a *hostsfile.File from data.
+func readFile(t *testing.T, data string) (f *hostsfile.File) {
+	t.Helper()
+
+	f, err := hostsfile.ReadFile(strings.NewReader(data), testSource)
+	require.NoError(t, err)
+
+	return f
+}
+
+// writeFile is a helper that writes f into a string.
+func writeFile(t *testing.T, f *hostsfile.File) (data string) {
+	t.Helper()
+
+	b := &strings.Builder{}
+	n, err := f.WriteTo(b)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(b.Len()), n)
+
+	return b.String()
+}
+
+func TestFile_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		data string
+	}{{
+		name: "common",
+		data: testData,
+	}, {
+		name: "crlf",
+		data: "# Comment.\r\n127.0.0.1  localhost\r\n\r\nbad line\r\n",
+	}, {
+		name: "empty",
+		data: "",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := readFile(t, tc.data)
+			assert.Equal(t, tc.data, writeFile(t, f))
+		})
+	}
+}
+
+func TestFile_modify(t *testing.T) {
+	t.Parallel()
+
+	const data = "# Hosts.\r\n" +
+		"127.0.0.1\tlocalhost # Loopback.\r\n" +
+		"192.0.2.1 old.example\r\n" +
+		"bad\r\n" +
+		"192.0.2.2 keep.example"
+
+	f := readFile(t, data)
+	require.Len(t, f.Errors(), 1)
+	testutil.AssertErrorMsg(t, `/etc/hosts:4: bad address: ParseAddr("bad"): unable to parse IP`, f.Errors()[0])
+
+	n, err := f.Update(netip.MustParseAddr("127.0.0.1"), "localhost", "loopback")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 1, f.Remove(func(rec *hostsfile.Record) (ok bool) {
+		return rec.Names[0] == "old.example"
+	}))
+
+	err = f.Add(netip.MustParseAddr("192.0.2.3"), "new.example")
+	require.NoError(t, err)
+
+	// Bad names are rejected and don't change the file.
+	err = f.Add(netip.MustParseAddr("192.0.2.4"))
+	assert.ErrorIs(t, err, hostsfile.ErrNoHosts)
+
+	err = f.Add(netip.MustParseAddr("192.0.2.4"), "")
+	assert.Error(t, err)
+
+	n, err = f.Update(netip.MustParseAddr("192.0.2.2"), "bad name")
+	assert.Error(t, err)
+	assert.Zero(t, n)
+
+	assert.Equal(t, "# Hosts.\r\n"+
+		"127.0.0.1 localhost loopback # Loopback.\r\n"+
+		"bad\r\n"+
+		"192.0.2.2 keep.example\r\n"+
+		"192.0.2.3 new.example\r\n", writeFile(t, f))
+}
+
+func TestFile_SetBlock(t *testing.T) {
+	t.Parallel()
+
+	const data = "127.0.0.1 localhost\n"
+
+	f := readFile(t, data)
+	err := f.SetBlock("tool", []*hostsfile.Record{{
+		Addr:  netip.MustParseAddr("192.0.2.1"),
+		Names: []string{"a.example"},
+	}})
+	require.NoError(t, err)
+
+	const withBlock = data + "# BEGIN tool\n192.0.2.1 a.example\n# END tool\n"
+	assert.Equal(t, withBlock, writeFile(t, f))
+
+	f = readFile(t, withBlock+"::1 localhost6\n")
+	err = f.SetBlock("tool", []*hostsfile.Record{{
+		Addr:  netip.MustParseAddr("192.0.2.2"),
+		Names: []string{"b.example", "c.example"},
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, data+
+		"# BEGIN tool\n192.0.2.2 b.example c.example\n# END tool\n"+
+		"::1 localhost6\n", writeFile(t, f))
+
+	err = f.SetBlock("tool", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, data+"::1 localhost6\n", writeFile(t, f))
+}
+
+func TestFile_SetBlock_unterminated(t *testing.T) {
+	t.Parallel()
+
+	const data = "# BEGIN x\n127.0.0.1 user1\n127.0.0.2 user2\n"
+
+	f := readFile(t, data)
+	recs := []*hostsfile.Record{{
+		Addr:  netip.MustParseAddr("192.0.2.1"),
+		Names: []string{"a.example"},
+	}}
+
+	for i := 0; i < 2; i++ {
+		err := f.SetBlock("x", recs)
+		assert.ErrorIs(t, err, hostsfile.ErrUnterminatedBlock)
+		assert.Equal(t, data, writeFile(t, f))
+	}
+}
+
+func TestFile_SetBlock_badRecords(t *testing.T) {
+	t.Parallel()
+
+	const data = "127.0.0.1 localhost\n"
+
+	testCases := []struct {
+		rec        *hostsfile.Record
+		name       string
+		wantErrMsg string
+	}{{
+		rec: &hostsfile.Record{
+			Addr:  netip.MustParseAddr("1.2.3.4"),
+			Names: []string{"bad name!"},
+		},
+		name: "bad_name",
+		wantErrMsg: `record at index 1: name at index 0: bad domain name "bad name!": ` +
+			`bad domain name label "bad name!": ` +
+			`bad domain name label rune ' '`,
+	}, {
+		rec: &hostsfile.Record{
+			Addr: netip.MustParseAddr("1.2.3.5"),
+		},
+		name:       "no_names",
+		wantErrMsg: "record at index 1: no hostnames",
+	}, {
+		rec: &hostsfile.Record{
+			Names: []string{"a.example"},
+		},
+		name:       "bad_addr",
+		wantErrMsg: "record at index 1: invalid address",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := readFile(t, data)
+			err := f.SetBlock("tool", []*hostsfile.Record{{
+				Addr:  netip.MustParseAddr("192.0.2.1"),
+				Names: []string{"a.example"},
+			}, tc.rec})
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, err)
+
+			assert.Equal(t, data, writeFile(t, f))
+		})
+	}
+}
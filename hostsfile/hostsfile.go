@@ -0,0 +1,80 @@
+// Package hostsfile contains utilities for working with the hosts files, which
+// map IP addresses to hostnames.
+//
+// See man 5 hosts.
+package hostsfile
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+const (
+	// ErrNoHosts is returned when a record has an address but no hostnames.
+	ErrNoHosts errors.Error = "no hostnames"
+
+	// ErrLineTooLong is returned when a line is longer than MaxLineLen.
+	ErrLineTooLong errors.Error = "line is too long"
+
+	// ErrUnterminatedBlock is returned by File.SetBlock when the file contains
+	// the beginning line of the block but no ending line after it.
+	ErrUnterminatedBlock errors.Error = "block has no end line"
+)
+
+// MaxLineLen is the maximum length of a line in a hosts file, in bytes,
+// including the comments.
+const MaxLineLen = 64 * 1024
+
+// Record is a single record of a hosts file.
+type Record struct {
+	// Addr is the IP address of the record.
+	Addr netip.Addr
+
+	// Source is the name of the source of the record, usually the path to the
+	// file.
+	Source string
+
+	// Names are the hostnames of the record, the first one of which is the
+	// canonical one and the rest are the aliases.  It is never empty.
+	Names []string
+
+	// Line is the 1-based number of the line of the record in the source.
+	Line int
+}
+
+// String returns the record formatted as a hosts file line, without the
+// trailing newline.
+func (rec *Record) String() (s string) {
+	return rec.Addr.String() + " " + strings.Join(rec.Names, " ")
+}
+
+// LineError is the underlying type of errors returned for the invalid lines of
+// a hosts file.
+type LineError struct {
+	// Err is the underlying error.
+	Err error
+
+	// Source is the name of the source of the line.
+	Source string
+
+	// Line is the 1-based number of the line.
+	Line int
+}
+
+// Error implements the error interface for *LineError.
+func (err *LineError) Error() (msg string) {
+	if err.Source == "" {
+		return fmt.Sprintf("line %d: %s", err.Line, err.Err)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", err.Source, err.Line, err.Err)
+}
+
+// Unwrap implements the errors.Wrapper interface for *LineError.  It returns
+// err.Err.
+func (err *LineError) Unwrap() (unwrapped error) {
+	return err.Err
+}
@@ -0,0 +1,47 @@
+package hostsfile
+
+import (
+	"fmt"
+	"io/fs"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// ParseFiles parses the files at paths from fsys and merges their records in
+// the order of paths.  The Source field of each record is the path of its
+// file.  If fsys is nil, the files are read from the operating system's file
+// system, and the paths may be absolute.  errs contains the errors of opening
+// and reading the files as well as the errors of the invalid lines, so that a
+// single missing or broken file doesn't prevent using the others.
+func ParseFiles(fsys fs.FS, paths ...string) (recs []*Record, errs []error) {
+	if fsys == nil {
+		fsys = osFS{}
+	}
+
+	c := &Collector{}
+	for _, p := range paths {
+		err := parseFile(c, fsys, p)
+		if err != nil {
+			c.Errors = append(c.Errors, err)
+		}
+	}
+
+	return c.Records, c.Errors
+}
+
+// parseFile parses a single file at p from fsys into c.
+func parseFile(c *Collector, fsys fs.FS, p string) (err error) {
+	f, err := fsys.Open(p)
+	if err != nil {
+		return fmt.Errorf("opening hosts file: %w", err)
+	}
+	defer func() { err = errors.WithDeferred(err, f.Close()) }()
+
+	return Parse(c, f, p)
+}
+
+// ParseDefault parses and merges the hosts files at DefaultHostsPaths in the
+// operating system's file system.  See ParseFiles.
+func ParseDefault() (recs []*Record, errs []error) {
+	return ParseFiles(nil, DefaultHostsPaths()...)
+}
@@ -0,0 +1,44 @@
+package hostsfile_test
+
+import (
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/hostsfile"
+	"github.com/AdguardTeam/golibs/testutil/fakefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFiles(t *testing.T) {
+	t.Parallel()
+
+	fsys := fakefs.New()
+	require.NoError(t, fsys.WriteFile("a", []byte("127.0.0.1 localhost\nbad\n"), 0o644))
+	require.NoError(t, fsys.WriteFile("b", []byte("192.0.2.1 host.example\n"), 0o644))
+
+	recs, errs := hostsfile.ParseFiles(fsys, "a", "missing", "b")
+	require.Len(t, recs, 2)
+
+	assert.Equal(t, "a", recs[0].Source)
+	assert.Equal(t, []string{"localhost"}, recs[0].Names)
+	assert.Equal(t, "b", recs[1].Source)
+	assert.Equal(t, []string{"host.example"}, recs[1].Names)
+
+	require.Len(t, errs, 2)
+
+	assert.ErrorAs(t, errs[0], new(*hostsfile.LineError))
+	assert.ErrorIs(t, errs[1], fs.ErrNotExist)
+}
+
+func TestDefaultHostsPaths(t *testing.T) {
+	t.Parallel()
+
+	paths := hostsfile.DefaultHostsPaths()
+	require.NotEmpty(t, paths)
+
+	for _, p := range paths {
+		assert.True(t, filepath.IsAbs(p), p)
+	}
+}
@@ -0,0 +1,171 @@
+package hostsfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/netutil"
+)
+
+// Handler handles the results of parsing a hosts file.
+type Handler interface {
+	// HandleRecord is called for every valid record.  If it returns an error,
+	// parsing is stopped and the error is returned from Parse.  rec must not
+	// be retained after the call.
+	HandleRecord(rec *Record) (err error)
+
+	// HandleInvalid is called for every invalid line.  err has the underlying
+	// type of *LineError.
+	HandleInvalid(err error)
+}
+
+// Collector is a Handler that collects all records and errors.  Its zero value
+// is ready for use.
+type Collector struct {
+	// Records are the valid records in the order of their appearance.
+	Records []*Record
+
+	// Errors are the errors for the invalid lines, all of which have the
+	// underlying type of *LineError.
+	Errors []error
+}
+
+// type check
+var _ Handler = (*Collector)(nil)
+
+// HandleRecord implements the Handler interface for *Collector.
+func (c *Collector) HandleRecord(rec *Record) (err error) {
+	clone := *rec
+	clone.Names = append([]string(nil), rec.Names...)
+	c.Records = append(c.Records, &clone)
+
+	return nil
+}
+
+// HandleInvalid implements the Handler interface for *Collector.
+func (c *Collector) HandleInvalid(err error) {
+	c.Errors = append(c.Errors, err)
+}
+
+// Parse reads hosts file data from src and passes the results to h line by
+// line, so that the memory used doesn't depend on the size of the data.  Empty
+// lines and comments are skipped.  Invalid lines are reported to h and don't
+// stop parsing.  source is used as the source name of the records and errors.
+// err is only returned for the errors of reading src and the errors returned
+// by h.
+func Parse(h Handler, src io.Reader, source string) (err error) {
+	r := bufio.NewReaderSize(src, MaxLineLen)
+	rec := &Record{
+		Source: source,
+	}
+
+	for lineNum := 1; ; lineNum++ {
+		var line []byte
+		line, err = readLine(r)
+		switch {
+		case err == nil:
+			err = parseLine(rec, line)
+		case errors.Is(err, io.EOF):
+			return nil
+		case errors.Is(err, ErrLineTooLong):
+			// Report it below.
+		default:
+			return fmt.Errorf("reading %s: %w", source, err)
+		}
+
+		if err != nil {
+			h.HandleInvalid(&LineError{
+				Err:    err,
+				Source: source,
+				Line:   lineNum,
+			})
+
+			continue
+		} else if rec.Names == nil {
+			// An empty line or a comment.
+			continue
+		}
+
+		rec.Line = lineNum
+		err = h.HandleRecord(rec)
+		if err != nil {
+			return fmt.Errorf("handling record at line %d: %w", lineNum, err)
+		}
+	}
+}
+
+// readLine reads a single line from r without the line terminator.  If the
+// line is longer than MaxLineLen, the rest of it is skipped and err is
+// ErrLineTooLong.  At the end of the data, err is io.EOF and line is nil.
+func readLine(r *bufio.Reader) (line []byte, err error) {
+	line, err = r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		for errors.Is(err, bufio.ErrBufferFull) {
+			_, err = r.ReadSlice('\n')
+		}
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+
+		return nil, ErrLineTooLong
+	} else if errors.Is(err, io.EOF) {
+		if len(line) == 0 {
+			return nil, io.EOF
+		}
+
+		// The last line without a terminator.
+		err = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	line = bytes.TrimSuffix(line, []byte("\n"))
+
+	return bytes.TrimSuffix(line, []byte("\r")), nil
+}
+
+// parseLine parses a single line into rec, reusing its Names slice.  If the
+// line is empty or a comment, rec.Names is nil.
+func parseLine(rec *Record, line []byte) (err error) {
+	rec.Names = rec.Names[:0]
+	if i := bytes.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		rec.Names = nil
+
+		return nil
+	}
+
+	rec.Addr, err = netip.ParseAddr(fields[0])
+	if err != nil {
+		rec.Names = nil
+
+		return fmt.Errorf("bad address: %w", err)
+	} else if len(fields) == 1 {
+		rec.Names = nil
+
+		return ErrNoHosts
+	}
+
+	for _, name := range fields[1:] {
+		err = netutil.ValidateDomainName(name)
+		if err != nil {
+			rec.Names = nil
+
+			return err
+		}
+
+		rec.Names = append(rec.Names, name)
+	}
+
+	return nil
+}
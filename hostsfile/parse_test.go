@@ -0,0 +1,128 @@
+package hostsfile_test
+
+import (
+	"io"
+	"net/netip"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/hostsfile"
+	"github.com/AdguardTeam/golibs/netutil"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSource is the source name for tests.
+const testSource = "/etc/hosts"
+
+// testData is the common hosts file data for tests.
+const testData = `# Comment.
+127.0.0.1	localhost  # The local host.
+
+::1 localhost ip6-localhost ip6-loopback
+192.0.2.1 host.example
+192.0.2.2
+not_an_ip host.example
+192.0.2.3 bad_host
+fe80::1%lo0 link-local
+192.0.2.4 last.example`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	c := &hostsfile.Collector{}
+	err := hostsfile.Parse(c, strings.NewReader(testData), testSource)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*hostsfile.Record{{
+		Addr:   netip.MustParseAddr("127.0.0.1"),
+		Source: testSource,
+		Names:  []string{"localhost"},
+		Line:   2,
+	}, {
+		Addr:   netip.MustParseAddr("::1"),
+		Source: testSource,
+		Names:  []string{"localhost", "ip6-localhost", "ip6-loopback"},
+		Line:   4,
+	}, {
+		Addr:   netip.MustParseAddr("192.0.2.1"),
+		Source: testSource,
+		Names:  []string{"host.example"},
+		Line:   5,
+	}, {
+		Addr:   netip.MustParseAddr("fe80::1%lo0"),
+		Source: testSource,
+		Names:  []string{"link-local"},
+		Line:   9,
+	}, {
+		Addr:   netip.MustParseAddr("192.0.2.4"),
+		Source: testSource,
+		Names:  []string{"last.example"},
+		Line:   10,
+	}}, c.Records)
+
+	require.Len(t, c.Errors, 3)
+
+	testutil.AssertError(t, &testutil.ErrorAssertion{
+		Is:  []error{hostsfile.ErrNoHosts},
+		As:  []any{new(*hostsfile.LineError)},
+		Msg: "/etc/hosts:6: no hostnames",
+	}, c.Errors[0])
+
+	testutil.AssertErrorMsg(
+		t,
+		`/etc/hosts:7: bad address: ParseAddr("not_an_ip"): unable to parse IP`,
+		c.Errors[1],
+	)
+
+	testutil.AssertError(t, &testutil.ErrorAssertion{
+		As: []any{new(*netutil.AddrError)},
+		Msg: `/etc/hosts:8: bad domain name "bad_host": ` +
+			`bad domain name label "bad_host": bad domain name label rune '_'`,
+	}, c.Errors[2])
+}
+
+func TestParse_longLine(t *testing.T) {
+	t.Parallel()
+
+	data := "192.0.2.1 " + strings.Repeat("a", hostsfile.MaxLineLen) + "\r\n192.0.2.2 host.example\r\n"
+
+	c := &hostsfile.Collector{}
+	err := hostsfile.Parse(c, strings.NewReader(data), "")
+	require.NoError(t, err)
+
+	require.Len(t, c.Records, 1)
+	assert.Equal(t, []string{"host.example"}, c.Records[0].Names)
+	assert.Equal(t, 2, c.Records[0].Line)
+
+	require.Len(t, c.Errors, 1)
+	testutil.AssertErrorMsg(t, "line 1: line is too long", c.Errors[0])
+}
+
+// errHandler is a hostsfile.Handler that returns an error for every record.
+type errHandler struct {
+	err error
+}
+
+// HandleRecord implements the hostsfile.Handler interface for errHandler.
+func (h errHandler) HandleRecord(_ *hostsfile.Record) (err error) { return h.err }
+
+// HandleInvalid implements the hostsfile.Handler interface for errHandler.
+func (errHandler) HandleInvalid(_ error) {}
+
+func TestParse_errors(t *testing.T) {
+	t.Parallel()
+
+	const testErr errors.Error = "test error"
+
+	err := hostsfile.Parse(errHandler{err: testErr}, strings.NewReader(testData), testSource)
+	assert.ErrorIs(t, err, testErr)
+	testutil.AssertErrorMsg(t, "handling record at line 2: test error", err)
+
+	r := io.MultiReader(strings.NewReader("127.0.0.1 localhost\n"), iotest.ErrReader(testErr))
+	err = hostsfile.Parse(&hostsfile.Collector{}, r, testSource)
+	testutil.AssertErrorMsg(t, "reading /etc/hosts: test error", err)
+}
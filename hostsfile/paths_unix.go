@@ -0,0 +1,10 @@
+//go:build !windows
+// +build !windows
+
+package hostsfile
+
+// DefaultHostsPaths returns the conventional paths of the system hosts files
+// on the current operating system.
+func DefaultHostsPaths() (paths []string) {
+	return []string{"/etc/hosts"}
+}
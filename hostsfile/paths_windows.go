@@ -0,0 +1,21 @@
+//go:build windows
+// +build windows
+
+package hostsfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DefaultHostsPaths returns the conventional paths of the system hosts files
+// on the current operating system.  On Windows, it is
+// %SystemRoot%\System32\drivers\etc\hosts.
+func DefaultHostsPaths() (paths []string) {
+	root := os.Getenv("SystemRoot")
+	if root == "" {
+		root = `C:\Windows`
+	}
+
+	return []string{filepath.Join(root, "System32", "drivers", "etc", "hosts")}
+}
@@ -0,0 +1,335 @@
+package hostsfile
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/golibs/service"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// EventSource is a source of file change notifications, for example one based
+// on fsnotify.  It should watch the directories of the files rather than the
+// files themselves to handle the editors that save files by renaming a
+// temporary file.
+type EventSource interface {
+	// Events returns the channel that receives a value whenever any of the
+	// watched files may have changed.  If the channel is closed, the *Watcher
+	// falls back to polling.
+	Events() (ch <-chan struct{})
+}
+
+// Subscriber is called with the records parsed from all watched files, in the
+// order of WatcherConfig.Paths, after every change.  errs contains the errors
+// of reading the files as well as the errors of the invalid lines.  recs and
+// errs must not be modified.
+type Subscriber func(recs []*Record, errs []error)
+
+// WatcherConfig is the configuration structure for a *Watcher.
+type WatcherConfig struct {
+	// FS is the file system containing the files.  If it is nil, the files
+	// are read from the operating system's file system, and the paths may be
+	// absolute.
+	FS fs.FS
+
+	// Clock is used for polling and debouncing.  If it is nil,
+	// timeutil.SystemClock is used.
+	Clock timeutil.Clock
+
+	// Events, if not nil, is used to detect changes instead of polling until
+	// its channel is closed.
+	Events EventSource
+
+	// Paths are the paths of the files to watch.  Missing files are treated
+	// as empty ones and are reported through the errors.
+	Paths []string
+
+	// PollInterval is the interval of checking the files for changes when
+	// Events is nil or closed.  If it is zero, DefaultPollInterval is used.
+	PollInterval time.Duration
+
+	// Debounce is the time to wait after the last detected change before
+	// re-parsing the files, so that a burst of writes causes a single update.
+	Debounce time.Duration
+}
+
+// DefaultPollInterval is the default interval of polling for changes.
+const DefaultPollInterval = 5 * time.Second
+
+// Watcher watches hosts files and notifies the subscribers with the new
+// records when the files change.  Without an EventSource, the changes are
+// detected by polling the size and the modification time of the files.
+type Watcher struct {
+	fsys     fs.FS
+	clock    timeutil.Clock
+	events   EventSource
+	paths    []string
+	poll     time.Duration
+	debounce time.Duration
+
+	// states are the last known states of the files.  It is only accessed
+	// from the watching goroutine after Start.
+	states map[string]fileState
+
+	// mu protects recs, errs, subs, and nextSubID.
+	mu        *sync.Mutex
+	recs      []*Record
+	errs      []error
+	subs      map[uint64]Subscriber
+	nextSubID uint64
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// fileState is the state of a file used to detect changes.
+type fileState struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+// NewWatcher returns a new properly initialized *Watcher.  c must not be nil.
+func NewWatcher(c *WatcherConfig) (w *Watcher) {
+	fsys := c.FS
+	if fsys == nil {
+		fsys = osFS{}
+	}
+
+	clock := c.Clock
+	if clock == nil {
+		clock = timeutil.SystemClock{}
+	}
+
+	poll := c.PollInterval
+	if poll == 0 {
+		poll = DefaultPollInterval
+	}
+
+	return &Watcher{
+		fsys:     fsys,
+		clock:    clock,
+		events:   c.Events,
+		paths:    append([]string(nil), c.Paths...),
+		poll:     poll,
+		debounce: c.Debounce,
+		states:   map[string]fileState{},
+		mu:       &sync.Mutex{},
+		subs:     map[uint64]Subscriber{},
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Subscribe adds f to the subscribers, which are called sequentially from the
+// watching goroutine.  unsubscribe removes it.
+func (w *Watcher) Subscribe(f Subscriber) (unsubscribe func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextSubID
+	w.nextSubID++
+	w.subs[id] = f
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		delete(w.subs, id)
+	}
+}
+
+// Records returns the records parsed most recently along with the errors.
+// recs and errs must not be modified.
+func (w *Watcher) Records() (recs []*Record, errs []error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.recs, w.errs
+}
+
+// type check
+var _ service.Interface = (*Watcher)(nil)
+
+// Start parses the files, notifies the subscribers, and starts watching for
+// changes in a separate goroutine.  It must only be called once.
+func (w *Watcher) Start(_ context.Context) (err error) {
+	w.changed()
+	w.reload()
+
+	go w.watch()
+
+	return nil
+}
+
+// Shutdown stops watching and waits for the watching goroutine to exit until
+// ctx is done.
+func (w *Watcher) Shutdown(ctx context.Context) (err error) {
+	close(w.done)
+
+	select {
+	case <-w.stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("shutting down hosts watcher: %w", ctx.Err())
+	}
+}
+
+// watch detects the changes and reloads the files until w is shut down.
+func (w *Watcher) watch() {
+	defer close(w.stopped)
+
+	l := &watchLoop{w: w}
+	defer l.cleanup()
+
+	if w.events != nil {
+		l.eventC = w.events.Events()
+	} else {
+		l.startPolling()
+	}
+
+	for l.step() {
+	}
+}
+
+// watchLoop is the state of the watching goroutine of a *Watcher.
+type watchLoop struct {
+	w *Watcher
+
+	// ticker is nil unless the files are polled.
+	ticker timeutil.Ticker
+
+	// debounce is nil until the first change is detected.
+	debounce timeutil.Timer
+
+	tickC     <-chan time.Time
+	eventC    <-chan struct{}
+	debounceC <-chan time.Time
+}
+
+// step waits for a single event and handles it.  ok is false if the watcher
+// has been shut down.
+func (l *watchLoop) step() (ok bool) {
+	changed := false
+	select {
+	case <-l.w.done:
+		return false
+	case <-l.tickC:
+		changed = l.w.changed()
+	case _, open := <-l.eventC:
+		if !open {
+			// Don't receive from the closed channel in a busy loop.  Reload
+			// the files, since some changes could have been missed.
+			l.eventC = nil
+			l.startPolling()
+		}
+
+		changed = true
+	case <-l.debounceC:
+		l.debounceC = nil
+		l.w.reload()
+	}
+
+	if changed {
+		l.resetDebounce()
+	}
+
+	return true
+}
+
+// startPolling starts the polling ticker.
+func (l *watchLoop) startPolling() {
+	l.ticker = l.w.clock.NewTicker(l.w.poll)
+	l.tickC = l.ticker.C()
+}
+
+// resetDebounce starts the debounce timer or restarts it if it's running.
+func (l *watchLoop) resetDebounce() {
+	if l.debounce == nil {
+		l.debounce = l.w.clock.NewTimer(l.w.debounce)
+	} else {
+		if !l.debounce.Stop() && l.debounceC != nil {
+			// Drain the channel, since the timer may have fired.
+			select {
+			case <-l.debounce.C():
+			default:
+			}
+		}
+
+		l.debounce.Reset(l.w.debounce)
+	}
+
+	l.debounceC = l.debounce.C()
+}
+
+// cleanup stops the timers.
+func (l *watchLoop) cleanup() {
+	if l.ticker != nil {
+		l.ticker.Stop()
+	}
+
+	if l.debounce != nil {
+		l.debounce.Stop()
+	}
+}
+
+// changed updates the states of the files and returns true if any of them has
+// changed.
+func (w *Watcher) changed() (ok bool) {
+	for _, p := range w.paths {
+		var st fileState
+		fi, err := fs.Stat(w.fsys, p)
+		if err == nil {
+			st = fileState{
+				modTime: fi.ModTime(),
+				size:    fi.Size(),
+				exists:  true,
+			}
+		}
+
+		if prev, has := w.states[p]; !has || prev != st {
+			w.states[p] = st
+			ok = true
+		}
+	}
+
+	return ok
+}
+
+// reload parses all files and notifies the subscribers.
+func (w *Watcher) reload() {
+	recs, errs := ParseFiles(w.fsys, w.paths...)
+
+	w.mu.Lock()
+	w.recs, w.errs = recs, errs
+	subs := make([]Subscriber, 0, len(w.subs))
+	for _, s := range w.subs {
+		subs = append(subs, s)
+	}
+	w.mu.Unlock()
+
+	for _, s := range subs {
+		s(recs, errs)
+	}
+}
+
+// osFS is an fs.FS that opens files in the operating system's file system
+// without the restrictions of fs.ValidPath, so that absolute paths can be used.
+type osFS struct{}
+
+// type check
+var _ fs.StatFS = osFS{}
+
+// Open implements the fs.FS interface for osFS.
+func (osFS) Open(name string) (f fs.File, err error) {
+	return os.Open(name)
+}
+
+// Stat implements the fs.StatFS interface for osFS.
+func (osFS) Stat(name string) (fi fs.FileInfo, err error) {
+	return os.Stat(name)
+}
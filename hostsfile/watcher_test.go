@@ -0,0 +1,183 @@
+package hostsfile_test
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/hostsfile"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/AdguardTeam/golibs/testutil/fakefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTimeout is the common timeout for tests.
+const testTimeout = 1 * time.Second
+
+// update is the data passed to a hostsfile.Subscriber.
+type update struct {
+	recs []*hostsfile.Record
+	errs []error
+}
+
+// newSubscriber returns a subscriber that sends updates to the returned
+// channel.
+func newSubscriber() (s hostsfile.Subscriber, ch chan update) {
+	ch = make(chan update, 10)
+
+	return func(recs []*hostsfile.Record, errs []error) {
+		ch <- update{recs: recs, errs: errs}
+	}, ch
+}
+
+// receiveUpdate returns the next update from ch or fails the test.
+func receiveUpdate(t *testing.T, ch <-chan update) (u update) {
+	t.Helper()
+
+	select {
+	case u = <-ch:
+		return u
+	case <-time.After(testTimeout):
+		t.Fatal("no update")
+
+		return update{}
+	}
+}
+
+// names returns the first names of all records.
+func names(recs []*hostsfile.Record) (res []string) {
+	for _, r := range recs {
+		res = append(res, r.Names[0])
+	}
+
+	return res
+}
+
+func TestWatcher_poll(t *testing.T) {
+	t.Parallel()
+
+	fsys := fakefs.New()
+	require.NoError(t, fsys.WriteFile("etc/hosts", []byte("127.0.0.1 localhost\n"), 0o644))
+
+	clock := testutil.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := hostsfile.NewWatcher(&hostsfile.WatcherConfig{
+		FS:           fsys,
+		Clock:        clock,
+		Paths:        []string{"etc/hosts", "etc/hosts.extra"},
+		PollInterval: time.Second,
+		Debounce:     2 * time.Second,
+	})
+
+	sub, ch := newSubscriber()
+	unsubscribe := w.Subscribe(sub)
+
+	ctx := context.Background()
+	require.NoError(t, w.Start(ctx))
+	testutil.CleanupAndRequireSuccess(t, func() (err error) { return w.Shutdown(ctx) })
+
+	u := receiveUpdate(t, ch)
+	assert.Equal(t, []string{"localhost"}, names(u.recs))
+
+	// The extra file is missing.
+	require.Len(t, u.errs, 1)
+	assert.ErrorIs(t, u.errs[0], fs.ErrNotExist)
+
+	err := fsys.WriteFile("etc/hosts.extra", []byte("192.0.2.1 host.example\n"), 0o644)
+	require.NoError(t, err)
+
+	// Wait for the ticker to detect the change and then for the debounce
+	// timer.
+	clock.AdvanceWhenBlocked(t, 1, time.Second)
+	clock.BlockUntil(t, 2)
+
+	clock.Advance(time.Second)
+	assert.Empty(t, ch)
+
+	clock.Advance(time.Second)
+	u = receiveUpdate(t, ch)
+	assert.Equal(t, []string{"localhost", "host.example"}, names(u.recs))
+	assert.Empty(t, u.errs)
+
+	recs, _ := w.Records()
+	assert.Equal(t, u.recs, recs)
+
+	unsubscribe()
+}
+
+// testEventSource is a hostsfile.EventSource for tests.
+type testEventSource chan struct{}
+
+// Events implements the hostsfile.EventSource interface for testEventSource.
+func (s testEventSource) Events() (ch <-chan struct{}) { return s }
+
+func TestWatcher_events(t *testing.T) {
+	t.Parallel()
+
+	fsys := fakefs.New()
+	require.NoError(t, fsys.WriteFile("hosts", []byte("127.0.0.1 localhost\n"), 0o644))
+
+	events := make(testEventSource)
+	w := hostsfile.NewWatcher(&hostsfile.WatcherConfig{
+		FS:     fsys,
+		Events: events,
+		Paths:  []string{"hosts"},
+	})
+
+	sub, ch := newSubscriber()
+	_ = w.Subscribe(sub)
+
+	ctx := context.Background()
+	require.NoError(t, w.Start(ctx))
+	testutil.CleanupAndRequireSuccess(t, func() (err error) { return w.Shutdown(ctx) })
+
+	u := receiveUpdate(t, ch)
+	assert.Equal(t, []string{"localhost"}, names(u.recs))
+
+	// Simulate an editor that saves the file by renaming a temporary one.
+	require.NoError(t, fsys.WriteFile("hosts.tmp", []byte("::1 localhost6\n"), 0o644))
+	require.NoError(t, fsys.Rename("hosts.tmp", "hosts"))
+	events <- struct{}{}
+
+	u = receiveUpdate(t, ch)
+	assert.Equal(t, []string{"localhost6"}, names(u.recs))
+}
+
+func TestWatcher_eventsClosed(t *testing.T) {
+	t.Parallel()
+
+	fsys := fakefs.New()
+	require.NoError(t, fsys.WriteFile("hosts", []byte("127.0.0.1 localhost\n"), 0o644))
+
+	events := make(testEventSource)
+	w := hostsfile.NewWatcher(&hostsfile.WatcherConfig{
+		FS:           fsys,
+		Events:       events,
+		Paths:        []string{"hosts"},
+		PollInterval: 10 * time.Millisecond,
+	})
+
+	sub, ch := newSubscriber()
+	_ = w.Subscribe(sub)
+
+	ctx := context.Background()
+	require.NoError(t, w.Start(ctx))
+	testutil.CleanupAndRequireSuccess(t, func() (err error) { return w.Shutdown(ctx) })
+
+	u := receiveUpdate(t, ch)
+	assert.Equal(t, []string{"localhost"}, names(u.recs))
+
+	// The files are reloaded once the event source is closed, since some
+	// changes could have been missed.
+	close(events)
+
+	u = receiveUpdate(t, ch)
+	assert.Equal(t, []string{"localhost"}, names(u.recs))
+
+	// The watcher falls back to polling.
+	require.NoError(t, fsys.WriteFile("hosts", []byte("::1 localhost6\n"), 0o644))
+
+	u = receiveUpdate(t, ch)
+	assert.Equal(t, []string{"localhost6"}, names(u.recs))
+}
@@ -0,0 +1,46 @@
+package httphdr
+
+import (
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+const (
+	// ErrEmptyElement is returned when a list header contains an empty
+	// element.
+	ErrEmptyElement errors.Error = "empty element"
+
+	// ErrMultipleValues is returned when a single-value header is sent more
+	// than once.
+	ErrMultipleValues errors.Error = "multiple values"
+
+	// ErrDuplicateParameter is returned when a parameter appears more than
+	// once in a single element of the Forwarded header.
+	ErrDuplicateParameter errors.Error = "duplicate parameter"
+)
+
+// HeaderError is the underlying type of errors returned from the parsers of
+// this package.
+type HeaderError struct {
+	// Err is the underlying error.
+	Err error
+
+	// Name is the canonical name of the header.
+	Name string
+
+	// Value is the full value of the header.  Multiple header lines are
+	// joined with ", ".
+	Value string
+}
+
+// Error implements the error interface for *HeaderError.
+func (err *HeaderError) Error() (msg string) {
+	return fmt.Sprintf("bad header %s value %q: %s", err.Name, err.Value, err.Err)
+}
+
+// Unwrap implements the errors.Wrapper interface for *HeaderError.  It returns
+// err.Err.
+func (err *HeaderError) Unwrap() (unwrapped error) {
+	return err.Err
+}
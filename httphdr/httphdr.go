@@ -0,0 +1,33 @@
+// Package httphdr contains HTTP header names and parsers for the values of
+// some of the headers.
+package httphdr
+
+// Common HTTP header names.
+const (
+	Accept          = "Accept"
+	Authorization   = "Authorization"
+	CacheControl    = "Cache-Control"
+	ContentEncoding = "Content-Encoding"
+	ContentLength   = "Content-Length"
+	ContentType     = "Content-Type"
+	Host            = "Host"
+	Location        = "Location"
+	Origin          = "Origin"
+	RetryAfter      = "Retry-After"
+	Server          = "Server"
+	UserAgent       = "User-Agent"
+
+	XContentTypeOptions = "X-Content-Type-Options"
+)
+
+// Proxy-related HTTP header names.
+const (
+	CFConnectingIP  = "CF-Connecting-IP"
+	Forwarded       = "Forwarded"
+	TrueClientIP    = "True-Client-IP"
+	XForwardedFor   = "X-Forwarded-For"
+	XForwardedHost  = "X-Forwarded-Host"
+	XForwardedProto = "X-Forwarded-Proto"
+	XRealIP         = "X-Real-IP"
+	XRequestID      = "X-Request-Id"
+)
@@ -0,0 +1,431 @@
+package httphdr
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// ParseXForwardedFor parses the X-Forwarded-For headers of h into a chain of
+// addresses, the leftmost of which is the one of the original client according
+// to the proxies.  Multiple headers are treated as a single comma-separated
+// list.  If there is no such header, addrs and err are nil.  err has the
+// underlying type of *HeaderError.
+//
+// Note that only the addresses added by the trusted proxies, which are the
+// rightmost ones, can be trusted.
+func ParseXForwardedFor(h http.Header) (addrs []netip.Addr, err error) {
+	vals := h.Values(XForwardedFor)
+	if len(vals) == 0 {
+		return nil, nil
+	}
+
+	val := strings.Join(vals, ", ")
+	defer wrapHeaderError(&err, XForwardedFor, val)
+
+	for i, s := range strings.Split(val, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, fmt.Errorf("element %d: %w", i, ErrEmptyElement)
+		}
+
+		var addr netip.Addr
+		addr, err = netip.ParseAddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+
+		addrs = append(addrs, addr)
+	}
+
+	return addrs, nil
+}
+
+// ParseXRealIP parses the X-Real-IP header of h.  If there is no such header,
+// addr is the zero value and err is nil.  err has the underlying type of
+// *HeaderError.
+func ParseXRealIP(h http.Header) (addr netip.Addr, err error) {
+	vals := h.Values(XRealIP)
+	if len(vals) == 0 {
+		return netip.Addr{}, nil
+	}
+
+	val := strings.Join(vals, ", ")
+	defer wrapHeaderError(&err, XRealIP, val)
+
+	if len(vals) > 1 {
+		return netip.Addr{}, ErrMultipleValues
+	}
+
+	return netip.ParseAddr(strings.TrimSpace(val))
+}
+
+// ForwardedNode is a node identifier from the Forwarded header.  See RFC 7239
+// Section 6.
+type ForwardedNode struct {
+	// Addr is the address of the node.  It is invalid if the identifier is
+	// "unknown" or obfuscated.
+	Addr netip.Addr
+
+	// Name is the identifier of the node if it is "unknown" or obfuscated,
+	// for example "_hidden".  It is empty if Addr is valid.
+	Name string
+
+	// Port is the port of the node, if there is a non-obfuscated one.
+	Port uint16
+}
+
+// IsZero returns true if n is empty, that is, the parameter is absent.
+func (n ForwardedNode) IsZero() (ok bool) {
+	return n == ForwardedNode{}
+}
+
+// ForwardedElement is a single element of the Forwarded header, which is added
+// by a single proxy.  See RFC 7239 Section 4.
+type ForwardedElement struct {
+	// For is the node which made the request to the proxy.
+	For ForwardedNode
+
+	// By is the interface of the proxy which received the request.
+	By ForwardedNode
+
+	// Host is the original value of the Host header.
+	Host string
+
+	// Proto is the protocol used to make the request, for example "https".
+	Proto string
+}
+
+// ParseForwarded parses the Forwarded headers of h.  The first element is the
+// one added by the first proxy.  Multiple headers are treated as a single
+// comma-separated list.  Unknown parameters are ignored.  If there is no such
+// header, elems and err are nil.  err has the underlying type of *HeaderError.
+//
+// Note that only the elements added by the trusted proxies, which are the
+// rightmost ones, can be trusted.
+func ParseForwarded(h http.Header) (elems []ForwardedElement, err error) {
+	vals := h.Values(Forwarded)
+	if len(vals) == 0 {
+		return nil, nil
+	}
+
+	val := strings.Join(vals, ", ")
+	defer wrapHeaderError(&err, Forwarded, val)
+
+	p := &forwardedParser{s: val}
+	for i := 0; ; i++ {
+		var e ForwardedElement
+		e, err = p.element()
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+
+		elems = append(elems, e)
+		if p.done() {
+			return elems, nil
+		}
+
+		// The only other way for an element to end is a comma.
+		p.pos++
+	}
+}
+
+// wrapHeaderError is a deferrable helper that wraps the error in errPtr, if
+// any, into a *HeaderError.  errPtr must be non-nil.
+func wrapHeaderError(errPtr *error, name, val string) {
+	err := *errPtr
+	if err == nil {
+		return
+	}
+
+	*errPtr = &HeaderError{
+		Err:   err,
+		Name:  name,
+		Value: val,
+	}
+}
+
+// forwardedParser is a parser for the value of the Forwarded header.
+type forwardedParser struct {
+	s   string
+	pos int
+}
+
+// done returns true if the whole value has been parsed.
+func (p *forwardedParser) done() (ok bool) {
+	return p.pos >= len(p.s)
+}
+
+// skipSpace skips optional whitespace.
+func (p *forwardedParser) skipSpace() {
+	for !p.done() && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// element parses a single forwarded-element up to the next comma or the end
+// of the value.
+func (p *forwardedParser) element() (e ForwardedElement, err error) {
+	seen := map[string]struct{}{}
+	for {
+		p.skipSpace()
+		if p.done() || p.s[p.pos] == ',' {
+			if len(seen) == 0 {
+				return e, ErrEmptyElement
+			}
+
+			return e, nil
+		}
+
+		err = p.param(&e, seen)
+		if err != nil {
+			return e, err
+		}
+
+		err = p.paramEnd()
+		if err != nil {
+			return e, err
+		}
+	}
+}
+
+// param parses a single parameter and sets it in e.  seen contains the names
+// of the parameters of e that have already been parsed.
+func (p *forwardedParser) param(e *ForwardedElement, seen map[string]struct{}) (err error) {
+	name, val, err := p.pair()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := seen[name]; ok {
+		return fmt.Errorf("parameter %q: %w", name, ErrDuplicateParameter)
+	}
+
+	seen[name] = struct{}{}
+	err = e.set(name, val)
+	if err != nil {
+		return fmt.Errorf("parameter %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// paramEnd skips the optional whitespace and the semicolon after a parameter.
+// The comma or the end of the value are left for element to handle.
+func (p *forwardedParser) paramEnd() (err error) {
+	p.skipSpace()
+	if p.done() || p.s[p.pos] == ',' {
+		return nil
+	} else if p.s[p.pos] == ';' {
+		p.pos++
+
+		return nil
+	}
+
+	return fmt.Errorf("unexpected character %q at index %d", p.s[p.pos], p.pos)
+}
+
+// pair parses a single name=value pair.  name is returned in lower case.
+func (p *forwardedParser) pair() (name, val string, err error) {
+	name = p.token()
+	if name == "" {
+		return "", "", fmt.Errorf("expected parameter name at index %d", p.pos)
+	}
+
+	if p.done() || p.s[p.pos] != '=' {
+		return "", "", fmt.Errorf("parameter %q: expected '=' at index %d", name, p.pos)
+	}
+
+	p.pos++
+	if !p.done() && p.s[p.pos] == '"' {
+		val, err = p.quoted()
+	} else {
+		val = p.token()
+		if val == "" {
+			err = errors.Error("empty value")
+		}
+	}
+
+	if err != nil {
+		return "", "", fmt.Errorf("parameter %q: %w", name, err)
+	}
+
+	return strings.ToLower(name), val, nil
+}
+
+// token parses an RFC 9110 token.
+func (p *forwardedParser) token() (tok string) {
+	start := p.pos
+	for !p.done() && isTokenChar(p.s[p.pos]) {
+		p.pos++
+	}
+
+	return p.s[start:p.pos]
+}
+
+// quoted parses an RFC 9110 quoted-string, unescaping the quoted pairs.
+func (p *forwardedParser) quoted() (val string, err error) {
+	start := p.pos
+	p.pos++
+
+	b := &strings.Builder{}
+	for !p.done() {
+		c := p.s[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			if p.done() {
+				break
+			}
+
+			c = p.s[p.pos]
+			p.pos++
+		}
+
+		_ = b.WriteByte(c)
+	}
+
+	return "", fmt.Errorf("unterminated quoted string at index %d", start)
+}
+
+// isTokenChar returns true if c is a tchar as defined in RFC 9110.
+func isTokenChar(c byte) (ok bool) {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	default:
+		return strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
+	}
+}
+
+// set sets the known parameter name to val.  name must be in lower case.
+func (e *ForwardedElement) set(name, val string) (err error) {
+	switch name {
+	case "for":
+		e.For, err = parseForwardedNode(val)
+	case "by":
+		e.By, err = parseForwardedNode(val)
+	case "host":
+		e.Host = val
+	case "proto":
+		e.Proto = strings.ToLower(val)
+	default:
+		// Ignore the extensions.
+	}
+
+	return err
+}
+
+// parseForwardedNode parses a node identifier as defined in RFC 7239 Section
+// 6.
+func parseForwardedNode(s string) (n ForwardedNode, err error) {
+	defer func() { err = errors.Annotate(err, "bad node %q: %w", s) }()
+
+	var port string
+	if strings.HasPrefix(s, "[") {
+		n, port, err = parseBracketedNode(s)
+	} else {
+		n, port, err = parseUnbracketedNode(s)
+	}
+
+	if err != nil {
+		return ForwardedNode{}, err
+	}
+
+	if port == "" {
+		return n, nil
+	} else if port[0] != ':' {
+		return ForwardedNode{}, fmt.Errorf("unexpected %q after address", port)
+	}
+
+	n.Port, err = parseForwardedPort(port[1:])
+
+	return n, err
+}
+
+// parseBracketedNode parses a node identifier with a bracketed IPv6 address.
+// rest is the part of s after the closing bracket.
+func parseBracketedNode(s string) (n ForwardedNode, rest string, err error) {
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return n, "", errors.Error("missing ']'")
+	}
+
+	n.Addr, err = netip.ParseAddr(s[1:end])
+	if err != nil {
+		return n, "", err
+	} else if !n.Addr.Is6() {
+		return n, "", errors.Error("bracketed address is not ipv6")
+	}
+
+	return n, s[end+1:], nil
+}
+
+// parseUnbracketedNode parses a node identifier with an IPv4 address or an
+// obfuscated or unknown name.  rest is the part of s starting with the colon
+// before the port, if any.
+func parseUnbracketedNode(s string) (n ForwardedNode, rest string, err error) {
+	host := s
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		host, rest = s[:i], s[i:]
+	}
+
+	if host == "unknown" || strings.HasPrefix(host, "_") {
+		n.Name = host
+
+		return n, rest, validateObfuscated(host)
+	}
+
+	n.Addr, err = netip.ParseAddr(host)
+	if err != nil {
+		return n, "", err
+	} else if !n.Addr.Is4() {
+		return n, "", errors.Error("ipv6 address must be bracketed")
+	}
+
+	return n, rest, nil
+}
+
+// parseForwardedPort parses a node port, which may be obfuscated, in which
+// case port is zero.
+func parseForwardedPort(s string) (port uint16, err error) {
+	if strings.HasPrefix(s, "_") {
+		return 0, validateObfuscated(s)
+	}
+
+	p, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("bad port: %w", err)
+	}
+
+	return uint16(p), nil
+}
+
+// validateObfuscated returns an error if s contains characters not allowed in
+// an obfuscated identifier.
+func validateObfuscated(s string) (err error) {
+	for _, c := range []byte(s) {
+		if !isObfuscatedChar(c) {
+			return fmt.Errorf("bad identifier character %q", c)
+		}
+	}
+
+	return nil
+}
+
+// isObfuscatedChar returns true if c is allowed in an obfuscated identifier as
+// defined in RFC 7239 Section 6.3.
+func isObfuscatedChar(c byte) (ok bool) {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	default:
+		return c == '.' || c == '_' || c == '-'
+	}
+}
@@ -0,0 +1,206 @@
+package httphdr_test
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// Common addresses for tests.
+var (
+	testIPv4      = netip.MustParseAddr("192.0.2.1")
+	testIPv4Proxy = netip.MustParseAddr("198.51.100.1")
+	testIPv6      = netip.MustParseAddr("2001:db8::1")
+)
+
+// newHeader returns a header with all vals set for name.
+func newHeader(name string, vals ...string) (h http.Header) {
+	h = http.Header{}
+	for _, v := range vals {
+		h.Add(name, v)
+	}
+
+	return h
+}
+
+func TestParseXForwardedFor(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		wantErrMsg string
+		vals       []string
+		want       []netip.Addr
+	}{{
+		name:       "none",
+		wantErrMsg: "",
+		vals:       nil,
+		want:       nil,
+	}, {
+		name:       "single",
+		wantErrMsg: "",
+		vals:       []string{"192.0.2.1"},
+		want:       []netip.Addr{testIPv4},
+	}, {
+		name:       "chain",
+		wantErrMsg: "",
+		vals:       []string{"192.0.2.1 ,2001:db8::1", "198.51.100.1"},
+		want:       []netip.Addr{testIPv4, testIPv6, testIPv4Proxy},
+	}, {
+		name: "empty",
+		wantErrMsg: `bad header X-Forwarded-For value "192.0.2.1,,198.51.100.1": ` +
+			`element 1: empty element`,
+		vals: []string{"192.0.2.1,,198.51.100.1"},
+		want: nil,
+	}, {
+		name: "bad_addr",
+		wantErrMsg: `bad header X-Forwarded-For value "192.0.2.1:80": ` +
+			`element 0: ParseAddr("192.0.2.1:80"): unexpected character (at ":80")`,
+		vals: []string{"192.0.2.1:80"},
+		want: nil,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			addrs, err := httphdr.ParseXForwardedFor(newHeader(httphdr.XForwardedFor, tc.vals...))
+			testutil.AssertErrorChain(t, tc.wantErrMsg, err, new(*httphdr.HeaderError))
+
+			assert.Equal(t, tc.want, addrs)
+		})
+	}
+}
+
+func TestParseXRealIP(t *testing.T) {
+	t.Parallel()
+
+	addr, err := httphdr.ParseXRealIP(http.Header{})
+	assert.NoError(t, err)
+	assert.False(t, addr.IsValid())
+
+	addr, err = httphdr.ParseXRealIP(newHeader(httphdr.XRealIP, " 2001:db8::1 "))
+	assert.NoError(t, err)
+	assert.Equal(t, testIPv6, addr)
+
+	_, err = httphdr.ParseXRealIP(newHeader(httphdr.XRealIP, "192.0.2.1", "192.0.2.2"))
+	testutil.AssertError(t, &testutil.ErrorAssertion{
+		Is:  []error{httphdr.ErrMultipleValues},
+		As:  []any{new(*httphdr.HeaderError)},
+		Msg: `bad header X-Real-IP value "192.0.2.1, 192.0.2.2": multiple values`,
+	}, err)
+}
+
+func TestParseForwarded(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		wantErrMsg string
+		vals       []string
+		want       []httphdr.ForwardedElement
+	}{{
+		name:       "none",
+		wantErrMsg: "",
+		vals:       nil,
+		want:       nil,
+	}, {
+		name:       "simple",
+		wantErrMsg: "",
+		vals:       []string{"for=192.0.2.1"},
+		want: []httphdr.ForwardedElement{{
+			For: httphdr.ForwardedNode{Addr: testIPv4},
+		}},
+	}, {
+		name:       "full",
+		wantErrMsg: "",
+		vals: []string{
+			`For="[2001:db8::1]:4711";proto=HTTPS;by=_proxy;host="example.com"`,
+			`for="192.0.2.1:80", for=unknown;ext="a,b;c"`,
+		},
+		want: []httphdr.ForwardedElement{{
+			For:   httphdr.ForwardedNode{Addr: testIPv6, Port: 4711},
+			By:    httphdr.ForwardedNode{Name: "_proxy"},
+			Host:  "example.com",
+			Proto: "https",
+		}, {
+			For: httphdr.ForwardedNode{Addr: testIPv4, Port: 80},
+		}, {
+			For: httphdr.ForwardedNode{Name: "unknown"},
+		}},
+	}, {
+		name:       "obfuscated_port",
+		wantErrMsg: "",
+		vals:       []string{`for="192.0.2.1:_p1"`},
+		want: []httphdr.ForwardedElement{{
+			For: httphdr.ForwardedNode{Addr: testIPv4},
+		}},
+	}, {
+		name: "unbracketed_ipv6",
+		wantErrMsg: `bad header Forwarded value "for=\"2001:db8::1\"": element 0: ` +
+			`parameter "for": bad node "2001:db8::1": ` +
+			`ParseAddr("2001"): unable to parse IP`,
+		vals: []string{`for="2001:db8::1"`},
+		want: nil,
+	}, {
+		name: "bracketed_ipv4",
+		wantErrMsg: `bad header Forwarded value "for=\"[192.0.2.1]\"": element 0: ` +
+			`parameter "for": bad node "[192.0.2.1]": bracketed address is not ipv6`,
+		vals: []string{`for="[192.0.2.1]"`},
+		want: nil,
+	}, {
+		name: "duplicate",
+		wantErrMsg: `bad header Forwarded value "for=192.0.2.1;For=192.0.2.2": ` +
+			`element 0: parameter "for": duplicate parameter`,
+		vals: []string{"for=192.0.2.1;For=192.0.2.2"},
+		want: nil,
+	}, {
+		name: "empty_element",
+		wantErrMsg: `bad header Forwarded value "for=192.0.2.1,,for=192.0.2.2": ` +
+			`element 1: empty element`,
+		vals: []string{"for=192.0.2.1,,for=192.0.2.2"},
+		want: nil,
+	}, {
+		name: "unterminated",
+		wantErrMsg: `bad header Forwarded value "for=\"192.0.2.1": element 0: ` +
+			`parameter "for": unterminated quoted string at index 4`,
+		vals: []string{`for="192.0.2.1`},
+		want: nil,
+	}, {
+		name: "no_value",
+		wantErrMsg: `bad header Forwarded value "for=": element 0: ` +
+			`parameter "for": empty value`,
+		vals: []string{"for="},
+		want: nil,
+	}, {
+		name: "bad_port",
+		wantErrMsg: `bad header Forwarded value "for=\"192.0.2.1:99999\"": ` +
+			`element 0: parameter "for": bad node "192.0.2.1:99999": ` +
+			`bad port: strconv.ParseUint: parsing "99999": value out of range`,
+		vals: []string{`for="192.0.2.1:99999"`},
+		want: nil,
+	}, {
+		name: "garbage",
+		wantErrMsg: `bad header Forwarded value "for=192.0.2.1 x": element 0: ` +
+			`unexpected character 'x' at index 14`,
+		vals: []string{"for=192.0.2.1 x"},
+		want: nil,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			elems, err := httphdr.ParseForwarded(newHeader(httphdr.Forwarded, tc.vals...))
+			testutil.AssertErrorChain(t, tc.wantErrMsg, err, new(*httphdr.HeaderError))
+
+			assert.Equal(t, tc.want, elems)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Package httputil contains HTTP utilities, such as middlewares and servers.
+package httputil
+
+import (
+	"net/http"
+)
+
+// Middleware is a common HTTP middleware.
+type Middleware interface {
+	// Wrap returns a new handler that wraps h.
+	Wrap(h http.Handler) (wrapped http.Handler)
+}
+
+// Wrap is a helper function that attaches the middlewares to h so that the
+// first middleware is the outermost one, that is, it is the first to receive
+// the request.
+func Wrap(h http.Handler, middlewares ...Middleware) (wrapped http.Handler) {
+	wrapped = h
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		wrapped = middlewares[i].Wrap(wrapped)
+	}
+
+	return wrapped
+}
+
+// ctxKey is the type for context keys of this package.
+type ctxKey int
+
+// Context keys of this package.
+const (
+	ctxKeyRealIP ctxKey = iota
+)
@@ -0,0 +1,87 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/httphdr"
+)
+
+// ContentTypeJSON is the value of the Content-Type header for JSON responses.
+const ContentTypeJSON = "application/json"
+
+// ErrorBody is the standard body of the JSON error responses.
+type ErrorBody struct {
+	// Details is optional additional information about the error, for example
+	// the list of invalid fields.
+	Details any `json:"details,omitempty"`
+
+	// Code is the machine-readable code of the error.
+	Code errors.Code `json:"code"`
+
+	// Message is the human-readable description of the error.
+	Message string `json:"message"`
+}
+
+// WriteJSON writes v encoded as JSON with the status code and the correct
+// Content-Type.  v is encoded before anything is written, so that if the
+// encoding fails, the client receives a proper internal server error response
+// instead of a partial body.  In that case, err is the encoding error.
+func WriteJSON(w http.ResponseWriter, status int, v any) (err error) {
+	buf := &bytes.Buffer{}
+	err = json.NewEncoder(buf).Encode(v)
+	if err != nil {
+		err = fmt.Errorf("encoding json response: %w", err)
+
+		// Use a body that is known to be encodable.
+		buf.Reset()
+		status = http.StatusInternalServerError
+		_ = json.NewEncoder(buf).Encode(&ErrorBody{
+			Code:    errors.CodeInternal,
+			Message: http.StatusText(status),
+		})
+	}
+
+	h := w.Header()
+	h.Set(httphdr.ContentType, ContentTypeJSON)
+	h.Set(httphdr.XContentTypeOptions, "nosniff")
+	w.WriteHeader(status)
+
+	_, writeErr := w.Write(buf.Bytes())
+	if writeErr != nil {
+		writeErr = fmt.Errorf("writing json response: %w", writeErr)
+	}
+
+	return errors.Join(err, writeErr)
+}
+
+// WriteError writes a JSON error response for reqErr with the body of type
+// *ErrorBody.  The code and the status code are determined using reg or, if it
+// is nil, the default code registry of package errors.  The messages of the
+// errors with the server error status codes are replaced with the status text
+// to avoid leaking the internal details to the clients.  details is optional.
+func WriteError(
+	w http.ResponseWriter,
+	reg *errors.CodeRegistry,
+	reqErr error,
+	details any,
+) (err error) {
+	if reg == nil {
+		reg = errors.DefaultCodeRegistry()
+	}
+
+	status := reg.HTTPStatus(reqErr)
+	msg := http.StatusText(status)
+	if status < http.StatusInternalServerError && reqErr != nil {
+		msg = reqErr.Error()
+	}
+
+	return WriteJSON(w, status, &ErrorBody{
+		Details: details,
+		Code:    reg.CodeOf(reqErr),
+		Message: msg,
+	})
+}
@@ -0,0 +1,93 @@
+package httputil_test
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/httputil"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := httputil.WriteJSON(w, http.StatusCreated, map[string]int{"id": 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, httputil.ContentTypeJSON, w.Header().Get(httphdr.ContentType))
+		assert.JSONEq(t, `{"id":1}`, w.Body.String())
+	})
+
+	t.Run("bad_value", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		err := httputil.WriteJSON(w, http.StatusOK, math.Inf(1))
+		testutil.AssertErrorMsg(t, "encoding json response: json: unsupported value: +Inf", err)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"code":"internal","message":"Internal Server Error"}`, w.Body.String())
+	})
+}
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	const errNotFound errors.Error = "no such filter"
+
+	reg := errors.NewCodeRegistry()
+	reg.Register(errors.CodeNotFound, errors.CodeInfo{
+		HTTPStatus: http.StatusNotFound,
+		RCode:      errors.RCodeNameError,
+	}, errNotFound)
+
+	testCases := []struct {
+		name       string
+		err        error
+		details    any
+		wantBody   string
+		wantStatus int
+	}{{
+		name:       "registered",
+		err:        fmt.Errorf("getting filter: %w", errNotFound),
+		details:    nil,
+		wantBody:   `{"code":"not_found","message":"getting filter: no such filter"}`,
+		wantStatus: http.StatusNotFound,
+	}, {
+		name:    "coder",
+		err:     errors.WithCode(errors.Error("bad name"), errors.CodeInvalidArgument),
+		details: map[string]string{"field": "name"},
+		wantBody: `{"code":"invalid_argument","message":"bad name",` +
+			`"details":{"field":"name"}}`,
+		wantStatus: http.StatusBadRequest,
+	}, {
+		name:       "internal",
+		err:        errors.Error("database password is hunter2"),
+		details:    nil,
+		wantBody:   `{"code":"unknown","message":"Internal Server Error"}`,
+		wantStatus: http.StatusInternalServerError,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := httptest.NewRecorder()
+			err := httputil.WriteError(w, reg, tc.err, tc.details)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.wantStatus, w.Code)
+			assert.Equal(t, httputil.ContentTypeJSON, w.Header().Get(httphdr.ContentType))
+			assert.JSONEq(t, tc.wantBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package httputil
+
+import (
+	"bufio"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// LogMiddlewareConfig is the configuration structure for a *LogMiddleware.
+type LogMiddlewareConfig struct {
+	// Logger is used to log the requests.  If it is nil, the logger from the
+	// request context is used, see slogutil.LoggerFromContextOrDefault.
+	Logger *slog.Logger
+
+	// Clock is used to measure the duration of the requests.  If it is nil,
+	// timeutil.SystemClock is used.
+	Clock timeutil.Clock
+
+	// RouteLevels are the levels for the requests with particular URL paths,
+	// which override Level.
+	RouteLevels map[string]slog.Level
+
+	// SkipPaths are the URL paths of the requests which are not logged, for
+	// example the health-check ones.
+	SkipPaths []string
+
+	// Level is the level for the requests not in RouteLevels.
+	Level slog.Level
+}
+
+// LogMiddleware is a middleware that logs the method, path, response status and
+// size, duration, and client address of every request.  The client address is
+// taken from the request context, if there is one, see RealIPFromContext.
+type LogMiddleware struct {
+	logger      *slog.Logger
+	clock       timeutil.Clock
+	routeLevels map[string]slog.Level
+	skip        map[string]struct{}
+	level       slog.Level
+}
+
+// type check
+var _ Middleware = (*LogMiddleware)(nil)
+
+// NewLogMiddleware returns a new properly initialized *LogMiddleware.  c must
+// not be nil.
+func NewLogMiddleware(c *LogMiddlewareConfig) (mw *LogMiddleware) {
+	clock := c.Clock
+	if clock == nil {
+		clock = timeutil.SystemClock{}
+	}
+
+	skip := make(map[string]struct{}, len(c.SkipPaths))
+	for _, p := range c.SkipPaths {
+		skip[p] = struct{}{}
+	}
+
+	return &LogMiddleware{
+		logger:      c.Logger,
+		clock:       clock,
+		routeLevels: c.RouteLevels,
+		skip:        skip,
+		level:       c.Level,
+	}
+}
+
+// Wrap implements the Middleware interface for *LogMiddleware.
+func (mw *LogMiddleware) Wrap(h http.Handler) (wrapped http.Handler) {
+	f := func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := mw.skip[r.URL.Path]; ok {
+			h.ServeHTTP(w, r)
+
+			return
+		}
+
+		ctx := r.Context()
+		l := mw.logger
+		if l == nil {
+			l = slogutil.LoggerFromContextOrDefault(ctx)
+		}
+
+		lvl, ok := mw.routeLevels[r.URL.Path]
+		if !ok {
+			lvl = mw.level
+		}
+
+		if !l.Enabled(ctx, lvl) {
+			h.ServeHTTP(w, r)
+
+			return
+		}
+
+		rw := &recorderResponseWriter{ResponseWriter: w}
+		start := mw.clock.Now()
+		defer func() {
+			l.LogAttrs(
+				ctx,
+				lvl,
+				"http request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rw.status()),
+				slog.Int64("size", rw.size),
+				slog.Duration("duration", mw.clock.Now().Sub(start)),
+				slog.String("client", clientAddr(r)),
+			)
+		}()
+
+		h.ServeHTTP(rw, r)
+	}
+
+	return http.HandlerFunc(f)
+}
+
+// clientAddr returns the address of the client that made r.
+func clientAddr(r *http.Request) (addr string) {
+	if ip, ok := RealIPFromContext(r.Context()); ok {
+		return ip.String()
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// recorderResponseWriter is an http.ResponseWriter that records the status
+// code and the size of the response.
+type recorderResponseWriter struct {
+	http.ResponseWriter
+
+	code int
+	size int64
+}
+
+// type check
+var (
+	_ http.ResponseWriter = (*recorderResponseWriter)(nil)
+	_ http.Flusher        = (*recorderResponseWriter)(nil)
+	_ http.Hijacker       = (*recorderResponseWriter)(nil)
+)
+
+// WriteHeader implements the http.ResponseWriter interface for
+// *recorderResponseWriter.
+func (w *recorderResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements the http.ResponseWriter interface for
+// *recorderResponseWriter.
+func (w *recorderResponseWriter) Write(b []byte) (n int, err error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+
+	n, err = w.ResponseWriter.Write(b)
+	w.size += int64(n)
+
+	return n, err
+}
+
+// Flush implements the http.Flusher interface for *recorderResponseWriter.  It
+// does nothing if the underlying writer doesn't support flushing.
+func (w *recorderResponseWriter) Flush() {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+
+	// http.Flusher has no way to report the error.
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements the http.Hijacker interface for *recorderResponseWriter.
+// It returns an error wrapping http.ErrNotSupported if the underlying writer
+// doesn't support hijacking.
+func (w *recorderResponseWriter) Hijack() (conn net.Conn, brw *bufio.ReadWriter, err error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap returns the underlying writer, so that http.ResponseController can
+// reach the optional interfaces, such as http.Flusher.
+func (w *recorderResponseWriter) Unwrap() (rw http.ResponseWriter) {
+	return w.ResponseWriter
+}
+
+// status returns the recorded status code.  If the handler hasn't written
+// anything, the status is http.StatusOK, as net/http sends it in that case.
+func (w *recorderResponseWriter) status() (code int) {
+	if w.code == 0 {
+		return http.StatusOK
+	}
+
+	return w.code
+}
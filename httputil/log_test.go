@@ -0,0 +1,137 @@
+package httputil_test
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/httputil"
+	"github.com/AdguardTeam/golibs/logutil/logtest"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogMiddleware(t *testing.T) {
+	t.Parallel()
+
+	l, logs := logtest.NewLogger(t)
+	clock := testutil.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	mw := httputil.NewLogMiddleware(&httputil.LogMiddlewareConfig{
+		Logger: l,
+		Clock:  clock,
+		RouteLevels: map[string]slog.Level{
+			"/debug": slog.LevelDebug,
+		},
+		SkipPaths: []string{"/health-check"},
+		Level:     slog.LevelInfo,
+	})
+
+	h := mw.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clock.Advance(time.Second)
+
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	serve := func(path string) {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.RemoteAddr = "192.0.2.1:1234"
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	serve("/ok")
+	logtest.AssertLogged(
+		t,
+		logs,
+		slog.LevelInfo,
+		"http request",
+		slog.String("method", http.MethodGet),
+		slog.String("path", "/ok"),
+		slog.Int("status", http.StatusOK),
+		slog.Int64("size", 5),
+		slog.Duration("duration", time.Second),
+		slog.String("client", "192.0.2.1"),
+	)
+
+	serve("/missing")
+	logtest.AssertLogged(
+		t,
+		logs,
+		slog.LevelInfo,
+		"http request",
+		slog.String("path", "/missing"),
+		slog.Int("status", http.StatusNotFound),
+	)
+
+	serve("/debug")
+	logtest.AssertLogged(t, logs, slog.LevelDebug, "http request", slog.String("path", "/debug"))
+
+	logs.Reset()
+	serve("/health-check")
+	assert.Empty(t, logs.Records())
+}
+
+func TestLogMiddleware_realIP(t *testing.T) {
+	t.Parallel()
+
+	l, logs := logtest.NewLogger(t)
+	h := httputil.Wrap(
+		http.NotFoundHandler(),
+		httputil.NewRealIP(&httputil.RealIPConfig{
+			TrustedProxies: mustParseSubnetSet("10.0.0.0/8"),
+			Header:         "X-Forwarded-For",
+		}),
+		httputil.NewLogMiddleware(&httputil.LogMiddlewareConfig{
+			Logger: l,
+		}),
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("X-Forwarded-For", "192.0.2.1")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	logtest.AssertLogged(t, logs, slog.LevelInfo, "http request", slog.String("client", "192.0.2.1"))
+}
+
+func TestLogMiddleware_optionalInterfaces(t *testing.T) {
+	t.Parallel()
+
+	l, _ := logtest.NewLogger(t)
+
+	var flushOK, hijackOK bool
+	var hijackErr error
+	h := httputil.NewLogMiddleware(&httputil.LogMiddlewareConfig{
+		Logger: l,
+	}).Wrap(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		var f http.Flusher
+		f, flushOK = w.(http.Flusher)
+		if flushOK {
+			f.Flush()
+		}
+
+		var hj http.Hijacker
+		hj, hijackOK = w.(http.Hijacker)
+		if hijackOK {
+			_, _, hijackErr = hj.Hijack()
+		}
+	}))
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.True(t, flushOK)
+	require.True(t, hijackOK)
+
+	assert.True(t, rw.Flushed)
+	assert.ErrorIs(t, hijackErr, http.ErrNotSupported)
+}
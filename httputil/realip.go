@@ -0,0 +1,171 @@
+package httputil
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/netutil"
+)
+
+// ContextWithRealIP returns a new context with the real client address.
+func ContextWithRealIP(parent context.Context, ip netip.Addr) (ctx context.Context) {
+	return context.WithValue(parent, ctxKeyRealIP, ip)
+}
+
+// RealIPFromContext returns the real client address stored in ctx by
+// ContextWithRealIP, for example by a *RealIP middleware.
+func RealIPFromContext(ctx context.Context) (ip netip.Addr, ok bool) {
+	ip, ok = ctx.Value(ctxKeyRealIP).(netip.Addr)
+
+	return ip, ok
+}
+
+// RealIPConfig is the configuration structure for a *RealIP middleware.
+type RealIPConfig struct {
+	// TrustedProxies is the set of networks of the proxies the headers of
+	// which are trusted.  If it is nil, no proxies are trusted and the address
+	// of the peer is always used.
+	TrustedProxies netutil.SubnetSet
+
+	// Header is the name of the single header set by the trusted proxies.  The
+	// supported headers are httphdr.Forwarded, httphdr.XForwardedFor, and
+	// single-address headers, such as httphdr.XRealIP and
+	// httphdr.CFConnectingIP.  Only one header is supported, since the
+	// trusted proxies usually don't remove the other ones, which could then be
+	// forged by the client.  If it is empty, the address of the peer is always
+	// used.
+	Header string
+}
+
+// RealIP is a middleware that determines the real address of the client and
+// stores it in the request context.  The proxy header is only inspected if the
+// request comes from a trusted proxy.  For the headers containing chains of
+// addresses, the rightmost address which doesn't belong to a trusted proxy is
+// used, since the rest of the chain could have been forged by the client.  If
+// the address can't be determined using the header, including when it is
+// missing or malformed, the address of the peer is used.
+type RealIP struct {
+	trusted netutil.SubnetSet
+	header  string
+}
+
+// type check
+var _ Middleware = (*RealIP)(nil)
+
+// NewRealIP returns a new properly initialized *RealIP.  c must not be nil.
+func NewRealIP(c *RealIPConfig) (mw *RealIP) {
+	return &RealIP{
+		trusted: c.TrustedProxies,
+		header:  c.Header,
+	}
+}
+
+// Wrap implements the Middleware interface for *RealIP.  If the address of the
+// peer can't be parsed, the request is passed as is.
+func (mw *RealIP) Wrap(h http.Handler) (wrapped http.Handler) {
+	f := func(w http.ResponseWriter, r *http.Request) {
+		ip, ok := mw.RealIP(r)
+		if ok {
+			r = r.WithContext(ContextWithRealIP(r.Context(), ip))
+		}
+
+		h.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(f)
+}
+
+// RealIP returns the real address of the client that made r.  ok is false if
+// the address of the peer in r.RemoteAddr can't be parsed.
+func (mw *RealIP) RealIP(r *http.Request) (ip netip.Addr, ok bool) {
+	peer, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	ip = peer.Addr().Unmap()
+	if mw.header == "" || !mw.isTrusted(ip) {
+		return ip, true
+	}
+
+	chain, err := headerChain(r.Header, mw.header)
+	if err != nil {
+		return ip, true
+	}
+
+	if hdrIP := mw.rightmostUntrusted(chain); hdrIP.IsValid() {
+		return hdrIP, true
+	}
+
+	return ip, true
+}
+
+// headerChain returns the chain of addresses from the header name.  The chain
+// is empty if the header is absent.
+func headerChain(h http.Header, name string) (chain []netip.Addr, err error) {
+	switch {
+	case strings.EqualFold(name, httphdr.Forwarded):
+		var elems []httphdr.ForwardedElement
+		elems, err = httphdr.ParseForwarded(h)
+		for _, e := range elems {
+			// Unknown and obfuscated nodes have invalid addresses, which are
+			// never trusted and so stop the search in rightmostUntrusted.
+			chain = append(chain, e.For.Addr)
+		}
+
+		return chain, err
+	case strings.EqualFold(name, httphdr.XForwardedFor):
+		return httphdr.ParseXForwardedFor(h)
+	case strings.EqualFold(name, httphdr.XRealIP):
+		var ip netip.Addr
+		ip, err = httphdr.ParseXRealIP(h)
+		if err != nil || !ip.IsValid() {
+			return nil, err
+		}
+
+		return []netip.Addr{ip}, nil
+	default:
+		return parseSingleAddr(h, name)
+	}
+}
+
+// rightmostUntrusted returns the rightmost address in chain which doesn't
+// belong to a trusted proxy or the leftmost one if all of them do.  The
+// returned address is invalid if chain is empty or if that address is invalid.
+func (mw *RealIP) rightmostUntrusted(chain []netip.Addr) (ip netip.Addr) {
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip = chain[i].Unmap()
+		if !mw.isTrusted(ip) {
+			return ip
+		}
+	}
+
+	return ip
+}
+
+// isTrusted returns true if ip belongs to a trusted proxy.
+func (mw *RealIP) isTrusted(ip netip.Addr) (ok bool) {
+	return mw.trusted != nil && ip.IsValid() && mw.trusted.Contains(net.IP(ip.AsSlice()))
+}
+
+// parseSingleAddr parses the header name containing a single address.
+func parseSingleAddr(h http.Header, name string) (chain []netip.Addr, err error) {
+	vals := h.Values(name)
+	if len(vals) == 0 {
+		return nil, nil
+	} else if len(vals) > 1 {
+		return nil, errors.Error("more than one value")
+	}
+
+	ip, err := netip.ParseAddr(strings.TrimSpace(vals[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	return []netip.Addr{ip}, nil
+}
@@ -0,0 +1,178 @@
+package httputil_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/httputil"
+	"github.com/AdguardTeam/golibs/netutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mustParseSubnetSet returns a SubnetSet with the given CIDRs.  It panics on
+// errors.
+func mustParseSubnetSet(cidrs ...string) (s netutil.SubnetSet) {
+	nets := make(netutil.SliceSubnetSet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+
+		nets = append(nets, n)
+	}
+
+	return nets
+}
+
+func TestRealIP(t *testing.T) {
+	t.Parallel()
+
+	trusted := mustParseSubnetSet("10.0.0.0/8", "2001:db8:ffff::/48")
+
+	const (
+		clientIP  = "192.0.2.1"
+		proxyIP   = "10.0.0.1"
+		spoofedIP = "198.51.100.6"
+	)
+
+	testCases := []struct {
+		name       string
+		trustedHdr string
+		remoteAddr string
+		header     map[string]string
+		want       string
+	}{{
+		name:       "untrusted_peer",
+		trustedHdr: httphdr.XForwardedFor,
+		remoteAddr: "198.51.100.1:1234",
+		header:     map[string]string{httphdr.XForwardedFor: clientIP},
+		want:       "198.51.100.1",
+	}, {
+		name:       "no_headers",
+		trustedHdr: httphdr.XForwardedFor,
+		remoteAddr: proxyIP + ":1234",
+		header:     map[string]string{},
+		want:       proxyIP,
+	}, {
+		name:       "no_trusted_header",
+		trustedHdr: "",
+		remoteAddr: proxyIP + ":1234",
+		header:     map[string]string{httphdr.XForwardedFor: clientIP},
+		want:       proxyIP,
+	}, {
+		name:       "xff",
+		trustedHdr: httphdr.XForwardedFor,
+		remoteAddr: proxyIP + ":1234",
+		header:     map[string]string{httphdr.XForwardedFor: "203.0.113.1, " + clientIP + ", 10.1.1.1"},
+		want:       clientIP,
+	}, {
+		name:       "xff_all_trusted",
+		trustedHdr: httphdr.XForwardedFor,
+		remoteAddr: proxyIP + ":1234",
+		header:     map[string]string{httphdr.XForwardedFor: "10.2.2.2, 10.1.1.1"},
+		want:       "10.2.2.2",
+	}, {
+		name:       "xff_bad",
+		trustedHdr: httphdr.XForwardedFor,
+		remoteAddr: proxyIP + ":1234",
+		header: map[string]string{
+			httphdr.XForwardedFor: "bad, " + clientIP,
+			httphdr.XRealIP:       spoofedIP,
+		},
+		want: proxyIP,
+	}, {
+		name:       "xff_spoofed_forwarded",
+		trustedHdr: httphdr.XForwardedFor,
+		remoteAddr: proxyIP + ":1234",
+		header:     map[string]string{httphdr.Forwarded: "for=" + spoofedIP},
+		want:       proxyIP,
+	}, {
+		name:       "forwarded",
+		trustedHdr: httphdr.Forwarded,
+		remoteAddr: "[2001:db8:ffff::1]:1234",
+		header: map[string]string{
+			httphdr.Forwarded:     `for="[2001:db8::1]"`,
+			httphdr.XForwardedFor: spoofedIP,
+		},
+		want: "2001:db8::1",
+	}, {
+		name:       "forwarded_unknown",
+		trustedHdr: httphdr.Forwarded,
+		remoteAddr: proxyIP + ":1234",
+		header:     map[string]string{httphdr.Forwarded: "for=" + clientIP + ", for=unknown"},
+		want:       proxyIP,
+	}, {
+		name:       "mapped_peer",
+		trustedHdr: httphdr.XRealIP,
+		remoteAddr: "[::ffff:10.0.0.1]:1234",
+		header:     map[string]string{httphdr.XRealIP: clientIP},
+		want:       clientIP,
+	}, {
+		name:       "real_ip_bad",
+		trustedHdr: httphdr.XRealIP,
+		remoteAddr: proxyIP + ":1234",
+		header: map[string]string{
+			httphdr.XRealIP:       "bad",
+			httphdr.XForwardedFor: spoofedIP,
+		},
+		want: proxyIP,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			mw := httputil.NewRealIP(&httputil.RealIPConfig{
+				TrustedProxies: trusted,
+				Header:         tc.trustedHdr,
+			})
+
+			var got netip.Addr
+			var ok bool
+			h := mw.Wrap(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got, ok = httputil.RealIPFromContext(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tc.remoteAddr
+			for k, v := range tc.header {
+				r.Header.Set(k, v)
+			}
+
+			h.ServeHTTP(httptest.NewRecorder(), r)
+			require.True(t, ok)
+
+			assert.Equal(t, netip.MustParseAddr(tc.want), got)
+		})
+	}
+}
+
+func TestRealIP_custom(t *testing.T) {
+	t.Parallel()
+
+	mw := httputil.NewRealIP(&httputil.RealIPConfig{
+		TrustedProxies: mustParseSubnetSet("10.0.0.0/8"),
+		Header:         "cf-connecting-ip",
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set(httphdr.CFConnectingIP, "192.0.2.1")
+	r.Header.Set(httphdr.XForwardedFor, "192.0.2.2")
+
+	ip, ok := mw.RealIP(r)
+	require.True(t, ok)
+
+	assert.Equal(t, netip.MustParseAddr("192.0.2.1"), ip)
+
+	r.RemoteAddr = "bad"
+	_, ok = mw.RealIP(r)
+	assert.False(t, ok)
+}
@@ -0,0 +1,204 @@
+package httputil
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/service"
+)
+
+// ServerConfig is the configuration structure for a *Server.
+type ServerConfig struct {
+	// Handler handles the requests.  It must not be nil.
+	Handler http.Handler
+
+	// TLSConfig, if not nil, makes the server serve HTTPS.  It can later be
+	// replaced using Server.SetTLSConfig.
+	TLSConfig *tls.Config
+
+	// Addr is the TCP address to listen on, for example "127.0.0.1:8080".
+	Addr string
+
+	// ReadTimeout is the maximum duration for reading the whole request.
+	ReadTimeout time.Duration
+
+	// ReadHeaderTimeout is the maximum duration for reading the request
+	// headers.
+	ReadHeaderTimeout time.Duration
+
+	// WriteTimeout is the maximum duration for writing the response.
+	WriteTimeout time.Duration
+
+	// IdleTimeout is the maximum duration for waiting for the next request on
+	// a keep-alive connection.
+	IdleTimeout time.Duration
+
+	// ShutdownTimeout, if positive, limits the duration of Shutdown in
+	// addition to the deadline of its context.
+	ShutdownTimeout time.Duration
+}
+
+// Server is a wrapper around *http.Server with context-based startup and
+// shutdown.
+type Server struct {
+	http            *http.Server
+	tlsConf         *atomic.Pointer[tls.Config]
+	addr            string
+	shutdownTimeout time.Duration
+
+	// mu protects listener and serveErr.
+	mu       *sync.Mutex
+	listener net.Listener
+	serveErr error
+
+	// done is closed when the server stops serving.
+	done chan struct{}
+}
+
+// NewServer returns a new properly initialized *Server.  c must not be nil.
+func NewServer(c *ServerConfig) (s *Server) {
+	s = &Server{
+		tlsConf:         &atomic.Pointer[tls.Config]{},
+		addr:            c.Addr,
+		shutdownTimeout: c.ShutdownTimeout,
+		mu:              &sync.Mutex{},
+		done:            make(chan struct{}),
+	}
+
+	s.tlsConf.Store(c.TLSConfig)
+
+	s.http = &http.Server{
+		Handler:           c.Handler,
+		ReadTimeout:       c.ReadTimeout,
+		ReadHeaderTimeout: c.ReadHeaderTimeout,
+		WriteTimeout:      c.WriteTimeout,
+		IdleTimeout:       c.IdleTimeout,
+	}
+
+	return s
+}
+
+// SetTLSConfig replaces the TLS configuration of s, for example after the
+// certificates have been renewed.  The new configuration is used for the new
+// connections.  conf must not be nil if s has been created with a TLS
+// configuration, and it has no effect otherwise.
+func (s *Server) SetTLSConfig(conf *tls.Config) {
+	s.tlsConf.Store(conf)
+}
+
+// type check
+var _ service.Interface = (*Server)(nil)
+
+// Start starts listening on the configured address and serving the requests
+// in a separate goroutine.  ctx is only used for listening.  The errors
+// returned from serving are reported by Err and Shutdown.
+func (s *Server) Start(ctx context.Context) (err error) {
+	defer func() { err = errors.Annotate(err, "starting http server: %w") }()
+
+	lc := &net.ListenConfig{}
+	l, err := lc.Listen(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	if s.tlsConf.Load() != nil {
+		l = tls.NewListener(l, &tls.Config{
+			GetConfigForClient: func(_ *tls.ClientHelloInfo) (c *tls.Config, err error) {
+				return s.tlsConf.Load(), nil
+			},
+		})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		_ = l.Close()
+
+		return errors.Error("already started")
+	}
+
+	s.listener = l
+
+	go s.serve(l)
+
+	return nil
+}
+
+// serve serves the requests from l and records the error, if any.
+func (s *Server) serve(l net.Listener) {
+	defer close(s.done)
+
+	err := s.http.Serve(l)
+	if errors.Is(err, http.ErrServerClosed) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.serveErr = fmt.Errorf("serving http: %w", err)
+}
+
+// Addr returns the address the server is listening on.  It is nil if the
+// server hasn't been started.  It is useful when the port in the configured
+// address is zero.
+func (s *Server) Addr() (addr net.Addr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
+
+// Done returns a channel which is closed when the server stops serving, either
+// because of Shutdown or because of an error.
+func (s *Server) Done() (done <-chan struct{}) {
+	return s.done
+}
+
+// Err returns the error which has stopped the server, if any.
+func (s *Server) Err() (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.serveErr
+}
+
+// Shutdown gracefully shuts the server down, waiting for the active requests
+// to finish until ctx is done or the shutdown timeout expires.  The error also
+// contains the error which has stopped the server before, if any.
+func (s *Server) Shutdown(ctx context.Context) (err error) {
+	s.mu.Lock()
+	started := s.listener != nil
+	s.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+
+	if s.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.shutdownTimeout)
+		defer cancel()
+	}
+
+	err = s.http.Shutdown(ctx)
+	if err != nil {
+		err = fmt.Errorf("shutting down http server: %w", err)
+	} else {
+		<-s.done
+	}
+
+	return errors.Join(s.Err(), err)
+}
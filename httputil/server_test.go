@@ -0,0 +1,119 @@
+package httputil_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/httputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTimeout is the common timeout for tests.
+const testTimeout = 1 * time.Second
+
+// newTestCert returns a new self-signed certificate with the given serial
+// number.
+func newTestCert(t *testing.T, serial int64) (cert tls.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+	}
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	s := httputil.NewServer(&httputil.ServerConfig{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, "ok")
+		}),
+		Addr:            "127.0.0.1:0",
+		ShutdownTimeout: testTimeout,
+	})
+
+	assert.Nil(t, s.Addr())
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	t.Cleanup(cancel)
+
+	require.NoError(t, s.Start(ctx))
+	assert.Error(t, s.Start(ctx))
+
+	resp, err := http.Get("http://" + s.Addr().String())
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "ok", string(b))
+
+	require.NoError(t, s.Shutdown(ctx))
+
+	select {
+	case <-s.Done():
+	default:
+		t.Fatal("server is not done after shutdown")
+	}
+
+	assert.NoError(t, s.Err())
+}
+
+func TestServer_SetTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	s := httputil.NewServer(&httputil.ServerConfig{
+		Handler:   http.NotFoundHandler(),
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{newTestCert(t, 1)}},
+		Addr:      "127.0.0.1:0",
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	t.Cleanup(cancel)
+
+	require.NoError(t, s.Start(ctx))
+	t.Cleanup(func() { require.NoError(t, s.Shutdown(ctx)) })
+
+	serial := func() (n int64) {
+		conn, err := tls.Dial("tcp", s.Addr().String(), &tls.Config{
+			InsecureSkipVerify: true,
+		})
+		require.NoError(t, err)
+
+		defer func() { require.NoError(t, conn.Close()) }()
+
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	assert.Equal(t, int64(1), serial())
+
+	s.SetTLSConfig(&tls.Config{Certificates: []tls.Certificate{newTestCert(t, 2)}})
+	assert.Equal(t, int64(2), serial())
+}
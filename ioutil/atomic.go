@@ -0,0 +1,79 @@
+package ioutil
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// WriteFileAtomic writes data to the file with the given name, like
+// os.WriteFile, but makes sure that the file contains either the previous or
+// the new data even after a crash or a power failure.  It writes data to a
+// temporary file in the same directory, syncs it to the disk, sets perm, and
+// renames it into place.  On Unix, the directory is synced as well.
+//
+// On Windows, where the renaming fails while another process has the target
+// file open, the renaming is retried for a short time.
+func WriteFileAtomic(name string, data []byte, perm fs.FileMode) (err error) {
+	defer func() { err = errors.Annotate(err, "writing file atomically: %w") }()
+
+	dir, base := filepath.Split(name)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+base+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tmpName := tmp.Name()
+	defer func() {
+		if err != nil {
+			// Don't leave the temporary file behind.  The error is not
+			// important, since the file might have already been closed
+			// or renamed.
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	err = writeAndSync(tmp, data, perm)
+	if err != nil {
+		return errors.WithDeferred(err, tmp.Close())
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("closing temporary file: %w", err)
+	}
+
+	err = renameFile(tmpName, name)
+	if err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// writeAndSync writes data to f, sets its permissions, and syncs it.
+func writeAndSync(f *os.File, data []byte, perm fs.FileMode) (err error) {
+	_, err = f.Write(data)
+	if err != nil {
+		return fmt.Errorf("writing temporary file: %w", err)
+	}
+
+	err = f.Chmod(perm)
+	if err != nil {
+		return fmt.Errorf("setting permissions: %w", err)
+	}
+
+	err = f.Sync()
+	if err != nil {
+		return fmt.Errorf("syncing temporary file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package ioutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/ioutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "test.txt")
+
+	err := ioutil.WriteFileAtomic(name, []byte("old"), 0o644)
+	require.NoError(t, err)
+
+	err = ioutil.WriteFileAtomic(name, []byte("new"), 0o600)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(name)
+	require.NoError(t, err)
+
+	assert.Equal(t, "new", string(data))
+
+	fi, err := os.Stat(name)
+	require.NoError(t, err)
+
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	assert.Len(t, entries, 1)
+}
+
+func TestWriteFileAtomic_noDir(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "absent", "test.txt")
+	err := ioutil.WriteFileAtomic(name, []byte("data"), 0o644)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,31 @@
+//go:build !windows
+// +build !windows
+
+package ioutil
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// renameFile renames the file oldName to newName.
+func renameFile(oldName, newName string) (err error) {
+	return os.Rename(oldName, newName)
+}
+
+// syncDir syncs the directory so that the renaming is persisted.
+func syncDir(dir string) (err error) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("opening directory: %w", err)
+	}
+
+	err = d.Sync()
+	if err != nil {
+		err = fmt.Errorf("syncing directory: %w", err)
+	}
+
+	return errors.WithDeferred(err, d.Close())
+}
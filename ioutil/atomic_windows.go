@@ -0,0 +1,42 @@
+//go:build windows
+// +build windows
+
+package ioutil
+
+import (
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+const (
+	// renameAttempts is the maximum number of attempts to rename a file.
+	renameAttempts = 5
+
+	// renameRetryIvl is the interval between the attempts to rename a file.
+	renameRetryIvl = 50 * time.Millisecond
+)
+
+// renameFile renames the file oldName to newName.  On Windows, the renaming
+// fails with an access error while another process, for example an antivirus
+// or an indexer, has newName open, so it's retried a few times.
+func renameFile(oldName, newName string) (err error) {
+	for i := 0; i < renameAttempts; i++ {
+		err = os.Rename(oldName, newName)
+		if !errors.Is(err, fs.ErrPermission) {
+			return err
+		}
+
+		time.Sleep(renameRetryIvl)
+	}
+
+	return err
+}
+
+// syncDir does nothing on Windows, since directories cannot be synced there,
+// and the renaming is persisted by the file system.
+func syncDir(_ string) (err error) {
+	return nil
+}
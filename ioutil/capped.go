@@ -0,0 +1,98 @@
+package ioutil
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// ErrBufferFull is returned by CappedBuffer.Write when the data doesn't fit
+// into the buffer and truncation is disabled.
+const ErrBufferFull errors.Error = "buffer full"
+
+// CappedBufferConfig is the configuration structure for a *CappedBuffer.
+type CappedBufferConfig struct {
+	// Limit is the maximum number of bytes the buffer keeps.  It must not be
+	// negative.
+	Limit int
+
+	// Truncate, if true, makes the buffer silently discard the data that
+	// doesn't fit, so that it can be used as a sink for io.Copy and
+	// io.MultiWriter.  Otherwise, ErrBufferFull is returned in that case.
+	Truncate bool
+}
+
+// CappedBuffer is an io.Writer that keeps at most a limited number of bytes,
+// which is useful to capture bounded previews of large data for diagnostics.
+// It is not safe for concurrent use.
+type CappedBuffer struct {
+	buf       *bytes.Buffer
+	limit     int
+	discarded int64
+	truncate  bool
+}
+
+// NewCappedBuffer returns a new properly initialized *CappedBuffer.  c must not
+// be nil.
+func NewCappedBuffer(c *CappedBufferConfig) (b *CappedBuffer) {
+	if c.Limit < 0 {
+		panic("ioutil: negative limit for CappedBuffer")
+	}
+
+	return &CappedBuffer{
+		buf:      &bytes.Buffer{},
+		limit:    c.Limit,
+		truncate: c.Truncate,
+	}
+}
+
+// type check
+var _ io.Writer = (*CappedBuffer)(nil)
+
+// Write implements the io.Writer interface for *CappedBuffer.  It writes as
+// much of p as fits.  The rest is discarded, and unless the buffer truncates,
+// n is the number of bytes kept and err is ErrBufferFull.
+func (b *CappedBuffer) Write(p []byte) (n int, err error) {
+	free := b.limit - b.buf.Len()
+	if len(p) <= free {
+		return b.buf.Write(p)
+	}
+
+	// Don't check the error, since bytes.Buffer.Write only panics.
+	n, _ = b.buf.Write(p[:free])
+	b.discarded += int64(len(p) - n)
+
+	if b.truncate {
+		return len(p), nil
+	}
+
+	return n, ErrBufferFull
+}
+
+// Bytes returns the data kept in the buffer.  The slice is valid until the next
+// modification of b.
+func (b *CappedBuffer) Bytes() (data []byte) {
+	return b.buf.Bytes()
+}
+
+// String returns the data kept in the buffer as a string.
+func (b *CappedBuffer) String() (s string) {
+	return b.buf.String()
+}
+
+// Len returns the number of bytes kept in the buffer.
+func (b *CappedBuffer) Len() (n int) {
+	return b.buf.Len()
+}
+
+// Discarded returns the number of bytes that didn't fit into the buffer.
+func (b *CappedBuffer) Discarded() (n int64) {
+	return b.discarded
+}
+
+// Reset empties the buffer and resets the number of discarded bytes.
+func (b *CappedBuffer) Reset() {
+	b.buf.Reset()
+	b.discarded = 0
+}
@@ -0,0 +1,55 @@
+package ioutil_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/ioutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCappedBuffer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("truncate", func(t *testing.T) {
+		t.Parallel()
+
+		b := ioutil.NewCappedBuffer(&ioutil.CappedBufferConfig{
+			Limit:    4,
+			Truncate: true,
+		})
+
+		n, err := io.Copy(b, strings.NewReader("abcdef"))
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(6), n)
+		assert.Equal(t, "abcd", b.String())
+		assert.Equal(t, int64(2), b.Discarded())
+
+		b.Reset()
+		assert.Equal(t, 0, b.Len())
+		assert.Equal(t, int64(0), b.Discarded())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		b := ioutil.NewCappedBuffer(&ioutil.CappedBufferConfig{
+			Limit: 4,
+		})
+
+		n, err := b.Write([]byte("abc"))
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, n)
+
+		n, err = b.Write([]byte("def"))
+		assert.ErrorIs(t, err, ioutil.ErrBufferFull)
+
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []byte("abcd"), b.Bytes())
+		assert.Equal(t, int64(2), b.Discarded())
+	})
+}
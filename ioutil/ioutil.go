@@ -0,0 +1,3 @@
+// Package ioutil contains extensions and utilities for package io from the
+// standard library.
+package ioutil
@@ -0,0 +1,80 @@
+package ioutil
+
+import (
+	"fmt"
+	"io"
+)
+
+// LimitReachedError is returned from the Read method of the reader returned by
+// LimitReader when the underlying reader contains more data than the limit
+// allows.
+type LimitReachedError struct {
+	// Limit is the maximum number of bytes allowed to be read.
+	Limit int64
+}
+
+// type check
+var _ error = (*LimitReachedError)(nil)
+
+// Error implements the error interface for *LimitReachedError.
+func (err *LimitReachedError) Error() (msg string) {
+	return fmt.Sprintf("attempted to read more than %d bytes", err.Limit)
+}
+
+// LimitReader returns an io.Reader that reads up to n bytes from r.  Unlike
+// io.LimitReader, if r contains more than n bytes, it returns a
+// *LimitReachedError instead of io.EOF, so that the callers can tell an
+// oversized input from a complete one.  To detect that, one extra byte may be
+// read from r.  n must not be negative.  limited is not safe for concurrent
+// use.
+func LimitReader(r io.Reader, n int64) (limited io.Reader) {
+	if n < 0 {
+		panic("ioutil: negative limit for LimitReader")
+	}
+
+	return &limitedReader{
+		r:     r,
+		limit: n,
+		left:  n,
+	}
+}
+
+// limitedReader is the io.Reader returned by LimitReader.
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	left  int64
+}
+
+// type check
+var _ io.Reader = (*limitedReader)(nil)
+
+// Read implements the io.Reader interface for *limitedReader.
+func (lr *limitedReader) Read(p []byte) (n int, err error) {
+	if lr.left == 0 {
+		return 0, lr.checkEOF()
+	}
+
+	if int64(len(p)) > lr.left {
+		p = p[:lr.left]
+	}
+
+	n, err = lr.r.Read(p)
+	lr.left -= int64(n)
+
+	return n, err
+}
+
+// checkEOF reads a byte from the underlying reader to check if it's exhausted.
+// It returns io.EOF if it is and a *LimitReachedError otherwise.
+func (lr *limitedReader) checkEOF() (err error) {
+	var b [1]byte
+	for {
+		n, err := lr.r.Read(b[:])
+		if n > 0 {
+			return &LimitReachedError{Limit: lr.limit}
+		} else if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package ioutil_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/ioutil"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitReader(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr string
+		limit   int64
+	}{{
+		name:    "less",
+		in:      "abc",
+		want:    "abc",
+		wantErr: "",
+		limit:   4,
+	}, {
+		name:    "exact",
+		in:      "abcd",
+		want:    "abcd",
+		wantErr: "",
+		limit:   4,
+	}, {
+		name:    "more",
+		in:      "abcde",
+		want:    "abcd",
+		wantErr: "attempted to read more than 4 bytes",
+		limit:   4,
+	}, {
+		name:    "zero",
+		in:      "a",
+		want:    "",
+		wantErr: "attempted to read more than 0 bytes",
+		limit:   0,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := io.ReadAll(ioutil.LimitReader(strings.NewReader(tc.in), tc.limit))
+			testutil.AssertErrorMsg(t, tc.wantErr, err)
+
+			assert.Equal(t, tc.want, string(got))
+			if tc.wantErr != "" {
+				lre := &ioutil.LimitReachedError{}
+				require.ErrorAs(t, err, &lre)
+
+				assert.Equal(t, tc.limit, lre.Limit)
+			}
+		})
+	}
+
+	assert.Panics(t, func() { _ = ioutil.LimitReader(strings.NewReader(""), -1) })
+}
@@ -1,6 +1,7 @@
 package log_test
 
 import (
+	"fmt"
 	"io"
 	"os"
 
@@ -54,3 +55,32 @@ func ExampleOnCloserError() {
 	//
 	// [error] github.com/AdguardTeam/golibs/log_test.ExampleOnCloserError.func1(): error occurred in a Close call: EOF
 }
+
+func ExampleModule() {
+	log.SetOutput(os.Stdout)
+	log.SetFlags(0)
+	log.SetLevel(log.INFO)
+
+	m := log.NewModule("dnsforward")
+	m.Debug("not printed")
+	m.Info("printed with the global level")
+
+	ok := log.SetModuleLevel("dnsforward", log.DEBUG)
+	fmt.Println("module set:", ok, m.Level())
+
+	m.Debug("printed with the module level")
+	log.Debug("not printed, since the global level is still %s", log.GetLevel())
+
+	ok = log.SetModuleLevel("nonexistent", log.DEBUG)
+	fmt.Println("nonexistent set:", ok)
+
+	m.ResetLevel()
+	m.Debug("not printed")
+
+	// Output:
+	//
+	// [info] dnsforward: printed with the global level
+	// module set: true debug
+	// [debug] dnsforward: printed with the module level
+	// nonexistent set: false
+}
@@ -0,0 +1,138 @@
+package log
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// levelInherit is the value of Module.level that means that the module uses
+// the global logging level.
+const levelInherit = -1
+
+// Module is a named logger with its own logging level, which can be changed
+// at runtime independently of the global one.  This allows increasing the
+// verbosity of a single subsystem without flooding the logs with the messages
+// from all others.
+//
+// Until the level is set using SetLevel, the module uses the global logging
+// level.  Messages are prefixed with the name of the module.
+type Module struct {
+	name string
+
+	// level is the logging level of the module or levelInherit.  It must only
+	// be accessed atomically.
+	level int64
+}
+
+// modulesMu protects modules.
+var modulesMu = &sync.Mutex{}
+
+// modules are all modules created with NewModule.
+var modules = map[string]*Module{}
+
+// NewModule returns the module with the given name, creating it if necessary.
+// Calling NewModule with the same name returns the same *Module, so that
+// packages can share module loggers.
+func NewModule(name string) (m *Module) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	m, ok := modules[name]
+	if !ok {
+		m = &Module{
+			name:  name,
+			level: levelInherit,
+		}
+		modules[name] = m
+	}
+
+	return m
+}
+
+// SetModuleLevel sets the logging level of the module with the given name.  ok
+// is false if there is no such module.
+func SetModuleLevel(name string, l Level) (ok bool) {
+	modulesMu.Lock()
+	m, ok := modules[name]
+	modulesMu.Unlock()
+
+	if ok {
+		m.SetLevel(l)
+	}
+
+	return ok
+}
+
+// ModuleNames returns the sorted names of all modules created with NewModule.
+func ModuleNames() (names []string) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	names = make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Name returns the name of the module.
+func (m *Module) Name() (name string) {
+	return m.name
+}
+
+// Level returns the current logging level of the module, which is the global
+// one unless it has been set with SetLevel.
+func (m *Module) Level() (l Level) {
+	if ml := atomic.LoadInt64(&m.level); ml != levelInherit {
+		return Level(ml)
+	}
+
+	return GetLevel()
+}
+
+// SetLevel sets the logging level of the module.
+func (m *Module) SetLevel(l Level) {
+	atomic.StoreInt64(&m.level, int64(l))
+}
+
+// ResetLevel makes the module use the global logging level again.
+func (m *Module) ResetLevel() {
+	atomic.StoreInt64(&m.level, levelInherit)
+}
+
+// Error writes to error log.
+func (m *Module) Error(format string, args ...interface{}) {
+	m.writeLog(ERROR, "error", "", format, args...)
+}
+
+// Info writes to info log.
+func (m *Module) Info(format string, args ...interface{}) {
+	m.writeLog(INFO, "info", "", format, args...)
+}
+
+// Debug writes to debug log.
+func (m *Module) Debug(format string, args ...interface{}) {
+	m.writeLog(DEBUG, "debug", "", format, args...)
+}
+
+// Tracef writes to debug log and adds the calling function's name.
+func (m *Module) Tracef(format string, args ...interface{}) {
+	if m.Level() >= DEBUG {
+		m.writeLog(DEBUG, "debug", getCallerName(), format, args...)
+	}
+}
+
+// writeLog writes the message prefixed with the name of the module if the
+// level of the module allows it.
+func (m *Module) writeLog(l Level, levelStr, funcName, format string, args ...interface{}) {
+	if m.Level() < l {
+		return
+	}
+
+	writeLog(levelStr, funcName, "%s: %s", m.name, fmt.Sprintf(format, args...))
+}
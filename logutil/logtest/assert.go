@@ -0,0 +1,72 @@
+package logtest
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// NewLogger returns a logger that captures all records as well as the handler
+// that stores them.  The captured records are removed when the test ends.
+func NewLogger(t testing.TB) (l *slog.Logger, h *Handler) {
+	t.Helper()
+
+	h = NewHandler(nil)
+	t.Cleanup(h.Reset)
+
+	return slog.New(h), h
+}
+
+// AssertLogged asserts that h has captured at least one record with the given
+// level and message, which also has all of attrs.  The values of attrs are
+// compared using slog.Value.Equal.
+func AssertLogged(
+	t testing.TB,
+	h *Handler,
+	lvl slog.Level,
+	msg string,
+	attrs ...slog.Attr,
+) (ok bool) {
+	t.Helper()
+
+	records := h.Find(lvl, msg)
+	if !assert.NotEmptyf(t, records, "no %s records with message %q", lvl, msg) {
+		return false
+	}
+
+	for _, r := range records {
+		if r.hasAttrs(attrs) {
+			return true
+		}
+	}
+
+	return assert.Failf(
+		t,
+		"attributes not found",
+		"no %s records with message %q have attributes %v",
+		lvl,
+		msg,
+		attrs,
+	)
+}
+
+// AssertNotLogged asserts that h has not captured any records with the given
+// level and message.
+func AssertNotLogged(t testing.TB, h *Handler, lvl slog.Level, msg string) (ok bool) {
+	t.Helper()
+
+	return assert.Emptyf(t, h.Find(lvl, msg), "unexpected %s records with message %q", lvl, msg)
+}
+
+// hasAttrs returns true if r has all attributes from attrs.
+func (r *Record) hasAttrs(attrs []slog.Attr) (ok bool) {
+	for _, a := range attrs {
+		v, found := r.Attr(a.Key)
+		if !found || !v.Equal(a.Value.Resolve()) {
+			return false
+		}
+	}
+
+	return true
+}
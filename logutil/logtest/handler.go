@@ -0,0 +1,197 @@
+// Package logtest contains utilities for testing logging code, such as a
+// handler that captures the records in memory.
+package logtest
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Record is a record captured by a *Handler.
+type Record struct {
+	// Time is the time of the record.
+	Time time.Time
+
+	// Message is the message of the record.
+	Message string
+
+	// Attrs are the attributes of the record, including the ones added with
+	// WithAttrs.  The keys of the attributes inside groups are prefixed with
+	// the names of the groups, separated by dots, and the groups themselves are
+	// flattened.
+	Attrs []slog.Attr
+
+	// Level is the level of the record.
+	Level slog.Level
+}
+
+// Attr returns the value of the first attribute of r with the given key, if
+// any.
+func (r *Record) Attr(key string) (v slog.Value, ok bool) {
+	for _, a := range r.Attrs {
+		if a.Key == key {
+			return a.Value, true
+		}
+	}
+
+	return slog.Value{}, false
+}
+
+// Handler is a slog.Handler that records all handled records in memory.  It is
+// intended to be used in tests to check that specific messages have been
+// logged.  All records from the handlers derived from the same *Handler are
+// stored together.
+type Handler struct {
+	level slog.Leveler
+	store *store
+
+	groupPrefix string
+
+	// attrs are the flattened attributes added with WithAttrs.
+	attrs []slog.Attr
+}
+
+// store is the storage of records shared between all handlers derived from the
+// same *Handler.
+type store struct {
+	// mu protects records.
+	mu      *sync.Mutex
+	records []Record
+}
+
+// NewHandler returns a new properly initialized *Handler that captures records
+// with levels of at least lvl.  If lvl is nil, all records are captured.
+func NewHandler(lvl slog.Leveler) (h *Handler) {
+	if lvl == nil {
+		lvl = slog.Level(-1 << 31)
+	}
+
+	return &Handler{
+		level: lvl,
+		store: &store{
+			mu: &sync.Mutex{},
+		},
+	}
+}
+
+// type check
+var _ slog.Handler = (*Handler)(nil)
+
+// Enabled implements the slog.Handler interface for *Handler.
+func (h *Handler) Enabled(_ context.Context, lvl slog.Level) (ok bool) {
+	return lvl >= h.level.Level()
+}
+
+// Handle implements the slog.Handler interface for *Handler.
+func (h *Handler) Handle(_ context.Context, r slog.Record) (err error) {
+	attrs := slices.Clip(h.attrs)
+	r.Attrs(func(a slog.Attr) (cont bool) {
+		attrs = appendFlatAttr(attrs, h.groupPrefix, a)
+
+		return true
+	})
+
+	s := h.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = append(s.records, Record{
+		Time:    r.Time,
+		Message: r.Message,
+		Attrs:   attrs,
+		Level:   r.Level,
+	})
+
+	return nil
+}
+
+// WithAttrs implements the slog.Handler interface for *Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) (res slog.Handler) {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	clone := *h
+	clone.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		clone.attrs = appendFlatAttr(clone.attrs, h.groupPrefix, a)
+	}
+
+	return &clone
+}
+
+// WithGroup implements the slog.Handler interface for *Handler.
+func (h *Handler) WithGroup(name string) (res slog.Handler) {
+	if name == "" {
+		return h
+	}
+
+	clone := *h
+	clone.groupPrefix += name + "."
+
+	return &clone
+}
+
+// Records returns a copy of all captured records in the order in which they
+// have been handled.
+func (h *Handler) Records() (records []Record) {
+	s := h.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.records)
+}
+
+// Find returns all captured records with the given level and message.
+func (h *Handler) Find(lvl slog.Level, msg string) (records []Record) {
+	s := h.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, r := range s.records {
+		if r.Level == lvl && r.Message == msg {
+			records = append(records, r)
+		}
+	}
+
+	return records
+}
+
+// Reset removes all captured records.
+func (h *Handler) Reset() {
+	s := h.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = nil
+}
+
+// appendFlatAttr appends a resolved attribute a to attrs, prefixing its key
+// with groupPrefix and flattening the groups.  Empty attributes are ignored,
+// and attributes from groups with empty keys are inlined, as required by
+// slog.Handler.
+func appendFlatAttr(attrs []slog.Attr, groupPrefix string, a slog.Attr) (res []slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+
+		for _, ga := range a.Value.Group() {
+			attrs = appendFlatAttr(attrs, groupPrefix, ga)
+		}
+
+		return attrs
+	}
+
+	a.Key = groupPrefix + a.Key
+
+	return append(attrs, a)
+}
@@ -0,0 +1,106 @@
+package logtest_test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/logutil/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	l, h := logtest.NewLogger(t)
+
+	l = l.With("upstream", "1.2.3.4:53").WithGroup("req")
+	l.Warn("retrying", "attempt", 2, slog.Group("q", "name", "example.com"))
+	l.Debug("debug")
+
+	records := h.Records()
+	require.Len(t, records, 2)
+
+	r := records[0]
+	assert.Equal(t, slog.LevelWarn, r.Level)
+	assert.Equal(t, "retrying", r.Message)
+
+	v, ok := r.Attr("req.q.name")
+	require.True(t, ok)
+
+	assert.Equal(t, "example.com", v.String())
+
+	logtest.AssertLogged(t, h, slog.LevelWarn, "retrying")
+	logtest.AssertLogged(
+		t,
+		h,
+		slog.LevelWarn,
+		"retrying",
+		slog.String("upstream", "1.2.3.4:53"),
+		slog.Int("req.attempt", 2),
+	)
+	logtest.AssertNotLogged(t, h, slog.LevelError, "retrying")
+
+	h.Reset()
+	assert.Empty(t, h.Records())
+}
+
+// testTB is a testing.TB that records failures instead of failing the test.
+type testTB struct {
+	// TB is embedded here simply to make *testTB a testing.TB without actually
+	// implementing all methods.
+	testing.TB
+
+	failed bool
+}
+
+// Errorf implements the testing.TB interface for *testTB.
+func (t *testTB) Errorf(_ string, _ ...interface{}) {
+	t.failed = true
+}
+
+// Helper implements the testing.TB interface for *testTB.
+func (t *testTB) Helper() {}
+
+// Name implements the testing.TB interface for *testTB.
+func (t *testTB) Name() (name string) {
+	return "TestName"
+}
+
+func TestAssertLogged_fail(t *testing.T) {
+	t.Parallel()
+
+	l, h := logtest.NewLogger(t)
+	l.Info("msg", "a", 1)
+
+	testCases := []struct {
+		check func(tb testing.TB) (ok bool)
+		name  string
+	}{{
+		check: func(tb testing.TB) (ok bool) {
+			return logtest.AssertLogged(tb, h, slog.LevelInfo, "msg", slog.Int("a", 2))
+		},
+		name: "bad_attr",
+	}, {
+		check: func(tb testing.TB) (ok bool) {
+			return logtest.AssertLogged(tb, h, slog.LevelInfo, "other")
+		},
+		name: "bad_msg",
+	}, {
+		check: func(tb testing.TB) (ok bool) {
+			return logtest.AssertNotLogged(tb, h, slog.LevelInfo, "msg")
+		},
+		name: "logged",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tb := &testTB{}
+			assert.False(t, tc.check(tb))
+			assert.True(t, tb.failed)
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package logutil contains utilities for logging, such as rotating log files.
+package logutil
@@ -0,0 +1,393 @@
+package logutil
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// backupTimeFormat is the format of the timestamps in the names of the backup
+// files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// compressExt is the extension of the compressed backup files.
+const compressExt = ".gz"
+
+// RotatingWriterConfig is the configuration for a *RotatingWriter.
+type RotatingWriterConfig struct {
+	// Path is the path to the log file.  The backup files are created in the
+	// same directory with the timestamp of the rotation inserted before the
+	// extension, for example "dns-2006-01-02T15-04-05.000.log".  Path must not
+	// be empty.
+	Path string
+
+	// MaxSize is the maximum size of the log file in bytes, after which it is
+	// rotated.  If MaxSize is zero, the file is only rotated with Rotate.
+	MaxSize int64
+
+	// MaxBackups is the maximum number of backup files to keep.  If MaxBackups
+	// is zero, the number of backups is not limited.
+	MaxBackups int
+
+	// MaxAge is the maximum age of backup files, after which they are removed.
+	// If MaxAge is zero, backups are not removed based on their age.
+	MaxAge time.Duration
+
+	// ErrorHandler is called with the errors that happen during the rotations
+	// triggered by writes but don't prevent the writes themselves, such as
+	// failures to rename the log file, compress the backup, or remove the old
+	// backups.  If ErrorHandler is nil, the errors are printed to os.Stderr.
+	ErrorHandler func(err error)
+
+	// Compress, if true, makes the writer compress the backup files using
+	// gzip.
+	Compress bool
+}
+
+// RotatingWriter is an io.WriteCloser that writes to a file and rotates it
+// once it reaches the maximum size, removing old backups according to the
+// configuration.  It can be used as the output of the log package:
+//
+//   w, err := logutil.NewRotatingWriter(conf)
+//   if err != nil {
+//           // …
+//   }
+//
+//   log.SetOutput(w)
+//
+// The rotation, including the removal and compression of the backups, is
+// performed synchronously during the write that exceeds the limit.  A
+// *RotatingWriter is safe for concurrent use.
+type RotatingWriter struct {
+	// mu protects file and size.
+	mu   *sync.Mutex
+	file *os.File
+	size int64
+
+	// now is used to get the current time.  It is only replaced in tests.
+	now func() (t time.Time)
+
+	errHdlr func(err error)
+
+	path       string
+	maxSize    int64
+	maxBackups int
+	maxAge     time.Duration
+	compress   bool
+}
+
+// type check
+var _ io.WriteCloser = (*RotatingWriter)(nil)
+
+// NewRotatingWriter returns a new properly initialized *RotatingWriter with the
+// log file opened for appending.  c must not be nil.
+func NewRotatingWriter(c *RotatingWriterConfig) (w *RotatingWriter, err error) {
+	if c.Path == "" {
+		return nil, errors.Error("rotating writer: empty path")
+	}
+
+	errHdlr := c.ErrorHandler
+	if errHdlr == nil {
+		errHdlr = printError
+	}
+
+	w = &RotatingWriter{
+		mu:         &sync.Mutex{},
+		now:        time.Now,
+		errHdlr:    errHdlr,
+		path:       c.Path,
+		maxSize:    c.MaxSize,
+		maxBackups: c.MaxBackups,
+		maxAge:     c.MaxAge,
+		compress:   c.Compress,
+	}
+
+	err = w.open()
+	if err != nil {
+		return nil, fmt.Errorf("rotating writer: %w", err)
+	}
+
+	return w, nil
+}
+
+// printError is the default error handler of *RotatingWriter.
+func printError(err error) {
+	_, _ = fmt.Fprintf(os.Stderr, "logutil: %s\n", err)
+}
+
+// Write implements the io.Writer interface for *RotatingWriter.  If writing p
+// would make the file exceed the maximum size, the file is rotated first.  The
+// errors of the rotation are passed to the error handler, and p is written
+// anyway, unless the log file could not be reopened.
+func (w *RotatingWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		err = w.rotate()
+		if w.file == nil {
+			return 0, err
+		} else if err != nil {
+			w.errHdlr(err)
+		}
+	}
+
+	n, err = w.file.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+// Close implements the io.Closer interface for *RotatingWriter.
+func (w *RotatingWriter) Close() (err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err = w.file.Close()
+	w.file = nil
+
+	return err
+}
+
+// Rotate closes the current log file, renames it into a backup file, opens a
+// new log file, and removes the outdated backups.  If the renaming fails, the
+// current log file is reopened and used further.
+func (w *RotatingWriter) Rotate() (err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return os.ErrClosed
+	}
+
+	return w.rotate()
+}
+
+// open opens the log file for appending.  w.mu is expected to be locked.
+func (w *RotatingWriter) open() (err error) {
+	err = os.MkdirAll(filepath.Dir(w.path), 0o755)
+	if err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.WithDeferred(fmt.Errorf("getting file info: %w", err), f.Close())
+	}
+
+	w.file = f
+	w.size = fi.Size()
+
+	return nil
+}
+
+// rotate performs the rotation.  If the renaming fails, the log file is
+// reopened, so w.file is only nil after rotate if the log file could not be
+// opened.  w.mu is expected to be locked.
+func (w *RotatingWriter) rotate() (err error) {
+	defer func() { err = errors.Annotate(err, "rotating %q: %w", w.path) }()
+
+	now := w.now()
+	backup, err := w.replaceFile(now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if w.compress {
+		err = compressFile(backup)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("compressing backup: %w", err))
+		}
+	}
+
+	errs = append(errs, w.removeOld(now))
+
+	return errors.Join(errs...)
+}
+
+// replaceFile closes the log file, renames it into a backup file with a unique
+// name for the moment now, and opens a new log file.  If closing or renaming
+// fails, the log file is reopened.  w.mu is expected to be locked.
+func (w *RotatingWriter) replaceFile(now time.Time) (backup string, err error) {
+	closeErr := w.file.Close()
+	w.file = nil
+	if closeErr != nil {
+		return "", errors.Join(fmt.Errorf("closing file: %w", closeErr), w.open())
+	}
+
+	backup = w.uniqueBackupName(now)
+	err = os.Rename(w.path, backup)
+	if err != nil {
+		// Reopen the original file so that the writes can continue.
+		return "", errors.Join(fmt.Errorf("renaming file: %w", err), w.open())
+	}
+
+	return backup, w.open()
+}
+
+// uniqueBackupName returns the name of a backup file created at t, which
+// doesn't match any existing file.  If several rotations happen within the
+// precision of the timestamp, the later backups get later timestamps, which
+// keeps the order of the backups.
+func (w *RotatingWriter) uniqueBackupName(t time.Time) (name string) {
+	for {
+		name = w.backupName(t)
+		if !fileExists(name) && !fileExists(name+compressExt) {
+			return name
+		}
+
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// fileExists returns true if there is a file at path.
+func fileExists(path string) (ok bool) {
+	_, err := os.Lstat(path)
+
+	return err == nil
+}
+
+// splitPath returns the prefix and the extension of the backup file names.
+func (w *RotatingWriter) splitPath() (prefix, ext string) {
+	ext = filepath.Ext(w.path)
+
+	return strings.TrimSuffix(w.path, ext) + "-", ext
+}
+
+// backupName returns the name of a backup file created at t.
+func (w *RotatingWriter) backupName(t time.Time) (name string) {
+	prefix, ext := w.splitPath()
+
+	return prefix + t.UTC().Format(backupTimeFormat) + ext
+}
+
+// backup is a backup file.
+type backup struct {
+	created time.Time
+	path    string
+}
+
+// backups returns the existing backup files sorted from newest to oldest.
+func (w *RotatingWriter) backups() (bs []backup, err error) {
+	prefix, ext := w.splitPath()
+	matches, err := filepath.Glob(globEscape(prefix) + "*")
+	if err != nil {
+		return nil, fmt.Errorf("listing backups: %w", err)
+	}
+
+	for _, m := range matches {
+		ts := strings.TrimPrefix(m, prefix)
+		ts = strings.TrimSuffix(ts, compressExt)
+		ts = strings.TrimSuffix(ts, ext)
+
+		created, parseErr := time.Parse(backupTimeFormat, ts)
+		if parseErr != nil {
+			// Not a backup file.
+			continue
+		}
+
+		bs = append(bs, backup{
+			created: created,
+			path:    m,
+		})
+	}
+
+	sort.Slice(bs, func(i, j int) (less bool) {
+		return bs[i].created.After(bs[j].created)
+	})
+
+	return bs, nil
+}
+
+// removeOld removes the backups exceeding the maximum number and age at the
+// moment now.
+func (w *RotatingWriter) removeOld(now time.Time) (err error) {
+	if w.maxBackups == 0 && w.maxAge == 0 {
+		return nil
+	}
+
+	bs, err := w.backups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, b := range bs {
+		tooMany := w.maxBackups > 0 && i >= w.maxBackups
+		tooOld := w.maxAge > 0 && now.Sub(b.created) > w.maxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+
+		err = os.Remove(b.path)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// compressFile compresses the file at path into a file with the same name and
+// the compressExt extension and removes the original.
+func compressFile(path string) (err error) {
+	err = gzipFile(path+compressExt, path)
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// gzipFile writes the compressed contents of the file at srcPath to a new file
+// at dstPath.
+func gzipFile(dstPath, srcPath string) (err error) {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.WithDeferred(err, src.Close()) }()
+
+	dst, err := os.OpenFile(dstPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errors.WithDeferred(err, dst.Close()) }()
+
+	gzw := gzip.NewWriter(dst)
+	_, err = io.Copy(gzw, src)
+	if err != nil {
+		return err
+	}
+
+	return gzw.Close()
+}
+
+// globEscape escapes the special characters of filepath.Match in s.
+func globEscape(s string) (escaped string) {
+	r := strings.NewReplacer(`*`, `[*]`, `?`, `[?]`, `[`, `[[]`)
+
+	return r.Replace(s)
+}
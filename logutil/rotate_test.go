@@ -0,0 +1,220 @@
+package logutil
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRotatingWriter returns a *RotatingWriter for tests with a fake clock
+// that advances by a second on every call.
+func newTestRotatingWriter(t *testing.T, c *RotatingWriterConfig) (w *RotatingWriter) {
+	t.Helper()
+
+	w, err := NewRotatingWriter(c)
+	require.NoError(t, err)
+	testutil.CleanupAndRequireSuccess(t, w.Close)
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.now = func() (t time.Time) {
+		now = now.Add(time.Second)
+
+		return now
+	}
+
+	return w
+}
+
+func TestRotatingWriter_size(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+	w := newTestRotatingWriter(t, &RotatingWriterConfig{
+		Path:       path,
+		MaxSize:    8,
+		MaxBackups: 2,
+	})
+
+	for _, s := range []string{"line1\n", "line2\n", "line3\n", "line4\n"} {
+		_, err := io.WriteString(w, s)
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "line4\n", string(data))
+
+	bs, err := w.backups()
+	require.NoError(t, err)
+	require.Len(t, bs, 2)
+
+	assert.Equal(t, filepath.Join(dir, "test-2023-01-01T00-00-03.000.log"), bs[0].path)
+	assert.Equal(t, filepath.Join(dir, "test-2023-01-01T00-00-02.000.log"), bs[1].path)
+
+	data, err = os.ReadFile(bs[0].path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "line3\n", string(data))
+}
+
+func TestRotatingWriter_age(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+	w := newTestRotatingWriter(t, &RotatingWriterConfig{
+		Path:   path,
+		MaxAge: 1500 * time.Millisecond,
+	})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, w.Rotate())
+	}
+
+	bs, err := w.backups()
+	require.NoError(t, err)
+
+	// The backups are created at seconds 1, 2, and 3, so the first one is
+	// removed during the last rotation.
+	require.Len(t, bs, 2)
+
+	assert.Equal(t, filepath.Join(dir, "test-2023-01-01T00-00-03.000.log"), bs[0].path)
+	assert.Equal(t, filepath.Join(dir, "test-2023-01-01T00-00-02.000.log"), bs[1].path)
+}
+
+func TestRotatingWriter_compress(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.log")
+	w := newTestRotatingWriter(t, &RotatingWriterConfig{
+		Path:     path,
+		Compress: true,
+	})
+
+	_, err := io.WriteString(w, "data\n")
+	require.NoError(t, err)
+
+	require.NoError(t, w.Rotate())
+
+	bs, err := w.backups()
+	require.NoError(t, err)
+	require.Len(t, bs, 1)
+
+	backupPath := bs[0].path
+	assert.Equal(t, compressExt, filepath.Ext(backupPath))
+
+	f, err := os.Open(backupPath)
+	require.NoError(t, err)
+	testutil.CleanupAndRequireSuccess(t, f.Close)
+
+	r, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, "data\n", string(data))
+}
+
+func TestRotatingWriter_closed(t *testing.T) {
+	t.Parallel()
+
+	w, err := NewRotatingWriter(&RotatingWriterConfig{
+		Path: filepath.Join(t.TempDir(), "test.log"),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+
+	_, err = w.Write([]byte("data"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+	assert.ErrorIs(t, w.Rotate(), os.ErrClosed)
+}
+
+func TestRotatingWriter_Rotate_sameTime(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	w := newTestRotatingWriter(t, &RotatingWriterConfig{
+		Path: filepath.Join(dir, "test.log"),
+	})
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.now = func() (t time.Time) { return now }
+
+	for _, s := range []string{"line1\n", "line2\n"} {
+		_, err := io.WriteString(w, s)
+		require.NoError(t, err)
+
+		require.NoError(t, w.Rotate())
+	}
+
+	bs, err := w.backups()
+	require.NoError(t, err)
+	require.Len(t, bs, 2)
+
+	assert.Equal(t, filepath.Join(dir, "test-2023-01-01T00-00-00.001.log"), bs[0].path)
+	assert.Equal(t, filepath.Join(dir, "test-2023-01-01T00-00-00.000.log"), bs[1].path)
+
+	data, err := os.ReadFile(bs[0].path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "line2\n", string(data))
+}
+
+func TestRotatingWriter_Write_renameError(t *testing.T) {
+	t.Parallel()
+
+	var errs []error
+	path := filepath.Join(t.TempDir(), "test.log")
+	w := newTestRotatingWriter(t, &RotatingWriterConfig{
+		Path:    path,
+		MaxSize: 8,
+		ErrorHandler: func(err error) {
+			errs = append(errs, err)
+		},
+	})
+
+	_, err := io.WriteString(w, "line1\n")
+	require.NoError(t, err)
+
+	// Make the renaming fail.
+	require.NoError(t, os.Remove(path))
+
+	n, err := io.WriteString(w, "line2\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, n)
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], os.ErrNotExist)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "line2\n", string(data))
+
+	// The next rotation succeeds.
+	_, err = io.WriteString(w, "line3\n")
+	require.NoError(t, err)
+
+	bs, err := w.backups()
+	require.NoError(t, err)
+	require.Len(t, bs, 1)
+
+	data, err = os.ReadFile(bs[0].path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "line2\n", string(data))
+	assert.Len(t, errs, 1)
+}
@@ -0,0 +1,61 @@
+package slogutil
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ctxKey is the type for context keys of this package.
+type ctxKey int
+
+// ctxKeyLogger is the context key for loggers.
+const ctxKeyLogger ctxKey = iota
+
+// ContextWithLogger returns a new context with the given logger.
+func ContextWithLogger(parent context.Context, l *slog.Logger) (ctx context.Context) {
+	return context.WithValue(parent, ctxKeyLogger, l)
+}
+
+// LoggerFromContext returns a logger for this request, if any.
+func LoggerFromContext(ctx context.Context) (l *slog.Logger, ok bool) {
+	l, ok = ctx.Value(ctxKeyLogger).(*slog.Logger)
+
+	return l, ok && l != nil
+}
+
+// LoggerFromContextOrDefault returns the logger for this request, if any, or
+// the default logger of package log/slog otherwise.  It never returns nil.
+func LoggerFromContextOrDefault(ctx context.Context) (l *slog.Logger) {
+	l, ok := LoggerFromContext(ctx)
+	if !ok {
+		return slog.Default()
+	}
+
+	return l
+}
+
+// ContextWithAttrs returns a new context with a child of the logger from parent
+// containing args, as well as the child logger itself.  If parent has no
+// logger, the default logger of package log/slog is used as the base.  args
+// are interpreted in the same way as in slog.Logger.With.
+//
+// This is useful in middlewares that add request-scoped attributes, such as
+// request IDs and client addresses:
+//
+//   ctx, l := slogutil.ContextWithAttrs(r.Context(), "req_id", id)
+//   l.Debug("handling")
+//
+//   next.ServeHTTP(w, r.WithContext(ctx))
+func ContextWithAttrs(parent context.Context, args ...any) (ctx context.Context, l *slog.Logger) {
+	l = LoggerFromContextOrDefault(parent).With(args...)
+
+	return ContextWithLogger(parent, l), l
+}
+
+// ContextWithGroup is like ContextWithAttrs but uses slog.Logger.WithGroup to
+// derive the child logger.
+func ContextWithGroup(parent context.Context, name string) (ctx context.Context, l *slog.Logger) {
+	l = LoggerFromContextOrDefault(parent).WithGroup(name)
+
+	return ContextWithLogger(parent, l), l
+}
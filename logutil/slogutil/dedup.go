@@ -0,0 +1,220 @@
+package slogutil
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DedupHandlerConfig is the configuration for a *DedupHandler.
+type DedupHandlerConfig struct {
+	// Handler is the handler to which the records are passed.  It must not be
+	// nil.
+	Handler slog.Handler
+
+	// Window is the duration of the window, starting from the first occurrence
+	// of a record, during which identical records are suppressed.  It must be
+	// positive.
+	Window time.Duration
+
+	// Burst is the number of identical records that are passed to Handler
+	// within a window before the rest are suppressed.  If Burst is zero, one
+	// is used.
+	Burst int
+}
+
+// DedupHandler is a slog.Handler that suppresses identical records, which are
+// the ones with the same level, message, and attributes, within a window.
+// Once the window is over, it emits a summary record with a message like
+// "message repeated 42 times: original message", if any records have been
+// suppressed.
+//
+// The summaries are emitted from a separate goroutine.  Call Close to emit the
+// pending summaries immediately and stop the timers, for example before
+// exiting.
+type DedupHandler struct {
+	handler slog.Handler
+	state   *dedupState
+
+	// attrsKey is the part of the deduplication key that contains the
+	// attributes and groups added with WithAttrs and WithGroup.
+	attrsKey string
+}
+
+// dedupState is the state shared between all handlers derived from the same
+// *DedupHandler.
+type dedupState struct {
+	// mu protects entries.
+	mu      *sync.Mutex
+	entries map[string]*dedupEntry
+
+	window time.Duration
+	burst  int
+}
+
+// dedupEntry contains the information about a record within a window.
+type dedupEntry struct {
+	handler    slog.Handler
+	timer      *time.Timer
+	record     slog.Record
+	count      int
+	suppressed int
+}
+
+// type check
+var _ slog.Handler = (*DedupHandler)(nil)
+
+// NewDedupHandler returns a new properly initialized *DedupHandler.  c must not
+// be nil.
+func NewDedupHandler(c *DedupHandlerConfig) (h *DedupHandler) {
+	burst := c.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &DedupHandler{
+		handler: c.Handler,
+		state: &dedupState{
+			mu:      &sync.Mutex{},
+			entries: map[string]*dedupEntry{},
+			window:  c.Window,
+			burst:   burst,
+		},
+	}
+}
+
+// Enabled implements the slog.Handler interface for *DedupHandler.
+func (h *DedupHandler) Enabled(ctx context.Context, lvl slog.Level) (ok bool) {
+	return h.handler.Enabled(ctx, lvl)
+}
+
+// Handle implements the slog.Handler interface for *DedupHandler.
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) (err error) {
+	key := h.key(r)
+
+	s := h.state
+	s.mu.Lock()
+	e, ok := s.entries[key]
+	if !ok {
+		e = &dedupEntry{
+			handler: h.handler,
+			record:  r.Clone(),
+		}
+		e.timer = time.AfterFunc(s.window, func() { s.expire(key) })
+		s.entries[key] = e
+	}
+
+	e.count++
+	suppress := e.count > s.burst
+	if suppress {
+		e.suppressed++
+	}
+	s.mu.Unlock()
+
+	if suppress {
+		return nil
+	}
+
+	return h.handler.Handle(ctx, r)
+}
+
+// key returns the deduplication key for r.
+func (h *DedupHandler) key(r slog.Record) (key string) {
+	b := make([]byte, 0, len(r.Message)+len(h.attrsKey)+32)
+	b = strconv.AppendInt(b, int64(r.Level), 10)
+	b = append(b, ' ')
+	b = append(b, r.Message...)
+	b = append(b, h.attrsKey...)
+	r.Attrs(func(a slog.Attr) (cont bool) {
+		b = appendAttr(b, "", a)
+
+		return true
+	})
+
+	return string(b)
+}
+
+// WithAttrs implements the slog.Handler interface for *DedupHandler.
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) (res slog.Handler) {
+	b := []byte(h.attrsKey)
+	for _, a := range attrs {
+		b = appendAttr(b, "", a)
+	}
+
+	return &DedupHandler{
+		handler:  h.handler.WithAttrs(attrs),
+		state:    h.state,
+		attrsKey: string(b),
+	}
+}
+
+// WithGroup implements the slog.Handler interface for *DedupHandler.
+func (h *DedupHandler) WithGroup(name string) (res slog.Handler) {
+	return &DedupHandler{
+		handler:  h.handler.WithGroup(name),
+		state:    h.state,
+		attrsKey: h.attrsKey + " " + name + ".",
+	}
+}
+
+// Close stops all timers and emits the pending summaries.  It affects all
+// handlers derived from h.  h can still be used after Close.
+func (h *DedupHandler) Close() (err error) {
+	s := h.state
+	s.mu.Lock()
+	entries := s.entries
+	s.entries = map[string]*dedupEntry{}
+	s.mu.Unlock()
+
+	var errs []error
+	for _, e := range entries {
+		e.timer.Stop()
+
+		err = e.emitSummary(context.Background())
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("emitting summaries: %w", errs[0])
+	}
+
+	return nil
+}
+
+// expire removes the entry with the given key and emits its summary, if
+// necessary.
+func (s *dedupState) expire(key string) {
+	s.mu.Lock()
+	e, ok := s.entries[key]
+	delete(s.entries, key)
+	s.mu.Unlock()
+
+	if ok {
+		// There is nothing to do with the error, since this is called from
+		// a timer goroutine.
+		_ = e.emitSummary(context.Background())
+	}
+}
+
+// emitSummary passes the summary record to the handler of e, if any records
+// have been suppressed.
+func (e *dedupEntry) emitSummary(ctx context.Context) (err error) {
+	if e.suppressed == 0 {
+		return nil
+	}
+
+	msg := fmt.Sprintf("message repeated %d times: %s", e.suppressed, e.record.Message)
+	r := slog.NewRecord(time.Now(), e.record.Level, msg, e.record.PC)
+	e.record.Attrs(func(a slog.Attr) (cont bool) {
+		r.AddAttrs(a)
+
+		return true
+	})
+
+	return e.handler.Handle(ctx, r)
+}
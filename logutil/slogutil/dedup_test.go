@@ -0,0 +1,66 @@
+package slogutil_test
+
+import (
+	"bytes"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent use.
+type lockedBuffer struct {
+	mu  *sync.Mutex
+	buf *bytes.Buffer
+}
+
+// Write implements the io.Writer interface for *lockedBuffer.
+func (b *lockedBuffer) Write(p []byte) (n int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// String returns the contents of the buffer.
+func (b *lockedBuffer) String() (s string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestDedupHandler_window(t *testing.T) {
+	t.Parallel()
+
+	buf := &lockedBuffer{
+		mu:  &sync.Mutex{},
+		buf: &bytes.Buffer{},
+	}
+
+	const window = 10 * time.Millisecond
+
+	h := slogutil.NewDedupHandler(&slogutil.DedupHandlerConfig{
+		Handler: slogutil.NewLegacyHandler(buf, nil),
+		Window:  window,
+	})
+
+	l := slog.New(h).With(slogutil.KeyPrefix, "test")
+	for i := 0; i < 10; i++ {
+		l.Info("msg")
+	}
+
+	want := "[info] test: msg\n" +
+		"[info] test: message repeated 9 times: msg\n"
+	assert.Eventually(t, func() (ok bool) {
+		return buf.String() == want
+	}, 100*window, window/2)
+
+	l.Info("msg")
+	assert.Eventually(t, func() (ok bool) {
+		return buf.String() == want+"[info] test: msg\n"
+	}, 100*window, window/2)
+}
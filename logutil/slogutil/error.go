@@ -0,0 +1,28 @@
+package slogutil
+
+import (
+	"log/slog"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// ErrorAttr returns an attribute with the key KeyError for err.  If err's chain
+// contains attributes attached using errors.WithAttrs, the value is a group
+// with the message of err under the key "msg" followed by all these
+// attributes, even if err has been annotated after they were attached.
+// Otherwise, the value is err itself.
+func ErrorAttr(err error) (a slog.Attr) {
+	attrs := errors.Attrs(err)
+	if len(attrs) == 0 {
+		return slog.Any(KeyError, err)
+	}
+
+	groupAttrs := make([]slog.Attr, 0, len(attrs)+1)
+	groupAttrs = append(groupAttrs, slog.String("msg", err.Error()))
+	groupAttrs = append(groupAttrs, attrs...)
+
+	return slog.Attr{
+		Key:   KeyError,
+		Value: slog.GroupValue(groupAttrs...),
+	}
+}
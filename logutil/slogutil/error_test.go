@@ -0,0 +1,52 @@
+package slogutil_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorAttr(t *testing.T) {
+	t.Parallel()
+
+	const testErr errors.Error = "test error"
+
+	testCases := []struct {
+		err  error
+		name string
+		want string
+	}{{
+		err:  testErr,
+		name: "plain",
+		want: `level=INFO msg=test err="test error"` + "\n",
+	}, {
+		err: errors.Annotate(
+			errors.WithAttrs(testErr, slog.String("upstream", "1.2.3.4:53")),
+			"exchanging: %w",
+		),
+		name: "annotated",
+		want: `level=INFO msg=test err.msg="exchanging: test error" ` +
+			`err.upstream=1.2.3.4:53` + "\n",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := &bytes.Buffer{}
+			l := slogutil.New(&slogutil.Config{
+				Output: buf,
+				Format: slogutil.FormatText,
+			})
+
+			l.Info("test", slogutil.ErrorAttr(tc.err))
+
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
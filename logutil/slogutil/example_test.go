@@ -0,0 +1,150 @@
+package slogutil_test
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+)
+
+func ExampleNew_adGuardLegacy() {
+	l := slogutil.New(&slogutil.Config{
+		Output: os.Stdout,
+		Format: slogutil.FormatAdGuardLegacy,
+		Level:  slog.LevelDebug,
+	})
+
+	l.Info("starting", "addr", "127.0.0.1:53")
+
+	dnsLogger := l.With(slogutil.KeyPrefix, "dnsforward")
+	dnsLogger.Debug("exchanging", slog.Group("req", "qtype", "AAAA", "name", "example.com"))
+	dnsLogger.Warn("bad upstream", slogutil.KeyError, "connection refused")
+
+	// Output:
+	//
+	// [info] starting addr=127.0.0.1:53
+	// [debug] dnsforward: exchanging req.qtype=AAAA req.name=example.com
+	// [warn] dnsforward: bad upstream err="connection refused"
+}
+
+func ExampleNew_text() {
+	l := slogutil.New(&slogutil.Config{
+		Output: os.Stdout,
+		Format: slogutil.FormatText,
+	})
+
+	l.Info("starting", "addr", "127.0.0.1:53")
+	l.Debug("not printed")
+
+	// Output:
+	//
+	// level=INFO msg=starting addr=127.0.0.1:53
+}
+
+func ExampleNew_json() {
+	l := slogutil.New(&slogutil.Config{
+		Output: os.Stdout,
+		Format: slogutil.FormatJSON,
+		Level:  slogutil.LevelTrace,
+	})
+
+	l.Info("starting", "addr", "127.0.0.1:53")
+	l.Log(context.Background(), slogutil.LevelTrace, "packet", "len", 42)
+	l.With(slogutil.KeyPrefix, "dnsforward").Warn("bad upstream", slogutil.KeyError, "refused")
+
+	// Output:
+	//
+	// {"level":"info","msg":"starting","addr":"127.0.0.1:53"}
+	// {"level":"trace","msg":"packet","len":42}
+	// {"level":"warn","msg":"bad upstream","prefix":"dnsforward","err":"refused"}
+}
+
+func ExampleNewDiscardLogger() {
+	l := slogutil.NewDiscardLogger()
+
+	l.Info("not printed")
+
+	fmt.Println(l.Enabled(context.Background(), slog.LevelError))
+
+	// Output:
+	//
+	// false
+}
+
+func ExampleParseLevel() {
+	for _, s := range []string{"trace", "DEBUG", "warning", "error+2", "bad"} {
+		lvl, err := slogutil.ParseLevel(s)
+		if err != nil {
+			fmt.Println(err)
+
+			continue
+		}
+
+		fmt.Println(lvl)
+	}
+
+	// Output:
+	//
+	// DEBUG-4
+	// DEBUG
+	// WARN
+	// ERROR+2
+	// parsing level "bad": slog: level string "bad": unknown name
+}
+
+func ExampleContextWithLogger() {
+	handler := func(ctx context.Context) {
+		l := slogutil.LoggerFromContextOrDefault(ctx)
+		l.Info("handling")
+	}
+
+	l := slogutil.New(&slogutil.Config{
+		Output: os.Stdout,
+		Format: slogutil.FormatAdGuardLegacy,
+	})
+
+	ctx := slogutil.ContextWithLogger(context.Background(), l.With(slogutil.KeyPrefix, "http"))
+	ctx, reqLogger := slogutil.ContextWithAttrs(ctx, "req_id", 123)
+	reqLogger.Info("request started")
+
+	handler(ctx)
+
+	_, ok := slogutil.LoggerFromContext(context.Background())
+	fmt.Println("found in empty context:", ok)
+
+	// Output:
+	//
+	// [info] http: request started req_id=123
+	// [info] http: handling req_id=123
+	// found in empty context: false
+}
+
+func ExampleDedupHandler() {
+	h := slogutil.NewDedupHandler(&slogutil.DedupHandlerConfig{
+		Handler: slogutil.NewLegacyHandler(os.Stdout, nil),
+		Window:  time.Hour,
+		Burst:   2,
+	})
+
+	l := slog.New(h)
+	for i := 0; i < 1000; i++ {
+		l.Error("upstream failed", "upstream", "1.2.3.4:53")
+	}
+
+	l.Error("upstream failed", "upstream", "5.6.7.8:53")
+
+	err := h.Close()
+	if err != nil {
+		panic(err)
+	}
+
+	// Output:
+	//
+	// [error] upstream failed upstream=1.2.3.4:53
+	// [error] upstream failed upstream=1.2.3.4:53
+	// [error] upstream failed upstream=5.6.7.8:53
+	// [error] message repeated 998 times: upstream failed upstream=1.2.3.4:53
+}
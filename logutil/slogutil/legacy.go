@@ -0,0 +1,220 @@
+package slogutil
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// legacyTimeFormat is the format of timestamps used by the legacy AdGuard log
+// package with its default flags.
+const legacyTimeFormat = "2006/01/02 15:04:05"
+
+// LegacyHandlerOptions are the options for a LegacyHandler.
+type LegacyHandlerOptions struct {
+	// Level is the minimum level of the records that are handled.  If nil,
+	// slog.LevelInfo is used.
+	Level slog.Leveler
+
+	// AddTimestamp, if true, adds a timestamp to every record.
+	AddTimestamp bool
+}
+
+// LegacyHandler is a slog.Handler that uses the format of the legacy AdGuard
+// log package:
+//
+//   2006/01/02 15:04:05 [info] prefix: message key1=value1 key2="value 2"
+//
+// The timestamp is only printed if LegacyHandlerOptions.AddTimestamp is true.
+// The prefix is the value of the attribute with the key KeyPrefix, if any.
+// Attributes inside groups have their keys prefixed with the names of the
+// groups, separated by dots.
+type LegacyHandler struct {
+	level slog.Leveler
+
+	// mu protects w.  It is shared between all handlers derived from the same
+	// one.
+	mu *sync.Mutex
+	w  io.Writer
+
+	prefix      string
+	groupPrefix string
+
+	// attrs are the preformatted attributes, each starting with a space.
+	attrs []byte
+
+	addTimestamp bool
+}
+
+// NewLegacyHandler creates a new properly initialized *LegacyHandler that
+// writes to w.  If opts is nil, the default values are used.
+func NewLegacyHandler(w io.Writer, opts *LegacyHandlerOptions) (h *LegacyHandler) {
+	if opts == nil {
+		opts = &LegacyHandlerOptions{}
+	}
+
+	lvl := opts.Level
+	if lvl == nil {
+		lvl = slog.LevelInfo
+	}
+
+	return &LegacyHandler{
+		level:        lvl,
+		mu:           &sync.Mutex{},
+		w:            w,
+		addTimestamp: opts.AddTimestamp,
+	}
+}
+
+// type check
+var _ slog.Handler = (*LegacyHandler)(nil)
+
+// Enabled implements the slog.Handler interface for *LegacyHandler.
+func (h *LegacyHandler) Enabled(_ context.Context, lvl slog.Level) (ok bool) {
+	return lvl >= h.level.Level()
+}
+
+// Handle implements the slog.Handler interface for *LegacyHandler.
+func (h *LegacyHandler) Handle(_ context.Context, r slog.Record) (err error) {
+	b := h.appendRecord(nil, r, true)
+	b = append(b, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err = h.w.Write(b)
+
+	return err
+}
+
+// appendRecord appends the formatted record to b.  If withMeta is false, the
+// timestamp and the level are omitted, which is useful when they are conveyed
+// in some other way.  The result has no trailing newline.
+func (h *LegacyHandler) appendRecord(b []byte, r slog.Record, withMeta bool) (res []byte) {
+	prefix := h.prefix
+	var attrs []byte
+	r.Attrs(func(a slog.Attr) (cont bool) {
+		if h.groupPrefix == "" && a.Key == KeyPrefix {
+			prefix = a.Value.String()
+		} else {
+			attrs = appendAttr(attrs, h.groupPrefix, a)
+		}
+
+		return true
+	})
+
+	if withMeta {
+		if h.addTimestamp && !r.Time.IsZero() {
+			b = r.Time.AppendFormat(b, legacyTimeFormat)
+			b = append(b, ' ')
+		}
+
+		b = append(b, '[')
+		b = append(b, levelString(r.Level)...)
+		b = append(b, "] "...)
+	}
+
+	if prefix != "" {
+		b = append(b, prefix...)
+		b = append(b, ": "...)
+	}
+
+	b = append(b, r.Message...)
+	b = append(b, h.attrs...)
+
+	return append(b, attrs...)
+}
+
+// WithAttrs implements the slog.Handler interface for *LegacyHandler.
+func (h *LegacyHandler) WithAttrs(attrs []slog.Attr) (res slog.Handler) {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	clone := h.clone()
+	for _, a := range attrs {
+		if h.groupPrefix == "" && a.Key == KeyPrefix {
+			clone.prefix = a.Value.String()
+		} else {
+			clone.attrs = appendAttr(clone.attrs, h.groupPrefix, a)
+		}
+	}
+
+	return clone
+}
+
+// WithGroup implements the slog.Handler interface for *LegacyHandler.
+func (h *LegacyHandler) WithGroup(name string) (res slog.Handler) {
+	if name == "" {
+		return h
+	}
+
+	clone := h.clone()
+	clone.groupPrefix += name + "."
+
+	return clone
+}
+
+// clone returns a copy of h with its own copy of the preformatted attributes.
+func (h *LegacyHandler) clone() (clone *LegacyHandler) {
+	c := *h
+	c.attrs = append([]byte(nil), h.attrs...)
+
+	return &c
+}
+
+// appendAttr appends a formatted attribute a to b, prefixing its key with
+// groupPrefix.  Empty attributes and groups are ignored, and attributes from
+// groups with empty keys are inlined, as required by slog.Handler.
+func appendAttr(b []byte, groupPrefix string, a slog.Attr) (res []byte) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return b
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+
+		for _, ga := range a.Value.Group() {
+			b = appendAttr(b, groupPrefix, ga)
+		}
+
+		return b
+	}
+
+	b = append(b, ' ')
+	b = appendString(b, groupPrefix+a.Key)
+	b = append(b, '=')
+
+	return appendString(b, a.Value.String())
+}
+
+// appendString appends s to b, quoting it if necessary.
+func appendString(b []byte, s string) (res []byte) {
+	if needsQuoting(s) {
+		return strconv.AppendQuote(b, s)
+	}
+
+	return append(b, s...)
+}
+
+// needsQuoting returns true if s must be quoted to be unambiguously parsed.
+func needsQuoting(s string) (ok bool) {
+	if s == "" {
+		return true
+	}
+
+	for _, r := range s {
+		if r == '=' || r == '"' || r == utf8.RuneError || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,37 @@
+package slogutil_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyHandler(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	h := slogutil.NewLegacyHandler(buf, &slogutil.LegacyHandlerOptions{
+		Level:        slogutil.LevelTrace,
+		AddTimestamp: true,
+	})
+
+	l := slog.New(h).With(slogutil.KeyPrefix, "test").WithGroup("g").With("a", 1)
+
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	r := slog.NewRecord(ts, slogutil.LevelTrace, "msg with spaces", 0)
+	r.AddAttrs(slog.String("b", ""), slog.Group("", slog.Int("c", 3)), slog.Attr{})
+
+	err := l.Handler().Handle(context.Background(), r)
+	require.NoError(t, err)
+
+	want := `2023/01/02 03:04:05 [trace] test: msg with spaces g.a=1 g.b="" g.c=3` + "\n"
+	assert.Equal(t, want, buf.String())
+
+	assert.False(t, h.Enabled(context.Background(), slogutil.LevelTrace-1))
+}
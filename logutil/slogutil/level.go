@@ -0,0 +1,50 @@
+package slogutil
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// LevelTrace is the level for very verbose messages, such as the ones logged on
+// every received packet.  It is lower than slog.LevelDebug.
+const LevelTrace = slog.LevelDebug - 4
+
+// ParseLevel parses a logging level from s.  In addition to the names accepted
+// by slog.Level.UnmarshalText, such as "INFO" or "WARN+2", it accepts "trace"
+// and "warning".  The names are case-insensitive.
+func ParseLevel(s string) (lvl slog.Level, err error) {
+	switch strings.ToLower(s) {
+	case "trace":
+		return LevelTrace, nil
+	case "warning":
+		return slog.LevelWarn, nil
+	}
+
+	err = lvl.UnmarshalText([]byte(s))
+	if err != nil {
+		return 0, fmt.Errorf("parsing level %q: %w", s, err)
+	}
+
+	return lvl, nil
+}
+
+// VerbosityToLevel returns slog.LevelDebug if verbose is true and
+// slog.LevelInfo otherwise.  It is a helper for migrating from the legacy log
+// package, which is configured using a verbose flag.
+func VerbosityToLevel(verbose bool) (lvl slog.Level) {
+	if verbose {
+		return slog.LevelDebug
+	}
+
+	return slog.LevelInfo
+}
+
+// levelString returns the lowercase name of lvl for the legacy format.
+func levelString(lvl slog.Level) (s string) {
+	if lvl == LevelTrace {
+		return "trace"
+	}
+
+	return strings.ToLower(lvl.String())
+}
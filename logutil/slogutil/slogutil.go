@@ -0,0 +1,184 @@
+// Package slogutil contains extensions and utilities for package log/slog from
+// the standard library.
+package slogutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Attribute keys.
+const (
+	// KeyPrefix is the key for log prefixes, which are usually the names of
+	// modules or subsystems.  LegacyHandler prints them before the message.
+	KeyPrefix = "prefix"
+
+	// KeyError is the key for errors.
+	KeyError = "err"
+)
+
+// Format is a supported format for the loggers created by New.
+type Format string
+
+// Valid formats.
+const (
+	// FormatAdGuardLegacy is the format of the legacy AdGuard log package.
+	// See LegacyHandler.
+	FormatAdGuardLegacy Format = "adguard_legacy"
+
+	// FormatJSON is the JSON format of package log/slog with one object per
+	// record, suitable for ingestion by log aggregators.  The levels are
+	// lowercase, like in FormatAdGuardLegacy, and the timestamps, if enabled,
+	// are in the RFC 3339 format with milliseconds in UTC.  See
+	// slog.JSONHandler.
+	FormatJSON Format = "json"
+
+	// FormatText is the text format of package log/slog.  See
+	// slog.TextHandler.
+	FormatText Format = "text"
+)
+
+// NewFormat returns a new valid format.
+func NewFormat(s string) (f Format, err error) {
+	switch f = Format(s); f {
+	case FormatAdGuardLegacy, FormatJSON, FormatText:
+		return f, nil
+	default:
+		return "", fmt.Errorf("bad log format %q", s)
+	}
+}
+
+// Config contains the configuration for a logger.
+type Config struct {
+	// Output is the output destination.  If not set, io.Discard is used.
+	Output io.Writer
+
+	// Format is the format for the logs.  If not set, FormatAdGuardLegacy is
+	// used.
+	Format Format
+
+	// Level is the minimum level of the records that are logged.  If not set,
+	// slog.LevelInfo is used.
+	Level slog.Leveler
+
+	// AddTimestamp, if true, adds a timestamp to every record.
+	AddTimestamp bool
+}
+
+// New creates a new logger with the given configuration.  If c is nil, the
+// default values are used.
+func New(c *Config) (l *slog.Logger) {
+	if c == nil {
+		c = &Config{}
+	}
+
+	output := c.Output
+	if output == nil {
+		output = io.Discard
+	}
+
+	lvl := c.Level
+	if lvl == nil {
+		lvl = slog.LevelInfo
+	}
+
+	var h slog.Handler
+	switch c.Format {
+	case FormatJSON:
+		h = slog.NewJSONHandler(output, &slog.HandlerOptions{
+			Level:       lvl,
+			ReplaceAttr: newJSONReplaceAttr(!c.AddTimestamp),
+		})
+	case FormatText:
+		h = slog.NewTextHandler(output, &slog.HandlerOptions{
+			Level:       lvl,
+			ReplaceAttr: newReplaceAttr(!c.AddTimestamp),
+		})
+	default:
+		h = NewLegacyHandler(output, &LegacyHandlerOptions{
+			Level:        lvl,
+			AddTimestamp: c.AddTimestamp,
+		})
+	}
+
+	return slog.New(h)
+}
+
+// newReplaceAttr returns a function for slog.HandlerOptions.ReplaceAttr that
+// removes the time attribute if removeTime is true.
+func newReplaceAttr(removeTime bool) (f func(groups []string, a slog.Attr) (res slog.Attr)) {
+	if !removeTime {
+		return nil
+	}
+
+	return func(groups []string, a slog.Attr) (res slog.Attr) {
+		if len(groups) == 0 && a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+
+		return a
+	}
+}
+
+// jsonTimeFormat is the format of timestamps in FormatJSON.
+const jsonTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// newJSONReplaceAttr returns a function for slog.HandlerOptions.ReplaceAttr
+// that applies the conventions of FormatJSON and removes the time attribute if
+// removeTime is true.
+func newJSONReplaceAttr(removeTime bool) (f func(groups []string, a slog.Attr) (res slog.Attr)) {
+	return func(groups []string, a slog.Attr) (res slog.Attr) {
+		if len(groups) > 0 {
+			return a
+		}
+
+		switch a.Key {
+		case slog.TimeKey:
+			if removeTime {
+				return slog.Attr{}
+			}
+
+			t := a.Value.Time().UTC()
+
+			return slog.String(slog.TimeKey, t.Format(jsonTimeFormat))
+		case slog.LevelKey:
+			lvl, ok := a.Value.Any().(slog.Level)
+			if !ok {
+				return a
+			}
+
+			return slog.String(slog.LevelKey, levelString(lvl))
+		default:
+			return a
+		}
+	}
+}
+
+// NewDiscardLogger returns a new logger that discards all records.
+func NewDiscardLogger() (l *slog.Logger) {
+	return slog.New(discardHandler{})
+}
+
+// discardHandler is a slog.Handler that discards all records.
+type discardHandler struct{}
+
+// type check
+var _ slog.Handler = discardHandler{}
+
+// Enabled implements the slog.Handler interface for discardHandler.  It always
+// returns false.
+func (discardHandler) Enabled(_ context.Context, _ slog.Level) (ok bool) { return false }
+
+// Handle implements the slog.Handler interface for discardHandler.  It always
+// returns nil.
+func (discardHandler) Handle(_ context.Context, _ slog.Record) (err error) { return nil }
+
+// WithAttrs implements the slog.Handler interface for discardHandler.  It
+// always returns itself.
+func (h discardHandler) WithAttrs(_ []slog.Attr) (res slog.Handler) { return h }
+
+// WithGroup implements the slog.Handler interface for discardHandler.  It
+// always returns itself.
+func (h discardHandler) WithGroup(_ string) (res slog.Handler) { return h }
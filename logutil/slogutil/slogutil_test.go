@@ -0,0 +1,33 @@
+package slogutil_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_jsonTimestamp(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := slogutil.New(&slogutil.Config{
+		Output:       buf,
+		Format:       slogutil.FormatJSON,
+		AddTimestamp: true,
+	})
+
+	ts := time.Date(2023, 1, 2, 3, 4, 5, 6_000_000, time.FixedZone("", 3600))
+	r := slog.NewRecord(ts, slog.LevelError, "msg", 0)
+
+	err := l.Handler().Handle(context.Background(), r)
+	require.NoError(t, err)
+
+	want := `{"time":"2023-01-02T02:04:05.006Z","level":"error","msg":"msg"}` + "\n"
+	assert.Equal(t, want, buf.String())
+}
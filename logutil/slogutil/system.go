@@ -0,0 +1,221 @@
+package slogutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// journaldSocket is the path to the socket of the native protocol of the
+// systemd journal.
+const journaldSocket = "/run/systemd/journal/socket"
+
+// systemSender sends formatted messages to the system log.
+type systemSender interface {
+	io.Closer
+
+	// send sends msg with the priority corresponding to lvl.
+	send(lvl slog.Level, msg []byte) (err error)
+}
+
+// SystemHandlerConfig is the configuration for a *SystemHandler.
+type SystemHandlerConfig struct {
+	// Level is the minimum level of the records that are handled.  If nil,
+	// slog.LevelInfo is used.
+	Level slog.Leveler
+
+	// Tag is the identifier of the program in the system log.  If empty,
+	// the base name of the executable is used.
+	Tag string
+}
+
+// SystemHandler is a slog.Handler that sends records to the systemd journal,
+// if it is available, or to the local syslog daemon otherwise.  The levels of
+// the records are converted into the corresponding priorities, and the rest of
+// the record is formatted in the same way as by LegacyHandler.
+type SystemHandler struct {
+	// legacy is used to format the records.  Its writer is never used.
+	legacy *LegacyHandler
+	sender systemSender
+}
+
+// type check
+var _ slog.Handler = (*SystemHandler)(nil)
+
+// NewSystemHandler returns a new *SystemHandler.  If neither the systemd
+// journal nor syslog are available on the current system, err wraps
+// errors.ErrUnsupported, so that callers can fall back to another handler:
+//
+//   h, err := slogutil.NewSystemHandler(conf)
+//   if errors.Is(err, errors.ErrUnsupported) {
+//           h = slogutil.NewLegacyHandler(os.Stderr, opts)
+//   } else if err != nil {
+//           // …
+//   }
+//
+// c must not be nil.  Callers should call Close once the handler and the
+// handlers derived from it are no longer used.
+func NewSystemHandler(c *SystemHandlerConfig) (h *SystemHandler, err error) {
+	tag := c.Tag
+	if tag == "" {
+		tag = programName()
+	}
+
+	var sender systemSender
+	sender, err = newJournaldSender(journaldSocket, tag)
+	if err != nil {
+		sender, err = newSyslogSender(tag)
+		if err != nil {
+			return nil, fmt.Errorf("creating system log handler: %w", err)
+		}
+	}
+
+	return &SystemHandler{
+		legacy: NewLegacyHandler(io.Discard, &LegacyHandlerOptions{
+			Level: c.Level,
+		}),
+		sender: sender,
+	}, nil
+}
+
+// programName returns the base name of the current executable.
+func programName() (name string) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "golibs"
+	}
+
+	return filepath.Base(exe)
+}
+
+// Enabled implements the slog.Handler interface for *SystemHandler.
+func (h *SystemHandler) Enabled(ctx context.Context, lvl slog.Level) (ok bool) {
+	return h.legacy.Enabled(ctx, lvl)
+}
+
+// Handle implements the slog.Handler interface for *SystemHandler.
+func (h *SystemHandler) Handle(_ context.Context, r slog.Record) (err error) {
+	return h.sender.send(r.Level, h.legacy.appendRecord(nil, r, false))
+}
+
+// WithAttrs implements the slog.Handler interface for *SystemHandler.
+func (h *SystemHandler) WithAttrs(attrs []slog.Attr) (res slog.Handler) {
+	return &SystemHandler{
+		legacy: h.legacy.WithAttrs(attrs).(*LegacyHandler),
+		sender: h.sender,
+	}
+}
+
+// WithGroup implements the slog.Handler interface for *SystemHandler.
+func (h *SystemHandler) WithGroup(name string) (res slog.Handler) {
+	return &SystemHandler{
+		legacy: h.legacy.WithGroup(name).(*LegacyHandler),
+		sender: h.sender,
+	}
+}
+
+// Close closes the connection to the system log.  It affects all handlers
+// derived from h.
+func (h *SystemHandler) Close() (err error) {
+	return h.sender.Close()
+}
+
+// Syslog priorities.  They are defined here, since package log/syslog is not
+// available on all platforms.
+const (
+	prioErr     = 3
+	prioWarning = 4
+	prioInfo    = 6
+	prioDebug   = 7
+)
+
+// priority returns the syslog priority for lvl.
+func priority(lvl slog.Level) (prio int) {
+	switch {
+	case lvl >= slog.LevelError:
+		return prioErr
+	case lvl >= slog.LevelWarn:
+		return prioWarning
+	case lvl >= slog.LevelInfo:
+		return prioInfo
+	default:
+		return prioDebug
+	}
+}
+
+// journaldSender is a systemSender that uses the native protocol of the
+// systemd journal.
+//
+// See https://systemd.io/JOURNAL_NATIVE_PROTOCOL.
+type journaldSender struct {
+	conn *net.UnixConn
+	tag  string
+}
+
+// type check
+var _ systemSender = (*journaldSender)(nil)
+
+// newJournaldSender returns a new *journaldSender connected to the socket at
+// path.
+func newJournaldSender(path, tag string) (s *journaldSender, err error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{
+		Name: path,
+		Net:  "unixgram",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("connecting to journald: %w", err)
+	}
+
+	return &journaldSender{
+		conn: conn,
+		tag:  tag,
+	}, nil
+}
+
+// send implements the systemSender interface for *journaldSender.  Messages
+// that are too large for a single datagram are not supported.
+func (s *journaldSender) send(lvl slog.Level, msg []byte) (err error) {
+	b := &bytes.Buffer{}
+	appendJournaldField(b, "PRIORITY", []byte(strconv.Itoa(priority(lvl))))
+	appendJournaldField(b, "SYSLOG_IDENTIFIER", []byte(s.tag))
+	appendJournaldField(b, "MESSAGE", msg)
+
+	_, err = s.conn.Write(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("writing to journald: %w", err)
+	}
+
+	return nil
+}
+
+// Close implements the systemSender interface for *journaldSender.
+func (s *journaldSender) Close() (err error) {
+	return s.conn.Close()
+}
+
+// appendJournaldField writes a field in the journald native format to b.
+// Values containing newlines are written in the binary-safe format.
+func appendJournaldField(b *bytes.Buffer, name string, val []byte) {
+	// Here and further, ignore the errors, since bytes.Buffer never returns
+	// them.
+	_, _ = b.WriteString(name)
+	if bytes.IndexByte(val, '\n') < 0 {
+		_ = b.WriteByte('=')
+		_, _ = b.Write(val)
+		_ = b.WriteByte('\n')
+
+		return
+	}
+
+	_ = b.WriteByte('\n')
+	_ = binary.Write(b, binary.LittleEndian, uint64(len(val)))
+	_, _ = b.Write(val)
+	_ = b.WriteByte('\n')
+}
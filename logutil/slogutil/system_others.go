@@ -0,0 +1,15 @@
+//go:build windows || plan9 || js || wasip1
+
+package slogutil
+
+import (
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// newSyslogSender always returns an error wrapping errors.ErrUnsupported, since
+// syslog is not available on this OS.
+func newSyslogSender(_ string) (s systemSender, err error) {
+	return nil, fmt.Errorf("syslog: %w", errors.ErrUnsupported)
+}
@@ -0,0 +1,99 @@
+//go:build !windows && !plan9 && !js && !wasip1
+
+package slogutil
+
+import (
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemHandler_journald(t *testing.T) {
+	t.Parallel()
+
+	sockPath := filepath.Join(t.TempDir(), "journal.sock")
+	srv, err := net.ListenUnixgram("unixgram", &net.UnixAddr{
+		Name: sockPath,
+		Net:  "unixgram",
+	})
+	require.NoError(t, err)
+	testutil.CleanupAndRequireSuccess(t, srv.Close)
+
+	sender, err := newJournaldSender(sockPath, "test")
+	require.NoError(t, err)
+
+	h := &SystemHandler{
+		legacy: NewLegacyHandler(nil, &LegacyHandlerOptions{
+			Level: slog.LevelDebug,
+		}),
+		sender: sender,
+	}
+	testutil.CleanupAndRequireSuccess(t, h.Close)
+
+	l := slog.New(h).With(KeyPrefix, "mod")
+
+	buf := make([]byte, 1024)
+	recv := func() (msg string) {
+		t.Helper()
+
+		require.NoError(t, srv.SetReadDeadline(time.Now().Add(time.Second)))
+
+		n, _, rerr := srv.ReadFromUnix(buf)
+		require.NoError(t, rerr)
+
+		return string(buf[:n])
+	}
+
+	l.Warn("simple", "a", 1)
+	assert.Equal(t, "PRIORITY=4\nSYSLOG_IDENTIFIER=test\nMESSAGE=mod: simple a=1\n", recv())
+
+	l.Debug("multi\nline")
+
+	wantMsg := "mod: multi\nline"
+	lenBytes := binary.LittleEndian.AppendUint64(nil, uint64(len(wantMsg)))
+	want := "PRIORITY=7\nSYSLOG_IDENTIFIER=test\nMESSAGE\n" + string(lenBytes) + wantMsg + "\n"
+	assert.Equal(t, want, recv())
+
+	assert.False(t, h.Enabled(context.Background(), slog.LevelDebug-1))
+}
+
+func TestNewSystemHandler_unsupported(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range []string{"/dev/log", "/var/run/syslog", "/var/run/log"} {
+		if fi, err := os.Stat(p); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			t.Skipf("syslog socket %q exists", p)
+		}
+	}
+
+	h, err := NewSystemHandler(&SystemHandlerConfig{})
+	if err == nil {
+		// The systemd journal is available.
+		testutil.CleanupAndRequireSuccess(t, h.Close)
+
+		return
+	}
+
+	assert.ErrorIs(t, err, errors.ErrUnsupported)
+}
+
+func TestPriority(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, prioDebug, priority(LevelTrace))
+	assert.Equal(t, prioDebug, priority(slog.LevelDebug))
+	assert.Equal(t, prioInfo, priority(slog.LevelInfo))
+	assert.Equal(t, prioWarning, priority(slog.LevelWarn))
+	assert.Equal(t, prioErr, priority(slog.LevelError))
+	assert.Equal(t, prioErr, priority(slog.LevelError+4))
+}
@@ -0,0 +1,53 @@
+//go:build !windows && !plan9 && !js && !wasip1
+
+package slogutil
+
+import (
+	"fmt"
+	"log/slog"
+	"log/syslog"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// syslogSender is a systemSender that uses the local syslog daemon.
+type syslogSender struct {
+	w *syslog.Writer
+}
+
+// type check
+var _ systemSender = (*syslogSender)(nil)
+
+// newSyslogSender returns a new *syslogSender connected to the local syslog
+// daemon.  If there is no daemon, for example in a container, err wraps
+// errors.ErrUnsupported.
+func newSyslogSender(tag string) (s *syslogSender, err error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to syslog: %w: %w", errors.ErrUnsupported, err)
+	}
+
+	return &syslogSender{
+		w: w,
+	}, nil
+}
+
+// send implements the systemSender interface for *syslogSender.
+func (s *syslogSender) send(lvl slog.Level, msg []byte) (err error) {
+	m := string(msg)
+	switch priority(lvl) {
+	case prioErr:
+		return s.w.Err(m)
+	case prioWarning:
+		return s.w.Warning(m)
+	case prioInfo:
+		return s.w.Info(m)
+	default:
+		return s.w.Debug(m)
+	}
+}
+
+// Close implements the systemSender interface for *syslogSender.
+func (s *syslogSender) Close() (err error) {
+	return s.w.Close()
+}
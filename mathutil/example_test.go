@@ -0,0 +1,19 @@
+package mathutil_test
+
+import (
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/mathutil"
+)
+
+func ExampleQuantile() {
+	q := mathutil.NewQuantile(0.9)
+	for i := 1; i <= 100; i++ {
+		q.Add(float64(i))
+	}
+
+	fmt.Printf("%.0f\n", q.Value())
+
+	// Output:
+	// 90
+}
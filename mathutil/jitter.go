@@ -0,0 +1,41 @@
+package mathutil
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RandFunc returns a pseudo-random number in the half-open interval [0.0, 1.0).
+// math/rand.Float64 is such a function.
+type RandFunc = func() (f float64)
+
+// randOrDefault returns rnd or, if it is nil, math/rand.Float64.
+func randOrDefault(rnd RandFunc) (res RandFunc) {
+	if rnd == nil {
+		return rand.Float64
+	}
+
+	return rnd
+}
+
+// FullJitter returns a random duration in the half-open interval [0, base).  If
+// rnd is nil, math/rand.Float64 is used.
+func FullJitter(base time.Duration, rnd RandFunc) (d time.Duration) {
+	return time.Duration(float64(base) * randOrDefault(rnd)())
+}
+
+// JitterPercent returns a random duration which differs from base by no more
+// than pct percent of it in either direction.  The result is never negative.
+// If rnd is nil, math/rand.Float64 is used.  For example, to spread the refresh
+// of a cache with a one-hour period by six minutes either way:
+//
+//	next := mathutil.JitterPercent(1*time.Hour, 10, nil)
+func JitterPercent(base time.Duration, pct uint, rnd RandFunc) (d time.Duration) {
+	delta := float64(base) * float64(pct) / 100 * (2*randOrDefault(rnd)() - 1)
+	d = time.Duration(float64(base) + delta)
+	if d < 0 {
+		return 0
+	}
+
+	return d
+}
@@ -0,0 +1,79 @@
+package mathutil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/mathutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// constRand returns a mathutil.RandFunc that always returns f.
+func constRand(f float64) (rnd mathutil.RandFunc) {
+	return func() (res float64) { return f }
+}
+
+func TestFullJitter(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, time.Duration(0), mathutil.FullJitter(time.Second, constRand(0)))
+	assert.Equal(t, 500*time.Millisecond, mathutil.FullJitter(time.Second, constRand(0.5)))
+
+	for i := 0; i < 100; i++ {
+		d := mathutil.FullJitter(time.Second, nil)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.Less(t, d, time.Second)
+	}
+}
+
+func TestJitterPercent(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		rnd  float64
+		pct  uint
+		want time.Duration
+	}{{
+		name: "lowest",
+		rnd:  0,
+		pct:  10,
+		want: 900 * time.Millisecond,
+	}, {
+		name: "middle",
+		rnd:  0.5,
+		pct:  10,
+		want: time.Second,
+	}, {
+		name: "high",
+		rnd:  0.75,
+		pct:  10,
+		want: 1050 * time.Millisecond,
+	}, {
+		name: "no_jitter",
+		rnd:  0.9,
+		pct:  0,
+		want: time.Second,
+	}, {
+		name: "never_negative",
+		rnd:  0,
+		pct:  200,
+		want: 0,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := mathutil.JitterPercent(time.Second, tc.pct, constRand(tc.rnd))
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	for i := 0; i < 100; i++ {
+		d := mathutil.JitterPercent(time.Second, 20, nil)
+		assert.GreaterOrEqual(t, d, 800*time.Millisecond)
+		assert.LessOrEqual(t, d, 1200*time.Millisecond)
+	}
+}
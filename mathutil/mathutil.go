@@ -0,0 +1,3 @@
+// Package mathutil contains helpers for common mathematical operations, such as
+// running statistics and overflow-aware arithmetic.
+package mathutil
@@ -0,0 +1,126 @@
+package mathutil
+
+import "unsafe"
+
+// Signed is a constraint for the signed integer types.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned is a constraint for the unsigned integer types.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Integer is a constraint for all integer types.
+type Integer interface {
+	Signed | Unsigned
+}
+
+// isSigned returns true if T is a signed integer type.
+func isSigned[T Integer]() (ok bool) {
+	return ^T(0) < 0
+}
+
+// maxValue returns the maximum value of T.
+func maxValue[T Integer]() (v T) {
+	if !isSigned[T]() {
+		return ^T(0)
+	}
+
+	bits := unsafe.Sizeof(v) * 8
+	v = 1
+
+	// Shift into the sign bit and wrap around to get the maximum.
+	return v<<(bits-1) - 1
+}
+
+// minValue returns the minimum value of T.
+func minValue[T Integer]() (v T) {
+	if !isSigned[T]() {
+		return 0
+	}
+
+	return ^maxValue[T]()
+}
+
+// CheckedAdd returns a + b.  overflow is true if the result has wrapped around,
+// in which case sum is the wrapped value.
+func CheckedAdd[T Integer](a, b T) (sum T, overflow bool) {
+	sum = a + b
+	if isSigned[T]() {
+		return sum, (b > 0 && sum < a) || (b < 0 && sum > a)
+	}
+
+	return sum, sum < a
+}
+
+// CheckedSub returns a - b.  overflow is true if the result has wrapped around,
+// in which case diff is the wrapped value.
+func CheckedSub[T Integer](a, b T) (diff T, overflow bool) {
+	diff = a - b
+	if isSigned[T]() {
+		return diff, (b > 0 && diff > a) || (b < 0 && diff < a)
+	}
+
+	return diff, b > a
+}
+
+// CheckedMul returns a * b.  overflow is true if the result has wrapped around,
+// in which case prod is the wrapped value.
+func CheckedMul[T Integer](a, b T) (prod T, overflow bool) {
+	if a == 0 || b == 0 {
+		return 0, false
+	}
+
+	prod = a * b
+	if isSigned[T]() {
+		// The quotient check below doesn't catch this case, since the
+		// division wraps around as well.
+		negOne, minVal := ^T(0), minValue[T]()
+		if (a == negOne && b == minVal) || (b == negOne && a == minVal) {
+			return prod, true
+		}
+	}
+
+	return prod, prod/b != a
+}
+
+// SaturatingAdd returns a + b or, if the result overflows, the maximum or the
+// minimum value of T, whichever is closer to the actual result.
+func SaturatingAdd[T Integer](a, b T) (sum T) {
+	sum, overflow := CheckedAdd(a, b)
+	if !overflow {
+		return sum
+	} else if b < 0 {
+		return minValue[T]()
+	}
+
+	return maxValue[T]()
+}
+
+// SaturatingSub returns a - b or, if the result overflows, the maximum or the
+// minimum value of T, whichever is closer to the actual result.
+func SaturatingSub[T Integer](a, b T) (diff T) {
+	diff, overflow := CheckedSub(a, b)
+	if !overflow {
+		return diff
+	} else if b < 0 {
+		return maxValue[T]()
+	}
+
+	return minValue[T]()
+}
+
+// SaturatingMul returns a * b or, if the result overflows, the maximum or the
+// minimum value of T, whichever is closer to the actual result.
+func SaturatingMul[T Integer](a, b T) (prod T) {
+	prod, overflow := CheckedMul(a, b)
+	if !overflow {
+		return prod
+	} else if (a < 0) != (b < 0) {
+		return minValue[T]()
+	}
+
+	return maxValue[T]()
+}
@@ -0,0 +1,146 @@
+package mathutil_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/mathutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckedAdd(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		a            int8
+		b            int8
+		want         int8
+		wantOverflow bool
+	}{{
+		name:         "simple",
+		a:            1,
+		b:            2,
+		want:         3,
+		wantOverflow: false,
+	}, {
+		name:         "negative",
+		a:            -100,
+		b:            -28,
+		want:         math.MinInt8,
+		wantOverflow: false,
+	}, {
+		name:         "overflow",
+		a:            math.MaxInt8,
+		b:            1,
+		want:         math.MinInt8,
+		wantOverflow: true,
+	}, {
+		name:         "underflow",
+		a:            math.MinInt8,
+		b:            -1,
+		want:         math.MaxInt8,
+		wantOverflow: true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, overflow := mathutil.CheckedAdd(tc.a, tc.b)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.wantOverflow, overflow)
+		})
+	}
+
+	_, overflow := mathutil.CheckedAdd[uint8](math.MaxUint8, 1)
+	assert.True(t, overflow)
+
+	_, overflow = mathutil.CheckedAdd[uint64](math.MaxUint64-1, 1)
+	assert.False(t, overflow)
+}
+
+func TestCheckedSub(t *testing.T) {
+	t.Parallel()
+
+	_, overflow := mathutil.CheckedSub[int8](math.MinInt8, 1)
+	assert.True(t, overflow)
+
+	_, overflow = mathutil.CheckedSub[int8](0, math.MinInt8)
+	assert.True(t, overflow)
+
+	got, overflow := mathutil.CheckedSub[int8](-1, math.MinInt8)
+	assert.False(t, overflow)
+	assert.Equal(t, int8(math.MaxInt8), got)
+
+	_, overflow = mathutil.CheckedSub[uint](1, 2)
+	assert.True(t, overflow)
+}
+
+func TestCheckedMul(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		a            int16
+		b            int16
+		wantOverflow bool
+	}{{
+		name:         "zero",
+		a:            0,
+		b:            math.MinInt16,
+		wantOverflow: false,
+	}, {
+		name:         "simple",
+		a:            -128,
+		b:            256,
+		wantOverflow: false,
+	}, {
+		name:         "overflow",
+		a:            256,
+		b:            128,
+		wantOverflow: true,
+	}, {
+		name:         "min_neg_one",
+		a:            math.MinInt16,
+		b:            -1,
+		wantOverflow: true,
+	}, {
+		name:         "neg_one_min",
+		a:            -1,
+		b:            math.MinInt16,
+		wantOverflow: true,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, overflow := mathutil.CheckedMul(tc.a, tc.b)
+			assert.Equal(t, tc.wantOverflow, overflow)
+			if !overflow {
+				assert.Equal(t, int(tc.a)*int(tc.b), int(got))
+			}
+		})
+	}
+}
+
+func TestSaturating(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, int8(math.MaxInt8), mathutil.SaturatingAdd[int8](100, 100))
+	assert.Equal(t, int8(math.MinInt8), mathutil.SaturatingAdd[int8](-100, -100))
+	assert.Equal(t, uint8(math.MaxUint8), mathutil.SaturatingAdd[uint8](200, 100))
+	assert.Equal(t, 3, mathutil.SaturatingAdd(1, 2))
+
+	assert.Equal(t, int8(math.MaxInt8), mathutil.SaturatingSub[int8](100, -100))
+	assert.Equal(t, int8(math.MinInt8), mathutil.SaturatingSub[int8](-100, 100))
+	assert.Equal(t, uint(0), mathutil.SaturatingSub[uint](1, 2))
+
+	assert.Equal(t, int32(math.MaxInt32), mathutil.SaturatingMul[int32](math.MinInt32, -1))
+	assert.Equal(t, int32(math.MinInt32), mathutil.SaturatingMul[int32](math.MaxInt32, -2))
+	assert.Equal(t, int64(math.MaxInt64), mathutil.SaturatingMul[int64](-1<<62, -4))
+	assert.Equal(t, uint64(math.MaxUint64), mathutil.SaturatingMul[uint64](1<<32, 1<<32))
+}
@@ -0,0 +1,189 @@
+package mathutil
+
+import (
+	"math"
+	"slices"
+)
+
+// EWMA is an exponentially weighted moving average.  It is not safe for
+// concurrent use.
+type EWMA struct {
+	alpha float64
+	value float64
+	set   bool
+}
+
+// NewEWMA returns a new *EWMA with the smoothing factor alpha, which must be in
+// the range (0, 1].  The greater alpha is, the faster older values are
+// discounted.
+func NewEWMA(alpha float64) (e *EWMA) {
+	if !(alpha > 0 && alpha <= 1) {
+		panic("mathutil: EWMA alpha out of range (0, 1]")
+	}
+
+	return &EWMA{
+		alpha: alpha,
+	}
+}
+
+// Add adds a new observation.  The first observation becomes the value of the
+// average as is.
+func (e *EWMA) Add(v float64) {
+	if !e.set {
+		e.value, e.set = v, true
+
+		return
+	}
+
+	e.value += e.alpha * (v - e.value)
+}
+
+// Value returns the current value of the average.  It returns zero if there
+// have been no observations.
+func (e *EWMA) Value() (v float64) {
+	return e.value
+}
+
+// Reset removes all observations.
+func (e *EWMA) Reset() {
+	e.value, e.set = 0, false
+}
+
+// quantileMarkers is the number of markers used by the P² algorithm.
+const quantileMarkers = 5
+
+// Quantile is an estimator of a single quantile of a stream of observations,
+// which uses a constant amount of memory.  It implements the P² algorithm by
+// R. Jain and I. Chlamtac.  The estimate is exact for the first five
+// observations.  It is not safe for concurrent use.
+type Quantile struct {
+	// heights are the heights of the markers.
+	heights [quantileMarkers]float64
+
+	// pos are the actual positions of the markers.
+	pos [quantileMarkers]float64
+
+	// desired are the desired positions of the markers.
+	desired [quantileMarkers]float64
+
+	// incr are the increments of the desired positions.
+	incr [quantileMarkers]float64
+
+	p     float64
+	count int
+}
+
+// NewQuantile returns a new *Quantile estimating the quantile p, which must be
+// in the range [0, 1].  For example, p is 0.99 for the 99th percentile.
+func NewQuantile(p float64) (q *Quantile) {
+	if !(p >= 0 && p <= 1) {
+		panic("mathutil: quantile out of range [0, 1]")
+	}
+
+	q = &Quantile{
+		p:    p,
+		incr: [quantileMarkers]float64{0, p / 2, p, (1 + p) / 2, 1},
+	}
+
+	q.Reset()
+
+	return q
+}
+
+// Reset removes all observations.
+func (q *Quantile) Reset() {
+	p := q.p
+	q.count = 0
+	q.pos = [quantileMarkers]float64{0, 1, 2, 3, 4}
+	q.desired = [quantileMarkers]float64{0, 2 * p, 4 * p, 2 + 2*p, 4}
+}
+
+// Count returns the number of observations.
+func (q *Quantile) Count() (n int) {
+	return q.count
+}
+
+// Add adds a new observation.
+func (q *Quantile) Add(x float64) {
+	if q.count < quantileMarkers {
+		q.heights[q.count] = x
+		q.count++
+		if q.count == quantileMarkers {
+			slices.Sort(q.heights[:])
+		}
+
+		return
+	}
+
+	q.count++
+
+	h := &q.heights
+	var k int
+	switch {
+	case x < h[0]:
+		h[0], k = x, 0
+	case x >= h[quantileMarkers-1]:
+		h[quantileMarkers-1], k = x, quantileMarkers-2
+	default:
+		for k = 0; k < quantileMarkers-2 && x >= h[k+1]; k++ {
+		}
+	}
+
+	for i := k + 1; i < quantileMarkers; i++ {
+		q.pos[i]++
+	}
+
+	for i := range q.desired {
+		q.desired[i] += q.incr[i]
+	}
+
+	for i := 1; i < quantileMarkers-1; i++ {
+		q.adjust(i)
+	}
+}
+
+// adjust moves the marker i if it is too far from its desired position.
+func (q *Quantile) adjust(i int) {
+	h, n := &q.heights, &q.pos
+
+	d := q.desired[i] - n[i]
+	if !(d >= 1 && n[i+1]-n[i] > 1) && !(d <= -1 && n[i-1]-n[i] < -1) {
+		return
+	}
+
+	d = math.Copysign(1, d)
+
+	// Try the piecewise-parabolic prediction first and fall back to the linear
+	// one if it breaks the monotonicity of the heights.
+	hp := h[i] + d/(n[i+1]-n[i-1])*
+		((n[i]-n[i-1]+d)*(h[i+1]-h[i])/(n[i+1]-n[i])+
+			(n[i+1]-n[i]-d)*(h[i]-h[i-1])/(n[i]-n[i-1]))
+	if h[i-1] < hp && hp < h[i+1] {
+		h[i] = hp
+	} else {
+		j := i + int(d)
+		h[i] += d * (h[j] - h[i]) / (n[j] - n[i])
+	}
+
+	n[i] += d
+}
+
+// Value returns the current estimate of the quantile.  It returns zero if there
+// have been no observations.
+func (q *Quantile) Value() (v float64) {
+	switch {
+	case q.count == 0:
+		return 0
+	case q.count < quantileMarkers:
+		vals := slices.Clone(q.heights[:q.count])
+		slices.Sort(vals)
+
+		return vals[int(math.Round(q.p*float64(q.count-1)))]
+	case q.p == 0:
+		return q.heights[0]
+	case q.p == 1:
+		return q.heights[quantileMarkers-1]
+	default:
+		return q.heights[quantileMarkers/2]
+	}
+}
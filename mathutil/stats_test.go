@@ -0,0 +1,106 @@
+package mathutil_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/mathutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEWMA(t *testing.T) {
+	t.Parallel()
+
+	e := mathutil.NewEWMA(0.5)
+	assert.Zero(t, e.Value())
+
+	e.Add(10)
+	assert.Equal(t, 10.0, e.Value())
+
+	e.Add(20)
+	assert.Equal(t, 15.0, e.Value())
+
+	e.Add(15)
+	assert.Equal(t, 15.0, e.Value())
+
+	e.Reset()
+	assert.Zero(t, e.Value())
+
+	e.Add(1)
+	assert.Equal(t, 1.0, e.Value())
+
+	assert.Panics(t, func() { _ = mathutil.NewEWMA(0) })
+	assert.Panics(t, func() { _ = mathutil.NewEWMA(1.5) })
+}
+
+func TestQuantile(t *testing.T) {
+	t.Parallel()
+
+	const n = 10_000
+
+	r := rand.New(rand.NewSource(1))
+	vals := make([]float64, n)
+	for i := range vals {
+		vals[i] = r.Float64() * 100
+	}
+
+	sorted := slices.Clone(vals)
+	slices.Sort(sorted)
+
+	testCases := []struct {
+		name string
+		p    float64
+	}{{
+		name: "median",
+		p:    0.5,
+	}, {
+		name: "p90",
+		p:    0.9,
+	}, {
+		name: "p99",
+		p:    0.99,
+	}, {
+		name: "min",
+		p:    0,
+	}, {
+		name: "max",
+		p:    1,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			q := mathutil.NewQuantile(tc.p)
+			for _, v := range vals {
+				q.Add(v)
+			}
+
+			want := sorted[int(tc.p*(n-1))]
+			assert.InDelta(t, want, q.Value(), 1)
+			assert.Equal(t, n, q.Count())
+		})
+	}
+}
+
+func TestQuantile_few(t *testing.T) {
+	t.Parallel()
+
+	q := mathutil.NewQuantile(0.5)
+	assert.Zero(t, q.Value())
+
+	q.Add(3)
+	assert.Equal(t, 3.0, q.Value())
+
+	q.Add(1)
+	q.Add(2)
+	assert.Equal(t, 2.0, q.Value())
+
+	q.Reset()
+	assert.Zero(t, q.Count())
+	assert.Zero(t, q.Value())
+
+	assert.Panics(t, func() { _ = mathutil.NewQuantile(-0.1) })
+}
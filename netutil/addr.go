@@ -152,11 +152,92 @@ const MaxDomainLabelLen = 63
 // See also: https://stackoverflow.com/a/32294443/1892060.
 const MaxDomainNameLen = 253
 
+// MaxDomainNameWireLen is the maximum allowed length of a full domain name in
+// the wire format, including the length octets and the root label, according
+// to RFC 1035.
+const MaxDomainNameWireLen = 255
+
+// DomainNameFormat is the format in which the length of a domain name is
+// measured.
+type DomainNameFormat uint8
+
+// Domain name formats for DomainNameOptions.
+const (
+	// DomainNameFormatPresentation means that the length of a domain name is
+	// the number of characters in its textual representation, for example
+	// "example.com".  The default maximum length is MaxDomainNameLen.
+	DomainNameFormatPresentation DomainNameFormat = iota
+
+	// DomainNameFormatWire means that the length of a domain name is the
+	// number of octets in its wire representation, which includes a length
+	// octet for each label and the terminating root label.  The default
+	// maximum length is MaxDomainNameWireLen.
+	DomainNameFormatWire
+)
+
+// DomainNameOptions are the options for ValidateDomainNameWithOptions.
+type DomainNameOptions struct {
+	// Format is the format in which the length of the name is measured.
+	Format DomainNameFormat
+
+	// MaxLen is the maximum length of the name measured according to Format.
+	// If MaxLen is zero, the default maximum length for Format is used.
+	MaxLen int
+
+	// MaxLabelLen is the maximum length of a single label.  If MaxLabelLen is
+	// zero, MaxDomainLabelLen is used.
+	MaxLabelLen int
+}
+
+// maxLens returns the maximum lengths of the name and its labels taking the
+// defaults into account.  opts may be nil.
+func (opts *DomainNameOptions) maxLens() (maxLen, maxLabelLen int) {
+	maxLen, maxLabelLen = MaxDomainNameLen, MaxDomainLabelLen
+	if opts == nil {
+		return maxLen, maxLabelLen
+	}
+
+	if opts.Format == DomainNameFormatWire {
+		maxLen = MaxDomainNameWireLen
+	}
+
+	if opts.MaxLen > 0 {
+		maxLen = opts.MaxLen
+	}
+
+	if opts.MaxLabelLen > 0 {
+		maxLabelLen = opts.MaxLabelLen
+	}
+
+	return maxLen, maxLabelLen
+}
+
+// nameLen returns the length of the non-fully-qualified domain name name
+// measured according to the format.  opts may be nil.
+func (opts *DomainNameOptions) nameLen(name string) (l int) {
+	l = len(name)
+	if opts != nil && opts.Format == DomainNameFormatWire {
+		// Add the length octet of the first label and the root label.  Other
+		// length octets take the place of the dots.
+		l += 2
+	}
+
+	return l
+}
+
 // ValidateDomainNameLabel returns an error if label is not a valid label of
 // a domain name.  An empty label is considered invalid.
 //
 // Any error returned will have the underlying type of *AddrError.
 func ValidateDomainNameLabel(label string) (err error) {
+	return validateDomainNameLabel(label, MaxDomainLabelLen)
+}
+
+// validateDomainNameLabel returns an error if label is not a valid label of
+// a domain name or is longer than maxLen.
+//
+// Any error returned will have the underlying type of *AddrError.
+func validateDomainNameLabel(label string, maxLen int) (err error) {
 	defer makeAddrError(&err, label, AddrKindLabel)
 
 	if label == "" {
@@ -164,10 +245,10 @@ func ValidateDomainNameLabel(label string) (err error) {
 	}
 
 	l := len(label)
-	if l > MaxDomainLabelLen {
+	if l > maxLen {
 		return &LengthError{
 			Kind:   AddrKindLabel,
-			Max:    MaxDomainLabelLen,
+			Max:    maxLen,
 			Length: l,
 		}
 	}
@@ -207,6 +288,17 @@ func ValidateDomainNameLabel(label string) (err error) {
 //
 // Any error returned will have the underlying type of *AddrError.
 func ValidateDomainName(name string) (err error) {
+	return ValidateDomainNameWithOptions(name, nil)
+}
+
+// ValidateDomainNameWithOptions is like ValidateDomainName but allows callers
+// to choose the format in which the length of the name is measured as well as
+// the maximum lengths of the name and its labels.  name must not be fully
+// qualified.  If opts is nil, the behavior is the same as the one of
+// ValidateDomainName.
+//
+// Any error returned will have the underlying type of *AddrError.
+func ValidateDomainNameWithOptions(name string, opts *DomainNameOptions) (err error) {
 	defer makeAddrError(&err, name, AddrKindName)
 
 	name, err = idna.ToASCII(name)
@@ -214,19 +306,20 @@ func ValidateDomainName(name string) (err error) {
 		return err
 	}
 
+	maxLen, maxLabelLen := opts.maxLens()
 	if name == "" {
 		return ErrAddrIsEmpty
-	} else if l := len(name); l > MaxDomainNameLen {
+	} else if l := opts.nameLen(name); l > maxLen {
 		return &LengthError{
 			Kind:   AddrKindName,
-			Max:    MaxDomainNameLen,
+			Max:    maxLen,
 			Length: l,
 		}
 	}
 
 	labels := strings.Split(name, ".")
 	for _, l := range labels {
-		err = ValidateDomainNameLabel(l)
+		err = validateDomainNameLabel(l, maxLabelLen)
 		if err != nil {
 			return err
 		}
@@ -67,3 +67,24 @@ func ExampleSubdomains() {
 	//
 	// []string(nil)
 }
+
+func ExampleValidateDomainNameWithOptions() {
+	name := "example.com"
+
+	err := netutil.ValidateDomainNameWithOptions(name, &netutil.DomainNameOptions{
+		Format: netutil.DomainNameFormatPresentation,
+		MaxLen: 11,
+	})
+	fmt.Println(err)
+
+	err = netutil.ValidateDomainNameWithOptions(name, &netutil.DomainNameOptions{
+		Format: netutil.DomainNameFormatWire,
+		MaxLen: 11,
+	})
+	fmt.Println(err)
+
+	// Output:
+	//
+	// <nil>
+	// bad domain name "example.com": domain name is too long: got 13, max 11
+}
@@ -282,6 +282,95 @@ func TestValidateDomainName(t *testing.T) {
 	}
 }
 
+func TestValidateDomainNameWithOptions(t *testing.T) {
+	t.Parallel()
+
+	// name253 is a 253-character domain name, which is 255 octets long in the
+	// wire format.
+	name253 := strings.Repeat(strings.Repeat("a", 62)+".", 4) + "a"
+
+	testCases := []struct {
+		opts       *netutil.DomainNameOptions
+		name       string
+		in         string
+		wantErrMsg string
+	}{{
+		opts:       nil,
+		name:       "nil_opts",
+		in:         name253,
+		wantErrMsg: "",
+	}, {
+		opts:       &netutil.DomainNameOptions{},
+		name:       "presentation",
+		in:         name253,
+		wantErrMsg: "",
+	}, {
+		opts: &netutil.DomainNameOptions{},
+		name: "presentation_too_long",
+		in:   name253 + "a",
+		wantErrMsg: `bad domain name "` + name253 + `a": ` +
+			`domain name is too long: got 254, max 253`,
+	}, {
+		opts: &netutil.DomainNameOptions{
+			Format: netutil.DomainNameFormatWire,
+		},
+		name:       "wire",
+		in:         name253,
+		wantErrMsg: "",
+	}, {
+		opts: &netutil.DomainNameOptions{
+			Format: netutil.DomainNameFormatWire,
+		},
+		name: "wire_too_long",
+		in:   name253 + "a",
+		wantErrMsg: `bad domain name "` + name253 + `a": ` +
+			`domain name is too long: got 256, max 255`,
+	}, {
+		opts: &netutil.DomainNameOptions{
+			Format: netutil.DomainNameFormatWire,
+			MaxLen: 13,
+		},
+		name:       "wire_custom",
+		in:         "example.com",
+		wantErrMsg: "",
+	}, {
+		opts: &netutil.DomainNameOptions{
+			Format: netutil.DomainNameFormatWire,
+			MaxLen: 12,
+		},
+		name: "wire_custom_too_long",
+		in:   "example.com",
+		wantErrMsg: `bad domain name "example.com": ` +
+			`domain name is too long: got 13, max 12`,
+	}, {
+		opts: &netutil.DomainNameOptions{
+			MaxLabelLen: 7,
+		},
+		name:       "label",
+		in:         "example.com",
+		wantErrMsg: "",
+	}, {
+		opts: &netutil.DomainNameOptions{
+			MaxLabelLen: 6,
+		},
+		name: "label_too_long",
+		in:   "example.com",
+		wantErrMsg: `bad domain name "example.com": ` +
+			`bad domain name label "example": ` +
+			`domain name label is too long: got 7, max 6`,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := netutil.ValidateDomainNameWithOptions(tc.in, tc.opts)
+			testutil.AssertErrorMsg(t, tc.wantErrMsg, err)
+		})
+	}
+}
+
 func TestValidateSRVDomainName(t *testing.T) {
 	t.Parallel()
 
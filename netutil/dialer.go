@@ -0,0 +1,165 @@
+package netutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// Interface-bound Dialing
+
+const (
+	// ErrInterfaceBindingUnsupported is returned from functions binding
+	// sockets to network interfaces on operating systems that don't support
+	// it.
+	ErrInterfaceBindingUnsupported errors.Error = "binding to network interfaces is not supported on this os"
+
+	// ErrInterfaceNameIsEmpty is returned from functions binding sockets to
+	// network interfaces when the name of the interface is empty.
+	ErrInterfaceNameIsEmpty errors.Error = "interface name is empty"
+
+	// ErrNetworkNotBindable is returned from functions binding sockets to
+	// source addresses when the network is not an IP one.
+	ErrNetworkNotBindable errors.Error = "binding is only supported for tcp and udp networks"
+)
+
+// controlFunc is the type of the net.Dialer.Control and net.ListenConfig.Control
+// functions.
+type controlFunc = func(network, address string, c syscall.RawConn) (err error)
+
+// BindError is the underlying type of errors returned when a socket cannot be
+// bound to a network interface or a source address.
+type BindError struct {
+	// Err is the underlying error.
+	Err error
+	// Interface is the name of the network interface, if any.
+	Interface string
+	// Addr is the source address, if any.
+	Addr netip.Addr
+}
+
+// Error implements the error interface for *BindError.
+func (err *BindError) Error() (msg string) {
+	if err.Interface == "" && err.Addr.IsValid() {
+		return fmt.Sprintf("binding to address %s: %s", err.Addr, err.Err)
+	}
+
+	return fmt.Sprintf("binding to interface %q: %s", err.Interface, err.Err)
+}
+
+// Unwrap implements the errors.Wrapper interface for *BindError.  It returns
+// err.Err.
+func (err *BindError) Unwrap() (unwrapped error) {
+	return err.Err
+}
+
+// NewInterfaceDialer returns a *net.Dialer that binds all outgoing connections
+// to the network interface with the name ifaceName.  It uses SO_BINDTODEVICE on
+// Linux and IP_BOUND_IF or IPV6_BOUND_IF on macOS.  On other operating systems
+// the error returned wraps ErrInterfaceBindingUnsupported.
+//
+// Any error returned will have the underlying type of *BindError.
+func NewInterfaceDialer(ifaceName string) (d *net.Dialer, err error) {
+	if ifaceName == "" {
+		return nil, &BindError{
+			Err: ErrInterfaceNameIsEmpty,
+		}
+	}
+
+	d = &net.Dialer{}
+	d.Control, err = interfaceControl(ifaceName)
+	if err != nil {
+		return nil, &BindError{
+			Err:       err,
+			Interface: ifaceName,
+		}
+	}
+
+	return d, nil
+}
+
+// DialContextFunc is the type of the net.Dialer.DialContext method.
+type DialContextFunc = func(ctx context.Context, network, address string) (conn net.Conn, err error)
+
+// BoundDialerConfig is the configuration structure for NewBoundDialContext.
+type BoundDialerConfig struct {
+	// Interface is the name of the network interface to bind the connections
+	// to.  If it is empty, the connections are not bound to an interface.
+	Interface string
+
+	// SourceAddr is the local address to bind the connections to.  If it is
+	// not valid, the local address is chosen by the OS.
+	SourceAddr netip.Addr
+
+	// Timeout is the timeout for establishing the connections, as in
+	// net.Dialer.Timeout.
+	Timeout time.Duration
+}
+
+// NewBoundDialContext returns a function that dials TCP and UDP connections
+// bound to the network interface and the source address from c, which is
+// useful on multi-homed hosts that must use a particular uplink.  c must not be
+// nil and is not used after NewBoundDialContext returns.  Binding to
+// interfaces is done the same way as in NewInterfaceDialer, so on operating
+// systems other than Linux and macOS the error returned wraps
+// ErrInterfaceBindingUnsupported if c.Interface is not empty.  Binding to
+// source addresses is supported on all operating systems.
+//
+// Any error returned by NewBoundDialContext will have the underlying type of
+// *BindError.  The errors of binding returned by dial contain a *BindError,
+// which can be retrieved using errors.As, but the errors of binding to the
+// interface are wrapped into a *net.OpError by package net.
+func NewBoundDialContext(c *BoundDialerConfig) (dial DialContextFunc, err error) {
+	iface, src, timeout := c.Interface, c.SourceAddr, c.Timeout
+
+	var ctrl controlFunc
+	if iface != "" {
+		ctrl, err = interfaceControl(iface)
+		if err != nil {
+			return nil, &BindError{
+				Err:       err,
+				Interface: iface,
+				Addr:      src,
+			}
+		}
+	}
+
+	return func(ctx context.Context, network, address string) (conn net.Conn, err error) {
+		d := &net.Dialer{
+			Timeout: timeout,
+			Control: ctrl,
+		}
+
+		if src.IsValid() {
+			d.LocalAddr, err = localAddr(network, src)
+			if err != nil {
+				return nil, &BindError{
+					Err:       err,
+					Interface: iface,
+					Addr:      src,
+				}
+			}
+		}
+
+		return d.DialContext(ctx, network, address)
+	}, nil
+}
+
+// localAddr returns the local address with an arbitrary port of the type that
+// net.Dialer requires for network.
+func localAddr(network string, src netip.Addr) (addr net.Addr, err error) {
+	ap := netip.AddrPortFrom(src, 0)
+	switch network {
+	case "tcp", "tcp4", "tcp6":
+		return net.TCPAddrFromAddrPort(ap), nil
+	case "udp", "udp4", "udp6":
+		return net.UDPAddrFromAddrPort(ap), nil
+	default:
+		return nil, fmt.Errorf("network %q: %w", network, ErrNetworkNotBindable)
+	}
+}
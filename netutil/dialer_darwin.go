@@ -0,0 +1,48 @@
+//go:build darwin
+// +build darwin
+
+package netutil
+
+import (
+	"net"
+	"strings"
+	"syscall"
+)
+
+// interfaceControl returns a function suitable for net.Dialer.Control that
+// binds the socket to the interface with name ifaceName using IP_BOUND_IF or
+// IPV6_BOUND_IF, depending on the network.
+func interfaceControl(ifaceName string) (ctrl controlFunc, err error) {
+	var iface *net.Interface
+	iface, err = net.InterfaceByName(ifaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := iface.Index
+	ctrl = func(network, _ string, c syscall.RawConn) (err error) {
+		level, opt := syscall.IPPROTO_IP, syscall.IP_BOUND_IF
+		if strings.HasSuffix(network, "6") {
+			level, opt = syscall.IPPROTO_IPV6, syscall.IPV6_BOUND_IF
+		}
+
+		var opErr error
+		err = c.Control(func(fd uintptr) {
+			opErr = syscall.SetsockoptInt(int(fd), level, opt, idx)
+		})
+		if err != nil {
+			return err
+		}
+
+		if opErr != nil {
+			return &BindError{
+				Err:       opErr,
+				Interface: ifaceName,
+			}
+		}
+
+		return nil
+	}
+
+	return ctrl, nil
+}
@@ -0,0 +1,32 @@
+//go:build linux
+// +build linux
+
+package netutil
+
+import "syscall"
+
+// interfaceControl returns a function suitable for net.Dialer.Control that
+// binds the socket to the interface with name ifaceName using
+// SO_BINDTODEVICE.
+func interfaceControl(ifaceName string) (ctrl controlFunc, err error) {
+	ctrl = func(_, _ string, c syscall.RawConn) (err error) {
+		var opErr error
+		err = c.Control(func(fd uintptr) {
+			opErr = syscall.BindToDevice(int(fd), ifaceName)
+		})
+		if err != nil {
+			return err
+		}
+
+		if opErr != nil {
+			return &BindError{
+				Err:       opErr,
+				Interface: ifaceName,
+			}
+		}
+
+		return nil
+	}
+
+	return ctrl, nil
+}
@@ -0,0 +1,66 @@
+//go:build linux
+// +build linux
+
+package netutil_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/netutil"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInterfaceDialer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := netutil.NewInterfaceDialer("")
+		assert.Nil(t, d)
+		testutil.AssertErrorMsg(t, `binding to interface "": interface name is empty`, err)
+		assert.ErrorIs(t, err, netutil.ErrInterfaceNameIsEmpty)
+	})
+
+	t.Run("loopback", func(t *testing.T) {
+		t.Parallel()
+
+		l, err := net.ListenPacket("udp4", "127.0.0.1:0")
+		require.NoError(t, err)
+		testutil.CleanupAndRequireSuccess(t, l.Close)
+
+		d, err := netutil.NewInterfaceDialer("lo")
+		require.NoError(t, err)
+
+		conn, err := d.Dial("udp4", l.LocalAddr().String())
+		if err != nil {
+			// Binding to a device may require additional privileges.
+			bindErr := &netutil.BindError{}
+			require.ErrorAs(t, err, &bindErr)
+
+			t.Skipf("binding to interface: %s", err)
+		}
+		testutil.CleanupAndRequireSuccess(t, conn.Close)
+
+		_, err = conn.Write([]byte("hello"))
+		require.NoError(t, err)
+	})
+
+	t.Run("not_exist", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := netutil.NewInterfaceDialer("nonexistent0")
+		require.NoError(t, err)
+
+		_, err = d.Dial("udp4", "127.0.0.1:53")
+		require.Error(t, err)
+
+		bindErr := &netutil.BindError{}
+		require.ErrorAs(t, err, &bindErr)
+
+		assert.Equal(t, "nonexistent0", bindErr.Interface)
+	})
+}
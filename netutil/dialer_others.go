@@ -0,0 +1,10 @@
+//go:build !(darwin || linux)
+// +build !darwin,!linux
+
+package netutil
+
+// interfaceControl always returns ErrInterfaceBindingUnsupported, since binding
+// sockets to network interfaces isn't supported on this OS.
+func interfaceControl(_ string) (ctrl controlFunc, err error) {
+	return nil, ErrInterfaceBindingUnsupported
+}
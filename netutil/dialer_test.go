@@ -0,0 +1,74 @@
+package netutil_test
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/netutil"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBoundDialContext(t *testing.T) {
+	t.Parallel()
+
+	src := netip.MustParseAddr("127.0.0.1")
+	conf := &netutil.BoundDialerConfig{
+		SourceAddr: src,
+	}
+	dial, err := netutil.NewBoundDialContext(conf)
+	require.NoError(t, err)
+
+	// Changing the configuration mustn't affect the function.
+	conf.SourceAddr = netip.MustParseAddr("192.0.2.1")
+
+	t.Run("tcp", func(t *testing.T) {
+		t.Parallel()
+
+		l, lErr := net.Listen("tcp4", "127.0.0.1:0")
+		require.NoError(t, lErr)
+		testutil.CleanupAndRequireSuccess(t, l.Close)
+
+		conn, dialErr := dial(context.Background(), "tcp4", l.Addr().String())
+		require.NoError(t, dialErr)
+		testutil.CleanupAndRequireSuccess(t, conn.Close)
+
+		laddr, ok := conn.LocalAddr().(*net.TCPAddr)
+		require.True(t, ok)
+
+		assert.Equal(t, src, laddr.AddrPort().Addr())
+	})
+
+	t.Run("udp", func(t *testing.T) {
+		t.Parallel()
+
+		l, lErr := net.ListenPacket("udp4", "127.0.0.1:0")
+		require.NoError(t, lErr)
+		testutil.CleanupAndRequireSuccess(t, l.Close)
+
+		conn, dialErr := dial(context.Background(), "udp4", l.LocalAddr().String())
+		require.NoError(t, dialErr)
+		testutil.CleanupAndRequireSuccess(t, conn.Close)
+
+		laddr, ok := conn.LocalAddr().(*net.UDPAddr)
+		require.True(t, ok)
+
+		assert.Equal(t, src, laddr.AddrPort().Addr())
+	})
+
+	t.Run("bad_network", func(t *testing.T) {
+		t.Parallel()
+
+		_, dialErr := dial(context.Background(), "unix", "/tmp/test.sock")
+		testutil.AssertErrorMsg(
+			t,
+			`binding to address 127.0.0.1: network "unix": `+
+				`binding is only supported for tcp and udp networks`,
+			dialErr,
+		)
+		assert.ErrorIs(t, dialErr, netutil.ErrNetworkNotBindable)
+	})
+}
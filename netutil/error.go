@@ -47,6 +47,105 @@ const (
 	AddrKindSRVName  AddrKind = "service domain name"
 )
 
+// ErrorCode is the machine-readable class of an address validation failure.
+// It allows callers to branch on the reason of the failure without matching
+// the error messages.
+type ErrorCode uint8
+
+// Error codes for AddrError.
+const (
+	// ErrorCodeUnknown is the zero value of ErrorCode.  It is used when the
+	// class of the failure is unknown, for example when an *AddrError is
+	// constructed manually.
+	ErrorCodeUnknown ErrorCode = iota
+
+	// ErrorCodeEmpty means that the address is empty.
+	ErrorCodeEmpty
+
+	// ErrorCodeEmptyLabel means that a domain name label is empty.
+	ErrorCodeEmptyLabel
+
+	// ErrorCodeBadRune means that the address contains an invalid rune.  The
+	// underlying error has the type of *RuneError.
+	ErrorCodeBadRune
+
+	// ErrorCodeTooLong means that the address or its part exceeds the maximum
+	// length.  The underlying error has the type of *LengthError.
+	ErrorCodeTooLong
+
+	// ErrorCodeBadLength means that the address or its part has a length that
+	// is not among the allowed ones.  The underlying error has the type of
+	// *LengthError.
+	ErrorCodeBadLength
+
+	// ErrorCodeNotAReversedIP means that a domain name is not a full reversed
+	// IP address.
+	ErrorCodeNotAReversedIP
+
+	// ErrorCodeNotAReversedSubnet means that a domain name is not a valid
+	// reversed IP network.
+	ErrorCodeNotAReversedSubnet
+
+	// ErrorCodeLeadingZero means that an octet of a reversed IPv4 address
+	// contains a forbidden leading zero.
+	ErrorCodeLeadingZero
+
+	// ErrorCodeBadSyntax means that the address cannot be parsed, for example
+	// because of a missing port or an invalid IP address.
+	ErrorCodeBadSyntax
+)
+
+// errorCodeStrings are the string representations of the error codes indexed
+// by the codes.
+var errorCodeStrings = [...]string{
+	ErrorCodeUnknown:            "unknown",
+	ErrorCodeEmpty:              "empty",
+	ErrorCodeEmptyLabel:         "empty_label",
+	ErrorCodeBadRune:            "bad_rune",
+	ErrorCodeTooLong:            "too_long",
+	ErrorCodeBadLength:          "bad_length",
+	ErrorCodeNotAReversedIP:     "not_a_reversed_ip",
+	ErrorCodeNotAReversedSubnet: "not_a_reversed_subnet",
+	ErrorCodeLeadingZero:        "leading_zero",
+	ErrorCodeBadSyntax:          "bad_syntax",
+}
+
+// String implements the fmt.Stringer interface for ErrorCode.
+func (c ErrorCode) String() (s string) {
+	if int(c) < len(errorCodeStrings) {
+		return errorCodeStrings[c]
+	}
+
+	return fmt.Sprintf("!bad_error_code_%d", uint8(c))
+}
+
+// errorCodeOf returns the error code for err, which must not be nil.  Errors
+// of unknown types are considered syntax errors, since they are usually
+// returned by parsing functions from other packages.
+func errorCodeOf(err error) (c ErrorCode) {
+	switch err := err.(type) {
+	case *AddrError:
+		return err.Code
+	case *LengthError:
+		return err.Code()
+	case *RuneError:
+		return err.Code()
+	}
+
+	switch err {
+	case ErrAddrIsEmpty:
+		return ErrorCodeEmpty
+	case ErrLabelIsEmpty:
+		return ErrorCodeEmptyLabel
+	case ErrNotAReversedIP:
+		return ErrorCodeNotAReversedIP
+	case ErrNotAReversedSubnet:
+		return ErrorCodeNotAReversedSubnet
+	default:
+		return ErrorCodeBadSyntax
+	}
+}
+
 // AddrError is the underlying type of errors returned from validation
 // functions when a domain name is invalid.
 type AddrError struct {
@@ -56,6 +155,9 @@ type AddrError struct {
 	Kind AddrKind
 	// Addr is the text of the invalid address.
 	Addr string
+	// Code is the class of the failure.  The errors returned from the
+	// functions of this package always have a non-zero Code.
+	Code ErrorCode
 }
 
 // Error implements the error interface for *AddrError.
@@ -88,6 +190,7 @@ func makeAddrError(errPtr *error, addr string, k AddrKind) {
 		Err:  err,
 		Kind: k,
 		Addr: addr,
+		Code: errorCodeOf(err),
 	}
 }
 
@@ -120,6 +223,16 @@ func (err *LengthError) Error() (msg string) {
 	return fmt.Sprintf(format, err.Kind, err.Length, err.Allowed)
 }
 
+// Code returns the error code for err, either ErrorCodeTooLong or
+// ErrorCodeBadLength.
+func (err *LengthError) Code() (c ErrorCode) {
+	if err.Max > 0 {
+		return ErrorCodeTooLong
+	}
+
+	return ErrorCodeBadLength
+}
+
 // RuneError is the underlying type of errors returned from validation functions
 // when a rune in the address is invalid.
 type RuneError struct {
@@ -133,3 +246,8 @@ type RuneError struct {
 func (err *RuneError) Error() (msg string) {
 	return fmt.Sprintf("bad %s rune %q", err.Kind, err.Rune)
 }
+
+// Code returns the error code for err, which is always ErrorCodeBadRune.
+func (err *RuneError) Code() (c ErrorCode) {
+	return ErrorCodeBadRune
+}
@@ -0,0 +1,125 @@
+package netutil_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/netutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddrError_Code(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		validate func() (err error)
+		name     string
+		want     netutil.ErrorCode
+	}{{
+		validate: func() (err error) { return netutil.ValidateDomainName("") },
+		name:     "empty",
+		want:     netutil.ErrorCodeEmpty,
+	}, {
+		validate: func() (err error) { return netutil.ValidateDomainName("example..com") },
+		name:     "empty_label",
+		want:     netutil.ErrorCodeEmptyLabel,
+	}, {
+		validate: func() (err error) { return netutil.ValidateDomainName("exa_mple.com") },
+		name:     "bad_rune",
+		want:     netutil.ErrorCodeBadRune,
+	}, {
+		validate: func() (err error) {
+			return netutil.ValidateDomainNameLabel(strings.Repeat("a", 64))
+		},
+		name: "too_long",
+		want: netutil.ErrorCodeTooLong,
+	}, {
+		validate: func() (err error) {
+			return netutil.ValidateMAC(net.HardwareAddr{0x00, 0x01, 0x02, 0x03})
+		},
+		name: "bad_length",
+		want: netutil.ErrorCodeBadLength,
+	}, {
+		validate: func() (err error) {
+			_, err = netutil.IPFromReversedAddr("example.com")
+
+			return err
+		},
+		name: "not_a_reversed_ip",
+		want: netutil.ErrorCodeNotAReversedIP,
+	}, {
+		validate: func() (err error) {
+			_, err = netutil.SubnetFromReversedAddr("example.com")
+
+			return err
+		},
+		name: "not_a_reversed_subnet",
+		want: netutil.ErrorCodeNotAReversedSubnet,
+	}, {
+		validate: func() (err error) {
+			_, err = netutil.SubnetFromReversedAddr("01.10" + ipv4Suffix)
+
+			return err
+		},
+		name: "leading_zero",
+		want: netutil.ErrorCodeLeadingZero,
+	}, {
+		validate: func() (err error) {
+			_, err = netutil.ParseIP("1.2.3")
+
+			return err
+		},
+		name: "bad_syntax",
+		want: netutil.ErrorCodeBadSyntax,
+	}, {
+		validate: func() (err error) {
+			_, err = netutil.ParseHostPort("example.com")
+
+			return err
+		},
+		name: "bad_syntax_hostport",
+		want: netutil.ErrorCodeBadSyntax,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.validate()
+			require.Error(t, err)
+
+			addrErr := &netutil.AddrError{}
+			require.ErrorAs(t, err, &addrErr)
+
+			assert.Equal(t, tc.want, addrErr.Code)
+		})
+	}
+}
+
+func TestErrorCode_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "unknown", netutil.ErrorCode(0).String())
+	assert.Equal(t, "!bad_error_code_255", netutil.ErrorCode(255).String())
+}
+
+func TestLengthError_Code(t *testing.T) {
+	t.Parallel()
+
+	var lenErr *netutil.LengthError
+	err := netutil.ValidateDomainName(strings.Repeat("a.", 127) + "a")
+	require.True(t, errors.As(err, &lenErr))
+
+	assert.Equal(t, netutil.ErrorCodeTooLong, lenErr.Code())
+
+	lenErr = &netutil.LengthError{
+		Kind:    netutil.AddrKindMAC,
+		Allowed: []int{6},
+		Length:  4,
+	}
+	assert.Equal(t, netutil.ErrorCodeBadLength, lenErr.Code())
+}
@@ -86,6 +86,7 @@ func ParseIP(s string) (ip net.IP, err error) {
 		return nil, &AddrError{
 			Kind: AddrKindIP,
 			Addr: s,
+			Code: ErrorCodeBadSyntax,
 		}
 	}
 
@@ -108,6 +109,7 @@ func ParseIPv4(s string) (ip net.IP, err error) {
 		return nil, &AddrError{
 			Kind: AddrKindIPv4,
 			Addr: s,
+			Code: ErrorCodeBadSyntax,
 		}
 	}
 
@@ -147,6 +149,7 @@ func ParseSubnet(s string) (n *net.IPNet, err error) {
 				Err:  err,
 				Kind: AddrKindCIDR,
 				Addr: s,
+				Code: errorCodeOf(err),
 			}
 		}
 
@@ -160,6 +163,7 @@ func ParseSubnet(s string) (n *net.IPNet, err error) {
 		return nil, &AddrError{
 			Kind: AddrKindCIDR,
 			Addr: s,
+			Code: ErrorCodeBadSyntax,
 		}
 	}
 
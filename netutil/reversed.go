@@ -186,6 +186,7 @@ func IPToReversedAddr(ip net.IP) (arpa string, err error) {
 		return "", &AddrError{
 			Kind: AddrKindIP,
 			Addr: ip.String(),
+			Code: ErrorCodeBadSyntax,
 		}
 	}
 
@@ -224,6 +225,7 @@ func ipv4NetFromReversed(arpa string) (subnet *net.IPNet, err error) {
 				Err:  errors.Error("leading zero is forbidden at this position"),
 				Kind: AddrKindLabel,
 				Addr: addr[octetIdx:],
+				Code: ErrorCodeLeadingZero,
 			}
 		}
 
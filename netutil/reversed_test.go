@@ -172,14 +172,7 @@ func TestIPFromReversedAddr(t *testing.T) {
 
 			ip, err := netutil.IPFromReversedAddr(tc.in)
 			assert.Equal(t, tc.want.To16(), ip.To16())
-			testutil.AssertErrorMsg(t, tc.wantErrMsg, err)
-
-			if tc.wantErrAs != nil {
-				require.Error(t, err)
-
-				assert.ErrorAs(t, err, new(*netutil.AddrError))
-				assert.ErrorAs(t, err, tc.wantErrAs)
-			}
+			testutil.AssertErrorChain(t, tc.wantErrMsg, err, new(*netutil.AddrError), tc.wantErrAs)
 		})
 	}
 }
@@ -244,14 +237,7 @@ func TestIPToReversedAddr(t *testing.T) {
 
 			arpa, err := netutil.IPToReversedAddr(tc.in)
 			assert.Equal(t, tc.want, arpa)
-			testutil.AssertErrorMsg(t, tc.wantErrMsg, err)
-
-			if tc.wantErrAs != nil {
-				require.Error(t, err)
-
-				assert.ErrorAs(t, err, new(*netutil.AddrError))
-				assert.ErrorAs(t, err, tc.wantErrAs)
-			}
+			testutil.AssertErrorChain(t, tc.wantErrMsg, err, new(*netutil.AddrError), tc.wantErrAs)
 		})
 	}
 }
@@ -474,14 +460,9 @@ func TestSubnetFromReversedAddr(t *testing.T) {
 			t.Parallel()
 
 			subnet, err := netutil.SubnetFromReversedAddr(tc.in)
-			testutil.AssertErrorMsg(t, tc.wantErrMsg, err)
-
-			if tc.wantErrAs != nil {
-				require.Error(t, err)
+			testutil.AssertErrorChain(t, tc.wantErrMsg, err, new(*netutil.AddrError), tc.wantErrAs)
 
-				assert.ErrorAs(t, err, new(*netutil.AddrError))
-				assert.ErrorAs(t, err, tc.wantErrAs)
-			} else {
+			if tc.wantErrMsg == "" {
 				require.NotNil(t, subnet)
 
 				assert.Equal(t, tc.want.IP.To16(), subnet.IP.To16())
@@ -0,0 +1,36 @@
+package sysresolvers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+)
+
+// ParseResolvConf parses the addresses of the name servers from the data in
+// the resolv.conf(5) format.  The port of the addresses is always 53.
+func ParseResolvConf(r io.Reader) (addrs []netip.AddrPort, err error) {
+	s := bufio.NewScanner(r)
+	for lineNum := 1; s.Scan(); lineNum++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+
+		var addr netip.Addr
+		addr, err = netip.ParseAddr(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		addrs = append(addrs, netip.AddrPortFrom(addr, defaultPort))
+	}
+
+	err = s.Err()
+	if err != nil {
+		return nil, fmt.Errorf("reading resolv.conf: %w", err)
+	}
+
+	return addrs, nil
+}
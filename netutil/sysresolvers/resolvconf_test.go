@@ -0,0 +1,58 @@
+package sysresolvers_test
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/netutil/sysresolvers"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResolvConf(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		in      string
+		wantErr string
+		want    []netip.AddrPort
+	}{{
+		name: "success",
+		in: "# comment\n" +
+			"search example.com\n" +
+			"nameserver 1.2.3.4\n" +
+			"nameserver\t2001:db8::1\n" +
+			"options ndots:1\n" +
+			"nameserver fe80::1%eth0\n",
+		wantErr: "",
+		want: []netip.AddrPort{
+			netip.MustParseAddrPort("1.2.3.4:53"),
+			netip.MustParseAddrPort("[2001:db8::1]:53"),
+			netip.MustParseAddrPort("[fe80::1%eth0]:53"),
+		},
+	}, {
+		name:    "empty",
+		in:      "",
+		wantErr: "",
+		want:    nil,
+	}, {
+		name:    "bad",
+		in:      "nameserver 1.2.3.4\nnameserver bad\n",
+		wantErr: `line 2: ParseAddr("bad"): unable to parse IP`,
+		want:    nil,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			addrs, err := sysresolvers.ParseResolvConf(strings.NewReader(tc.in))
+			testutil.AssertErrorMsg(t, tc.wantErr, err)
+
+			assert.Equal(t, tc.want, addrs)
+		})
+	}
+}
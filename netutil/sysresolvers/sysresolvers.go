@@ -0,0 +1,140 @@
+// Package sysresolvers discovers the DNS resolvers configured in the operating
+// system.
+package sysresolvers
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"sync"
+)
+
+// defaultPort is the default port of DNS servers.
+const defaultPort = 53
+
+// DiscoverFunc returns the addresses of the DNS resolvers configured in the
+// system.  System is the default implementation.
+type DiscoverFunc func(ctx context.Context) (addrs []netip.AddrPort, err error)
+
+// System returns the addresses of the DNS resolvers configured in the operating
+// system.  On Windows, they are taken from the network adapters' settings; on
+// macOS, from the output of scutil; and on other Unix systems, from
+// /etc/resolv.conf.
+func System(ctx context.Context) (addrs []netip.AddrPort, err error) {
+	addrs, err = systemResolvers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("discovering system resolvers: %w", err)
+	}
+
+	return addrs, nil
+}
+
+// Config is the configuration structure for a *Discoverer.
+type Config struct {
+	// Discover returns the addresses of the resolvers.  If it is nil, System
+	// is used.
+	Discover DiscoverFunc
+
+	// OnChange, if not nil, is called with the new addresses each time they
+	// change after a refresh.  It is called synchronously, so it must not
+	// call the methods of the Discoverer that changes.
+	OnChange func(addrs []netip.AddrPort)
+
+	// ListenAddrs are the addresses the program itself listens on.  The
+	// resolvers with these addresses are excluded to avoid loops.  An
+	// unspecified address excludes all resolvers with the same port on the
+	// loopback addresses as well.
+	ListenAddrs []netip.AddrPort
+}
+
+// Discoverer keeps the current list of the system resolvers.  To refresh it
+// periodically, use it as a service.Refresher with service.RefreshWorker; to
+// refresh it on changes, call Refresh from a watcher of the configuration file.
+type Discoverer struct {
+	discover DiscoverFunc
+	onChange func(addrs []netip.AddrPort)
+	listen   []netip.AddrPort
+
+	// mu protects addrs.
+	mu    *sync.Mutex
+	addrs []netip.AddrPort
+}
+
+// NewDiscoverer returns a new properly initialized *Discoverer.  c must not be
+// nil.  Refresh must be called to fill the list of the resolvers.
+func NewDiscoverer(c *Config) (d *Discoverer) {
+	discover := c.Discover
+	if discover == nil {
+		discover = System
+	}
+
+	return &Discoverer{
+		discover: discover,
+		onChange: c.OnChange,
+		listen:   slices.Clone(c.ListenAddrs),
+		mu:       &sync.Mutex{},
+	}
+}
+
+// Addrs returns the current addresses of the resolvers.  addrs must not be
+// modified.
+func (d *Discoverer) Addrs() (addrs []netip.AddrPort) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.addrs
+}
+
+// Refresh implements the service.Refresher interface for *Discoverer.  It
+// rediscovers the resolvers and calls the change handler if they've changed.
+// On error, the previous addresses are kept.
+func (d *Discoverer) Refresh(ctx context.Context) (err error) {
+	addrs, err := d.discover(ctx)
+	if err != nil {
+		return err
+	}
+
+	addrs = d.filter(addrs)
+
+	d.mu.Lock()
+	changed := !slices.Equal(d.addrs, addrs)
+	if changed {
+		d.addrs = addrs
+	}
+	d.mu.Unlock()
+
+	if changed && d.onChange != nil {
+		d.onChange(addrs)
+	}
+
+	return nil
+}
+
+// filter returns addrs without the duplicates and the addresses matching the
+// listen addresses.
+func (d *Discoverer) filter(addrs []netip.AddrPort) (filtered []netip.AddrPort) {
+	filtered = make([]netip.AddrPort, 0, len(addrs))
+	for _, a := range addrs {
+		if !d.isListenAddr(a) && !slices.Contains(filtered, a) {
+			filtered = append(filtered, a)
+		}
+	}
+
+	return filtered
+}
+
+// isListenAddr returns true if a is one of the listen addresses.
+func (d *Discoverer) isListenAddr(a netip.AddrPort) (ok bool) {
+	for _, l := range d.listen {
+		if l == a {
+			return true
+		}
+
+		if l.Addr().IsUnspecified() && l.Port() == a.Port() && a.Addr().IsLoopback() {
+			return true
+		}
+	}
+
+	return false
+}
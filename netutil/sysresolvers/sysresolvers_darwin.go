@@ -0,0 +1,49 @@
+//go:build darwin
+// +build darwin
+
+package sysresolvers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/netip"
+	"os/exec"
+	"strings"
+)
+
+// systemResolvers returns the resolvers reported by scutil, since on macOS
+// /etc/resolv.conf doesn't reflect the per-interface and VPN settings.
+func systemResolvers(ctx context.Context) (addrs []netip.AddrPort, err error) {
+	out, err := exec.CommandContext(ctx, "scutil", "--dns").Output()
+	if err != nil {
+		return nil, fmt.Errorf("running scutil: %w", err)
+	}
+
+	return parseScutil(out)
+}
+
+// parseScutil parses the addresses of the name servers from the output of
+// "scutil --dns", which contains lines like:
+//
+//	nameserver[0] : 192.168.1.1
+func parseScutil(out []byte) (addrs []netip.AddrPort, err error) {
+	s := bufio.NewScanner(bytes.NewReader(out))
+	for s.Scan() {
+		key, val, ok := strings.Cut(s.Text(), ":")
+		if !ok || !strings.HasPrefix(strings.TrimSpace(key), "nameserver[") {
+			continue
+		}
+
+		var addr netip.Addr
+		addr, err = netip.ParseAddr(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("parsing scutil output: %w", err)
+		}
+
+		addrs = append(addrs, netip.AddrPortFrom(addr, defaultPort))
+	}
+
+	return addrs, s.Err()
+}
@@ -0,0 +1,63 @@
+package sysresolvers_test
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/netutil/sysresolvers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverer_Refresh(t *testing.T) {
+	t.Parallel()
+
+	const testErr errors.Error = "test error"
+
+	var (
+		addrGood  = netip.MustParseAddrPort("1.2.3.4:53")
+		addrOwn   = netip.MustParseAddrPort("192.168.0.1:53")
+		addrLocal = netip.MustParseAddrPort("127.0.0.1:53")
+	)
+
+	var discovered []netip.AddrPort
+	var discoverErr error
+	var changes [][]netip.AddrPort
+
+	d := sysresolvers.NewDiscoverer(&sysresolvers.Config{
+		Discover: func(_ context.Context) (addrs []netip.AddrPort, err error) {
+			return discovered, discoverErr
+		},
+		OnChange: func(addrs []netip.AddrPort) {
+			changes = append(changes, addrs)
+		},
+		ListenAddrs: []netip.AddrPort{
+			addrOwn,
+			netip.MustParseAddrPort("[::]:53"),
+		},
+	})
+
+	ctx := context.Background()
+
+	discovered = []netip.AddrPort{addrOwn, addrGood, addrLocal, addrGood}
+	require.NoError(t, d.Refresh(ctx))
+	assert.Equal(t, []netip.AddrPort{addrGood}, d.Addrs())
+
+	// Unchanged.
+	discovered = []netip.AddrPort{addrGood}
+	require.NoError(t, d.Refresh(ctx))
+
+	// Failed, so the previous value is kept.
+	discoverErr = testErr
+	assert.ErrorIs(t, d.Refresh(ctx), testErr)
+	assert.Equal(t, []netip.AddrPort{addrGood}, d.Addrs())
+
+	discoverErr = nil
+	discovered = nil
+	require.NoError(t, d.Refresh(ctx))
+	assert.Empty(t, d.Addrs())
+
+	assert.Equal(t, [][]netip.AddrPort{{addrGood}, {}}, changes)
+}
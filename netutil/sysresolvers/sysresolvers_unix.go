@@ -0,0 +1,26 @@
+//go:build !darwin && !windows
+// +build !darwin,!windows
+
+package sysresolvers
+
+import (
+	"context"
+	"net/netip"
+	"os"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// resolvConfPath is the path to the resolver configuration file.
+const resolvConfPath = "/etc/resolv.conf"
+
+// systemResolvers returns the resolvers from the resolver configuration file.
+func systemResolvers(_ context.Context) (addrs []netip.AddrPort, err error) {
+	f, err := os.Open(resolvConfPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errors.WithDeferred(err, f.Close()) }()
+
+	return ParseResolvConf(f)
+}
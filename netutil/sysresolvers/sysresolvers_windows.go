@@ -0,0 +1,54 @@
+//go:build windows
+// +build windows
+
+package sysresolvers
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"slices"
+	"sync"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// errFakeDial is returned from the fake dialing function used to collect the
+// resolvers' addresses.
+const errFakeDial errors.Error = "fake dial"
+
+// systemResolvers returns the resolvers configured for the network adapters.
+// The pure Go resolver retrieves them using the system API, so it's used with
+// a dialing function that records the addresses it's asked to dial instead of
+// connecting.
+func systemResolvers(ctx context.Context) (addrs []netip.AddrPort, err error) {
+	mu := &sync.Mutex{}
+	r := &net.Resolver{
+		PreferGo:     true,
+		StrictErrors: true,
+		Dial: func(_ context.Context, _, address string) (_ net.Conn, err error) {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return nil, err
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if !slices.Contains(addrs, addr) {
+				addrs = append(addrs, addr)
+			}
+
+			return nil, errFakeDial
+		},
+	}
+
+	// The error is expected, since the dialing always fails.  Use a name in
+	// a reserved domain so that no real queries are attempted.
+	_, _ = r.LookupHost(ctx, "sysresolvers.invalid")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return addrs, nil
+}
@@ -0,0 +1,211 @@
+package osutil
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// EnvTagName is the name of the struct field tag used by LoadEnv.
+const EnvTagName = "env"
+
+// EnvLookupFunc is the type of function that looks up the environment
+// variables.  os.LookupEnv is the canonical implementation.
+type EnvLookupFunc func(key string) (val string, ok bool)
+
+// EnvError is a single error of loading an environment variable.
+type EnvError struct {
+	// Err is the underlying error.  It must not be nil.
+	Err error
+
+	// Name is the name of the variable.
+	Name string
+
+	// Value is the value of the variable.
+	Value string
+}
+
+// type check
+var _ errors.Wrapper = (*EnvError)(nil)
+
+// Error implements the error interface for *EnvError.
+func (err *EnvError) Error() (msg string) {
+	return fmt.Sprintf("env var %s: bad value %q: %s", err.Name, err.Value, err.Err)
+}
+
+// Unwrap implements the errors.Wrapper interface for *EnvError.
+func (err *EnvError) Unwrap() (unwrapped error) {
+	return err.Err
+}
+
+// LoadEnv is like LoadEnvFunc with os.LookupEnv.
+func LoadEnv(dst any) (err error) {
+	return LoadEnvFunc(dst, os.LookupEnv)
+}
+
+// LoadEnvFunc sets the fields of the struct pointed to by dst from the
+// environment variables named in their "env" tags, for example:
+//
+//	type config struct {
+//		Addr    netip.Addr        `env:"ADDR"`
+//		Timeout timeutil.Duration `env:"TIMEOUT"`
+//		Verbose bool              `env:"VERBOSE"`
+//	}
+//
+// Fields of types implementing encoding.TextUnmarshaler, such as netip.Addr,
+// netip.Prefix, timeutil.Duration, and netutil.HostPort, as well as strings,
+// booleans, numbers, and pointers to these are supported.  Values of slices
+// are split by commas.  Untagged struct fields are loaded recursively, and
+// fields for unset variables are left unchanged.
+//
+// All bad variables are reported; err is then a result of errors.Join
+// containing an *EnvError for each of them.
+func LoadEnvFunc(dst any, lookup EnvLookupFunc) (err error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("dst: want non-nil pointer to struct, got %T", dst)
+	}
+
+	return errors.Join(loadEnvStruct(v.Elem(), lookup, nil)...)
+}
+
+// textUnmarshalerType is the reflect.Type of encoding.TextUnmarshaler.
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// loadEnvStruct loads the fields of the struct v and appends the errors to
+// errs.
+func loadEnvStruct(v reflect.Value, lookup EnvLookupFunc, errs []error) (res []error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		fv := v.Field(i)
+		name, ok := sf.Tag.Lookup(EnvTagName)
+		if !ok {
+			if fv.Kind() == reflect.Struct && !isTextUnmarshaler(fv) {
+				errs = loadEnvStruct(fv, lookup, errs)
+			}
+
+			continue
+		}
+
+		val, ok := lookup(name)
+		if !ok {
+			continue
+		}
+
+		err := setEnvValue(fv, val)
+		if err != nil {
+			errs = append(errs, &EnvError{
+				Err:   err,
+				Name:  name,
+				Value: val,
+			})
+		}
+	}
+
+	return errs
+}
+
+// isTextUnmarshaler returns true if the address of v implements
+// encoding.TextUnmarshaler.
+func isTextUnmarshaler(v reflect.Value) (ok bool) {
+	return v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType)
+}
+
+// setEnvValue parses val and sets v to it.
+func setEnvValue(v reflect.Value, val string) (err error) {
+	if v.Kind() == reflect.Pointer {
+		ptr := reflect.New(v.Type().Elem())
+		err = setEnvValue(ptr.Elem(), val)
+		if err == nil {
+			v.Set(ptr)
+		}
+
+		return err
+	}
+
+	if isTextUnmarshaler(v) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(val))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(val)
+
+		return nil
+	case reflect.Slice:
+		return setEnvSlice(v, val)
+	default:
+		return setEnvNumber(v, val)
+	}
+}
+
+// setEnvNumber parses val as a boolean or a number and sets v to it.
+func setEnvNumber(v reflect.Value, val string) (err error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(val)
+		if err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(val, 0, v.Type().Bits())
+		if err == nil {
+			v.SetInt(n)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		n, err = strconv.ParseUint(val, 0, v.Type().Bits())
+		if err == nil {
+			v.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(val, v.Type().Bits())
+		if err == nil {
+			v.SetFloat(f)
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	// Unwrap the strconv errors, since they repeat the value.
+	numErr := &strconv.NumError{}
+	if errors.As(err, &numErr) {
+		return numErr.Err
+	}
+
+	return err
+}
+
+// setEnvSlice parses the comma-separated val and sets the slice v to it.  An
+// empty val results in an empty slice.
+func setEnvSlice(v reflect.Value, val string) (err error) {
+	var parts []string
+	if val != "" {
+		parts = strings.Split(val, ",")
+	}
+
+	s := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+	for i, p := range parts {
+		err = setEnvValue(s.Index(i), strings.TrimSpace(p))
+		if err != nil {
+			return fmt.Errorf("at index %d: %w", i, err)
+		}
+	}
+
+	v.Set(s)
+
+	return nil
+}
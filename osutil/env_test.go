@@ -0,0 +1,114 @@
+package osutil_test
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/netutil"
+	"github.com/AdguardTeam/golibs/osutil"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEnvConfig is the configuration structure for tests of LoadEnvFunc.
+type testEnvConfig struct {
+	Upstream *netutil.HostPort `env:"UPSTREAM"`
+	Nested   struct {
+		Name string `env:"NAME"`
+	}
+	Subnets []netip.Prefix    `env:"SUBNETS"`
+	Addr    netip.Addr        `env:"ADDR"`
+	Timeout timeutil.Duration `env:"TIMEOUT"`
+	Port    uint16            `env:"PORT"`
+	Verbose bool              `env:"VERBOSE"`
+	Unset   int               `env:"UNSET"`
+}
+
+// newEnvLookup returns an osutil.EnvLookupFunc that looks the variables up in
+// env.
+func newEnvLookup(env map[string]string) (f osutil.EnvLookupFunc) {
+	return func(key string) (val string, ok bool) {
+		val, ok = env[key]
+
+		return val, ok
+	}
+}
+
+func TestLoadEnvFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		c := &testEnvConfig{
+			Unset: 42,
+		}
+
+		err := osutil.LoadEnvFunc(c, newEnvLookup(map[string]string{
+			"ADDR":     "1.2.3.4",
+			"NAME":     "test",
+			"PORT":     "53",
+			"SUBNETS":  "1.2.3.0/24, 2001:db8::/32",
+			"TIMEOUT":  "1m",
+			"UPSTREAM": "example.com:53",
+			"VERBOSE":  "true",
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, &testEnvConfig{
+			Upstream: &netutil.HostPort{Host: "example.com", Port: 53},
+			Nested: struct {
+				Name string `env:"NAME"`
+			}{
+				Name: "test",
+			},
+			Subnets: []netip.Prefix{
+				netip.MustParsePrefix("1.2.3.0/24"),
+				netip.MustParsePrefix("2001:db8::/32"),
+			},
+			Addr:    netip.MustParseAddr("1.2.3.4"),
+			Timeout: timeutil.Duration{Duration: time.Minute},
+			Port:    53,
+			Verbose: true,
+			Unset:   42,
+		}, c)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		c := &testEnvConfig{}
+		err := osutil.LoadEnvFunc(c, newEnvLookup(map[string]string{
+			"ADDR":    "bad",
+			"PORT":    "65536",
+			"SUBNETS": "1.2.3.0/24,bad",
+			"VERBOSE": "yes",
+		}))
+
+		testutil.AssertErrorMsg(
+			t,
+			`4 errors: `+
+				`"env var SUBNETS: bad value \"1.2.3.0/24,bad\": at index 1: `+
+				`netip.ParsePrefix(\"bad\"): no '/'", `+
+				`"env var ADDR: bad value \"bad\": ParseAddr(\"bad\"): unable to parse IP", `+
+				`"env var PORT: bad value \"65536\": value out of range", `+
+				`"env var VERBOSE: bad value \"yes\": invalid syntax"`,
+			err,
+		)
+
+		envErr := &osutil.EnvError{}
+		require.ErrorAs(t, err, &envErr)
+
+		assert.Equal(t, "SUBNETS", envErr.Name)
+	})
+
+	t.Run("bad_dst", func(t *testing.T) {
+		t.Parallel()
+
+		err := osutil.LoadEnvFunc(testEnvConfig{}, newEnvLookup(nil))
+		testutil.AssertErrorMsg(t, "dst: want non-nil pointer to struct, got osutil_test.testEnvConfig", err)
+	})
+}
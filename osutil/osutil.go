@@ -0,0 +1,3 @@
+// Package osutil contains utilities for functions requiring system calls and
+// other OS-specific APIs, such as signal handling and privilege management.
+package osutil
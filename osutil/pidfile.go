@@ -0,0 +1,123 @@
+package osutil
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// ErrAlreadyRunning is returned by CreatePIDFile when another instance of the
+// program holds the PID file.
+const ErrAlreadyRunning errors.Error = "already running"
+
+// PIDFileError is returned by CreatePIDFile and describes the failure.  Use
+// errors.Is with ErrAlreadyRunning to check if another instance is running and
+// with fs.ErrPermission to check for permission problems.
+type PIDFileError struct {
+	// Err is the underlying error.  It must not be nil.
+	Err error
+
+	// Path is the path to the PID file.
+	Path string
+
+	// PID is the ID of the running process, if Err is ErrAlreadyRunning and
+	// the PID file contains a valid ID.  Otherwise, it is zero.
+	PID int
+}
+
+// type check
+var _ errors.Wrapper = (*PIDFileError)(nil)
+
+// Error implements the error interface for *PIDFileError.
+func (err *PIDFileError) Error() (msg string) {
+	if err.PID != 0 {
+		return fmt.Sprintf("pid file %q: pid %d: %s", err.Path, err.PID, err.Err)
+	}
+
+	return fmt.Sprintf("pid file %q: %s", err.Path, err.Err)
+}
+
+// Unwrap implements the errors.Wrapper interface for *PIDFileError.
+func (err *PIDFileError) Unwrap() (unwrapped error) {
+	return err.Err
+}
+
+// PIDFile is a PID file held by the current process.
+type PIDFile struct {
+	file *os.File
+	path string
+}
+
+// CreatePIDFile creates the PID file at path, writes the ID of the current
+// process into it, and keeps it open to prevent other instances from starting.
+// A stale file left by a process that is no longer running is overwritten.
+// Any error returned has the type *PIDFileError.
+//
+// On Unix, the file is protected with an advisory lock, which the OS releases
+// when the process exits.  On other platforms, the file is created exclusively,
+// and the liveness of the process it names is checked when it already exists.
+func CreatePIDFile(path string) (f *PIDFile, err error) {
+	file, err := createPIDFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writePID(file)
+	if err != nil {
+		err = errors.WithDeferred(err, file.Close())
+
+		return nil, &PIDFileError{
+			Err:  err,
+			Path: path,
+		}
+	}
+
+	return &PIDFile{
+		file: file,
+		path: path,
+	}, nil
+}
+
+// Remove removes the PID file and releases it.
+func (f *PIDFile) Remove() (err error) {
+	err = removePIDFile(f.file, f.path)
+	if err != nil {
+		return fmt.Errorf("removing pid file %q: %w", f.path, err)
+	}
+
+	return nil
+}
+
+// writePID replaces the contents of file with the ID of the current process.
+func writePID(file *os.File) (err error) {
+	err = file.Truncate(0)
+	if err != nil {
+		return fmt.Errorf("truncating: %w", err)
+	}
+
+	_, err = file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	if err != nil {
+		return fmt.Errorf("writing pid: %w", err)
+	}
+
+	return file.Sync()
+}
+
+// readPID reads the process ID from the file at path.  It returns zero if the
+// file doesn't contain a valid ID.
+func readPID(path string) (pid int) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+
+	pid, err = strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil || pid <= 0 {
+		return 0
+	}
+
+	return pid
+}
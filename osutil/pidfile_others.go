@@ -0,0 +1,71 @@
+//go:build !(darwin || freebsd || linux || netbsd || openbsd)
+// +build !darwin,!freebsd,!linux,!netbsd,!openbsd
+
+package osutil
+
+import (
+	"io/fs"
+	"os"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// createPIDFile creates the PID file at path exclusively.  If the file already
+// exists and the process it names isn't running, the file is removed as stale
+// and the creation is retried once.
+func createPIDFile(path string) (file *os.File, err error) {
+	for i := 0; i < 2; i++ {
+		file, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+		if !errors.Is(err, fs.ErrExist) {
+			break
+		}
+
+		pid := readPID(path)
+		if pid != 0 && isRunning(pid) {
+			return nil, &PIDFileError{
+				Err:  ErrAlreadyRunning,
+				Path: path,
+				PID:  pid,
+			}
+		}
+
+		err = os.Remove(path)
+		if err != nil {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, &PIDFileError{
+			Err:  err,
+			Path: path,
+		}
+	}
+
+	return file, nil
+}
+
+// removePIDFile closes file and removes the PID file at path.  The file is
+// closed first, since Windows doesn't allow removing open files.
+func removePIDFile(file *os.File, path string) (err error) {
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// isRunning returns true if the process with the given ID seems to be running.
+// On Windows, os.FindProcess fails for processes that don't exist; on other
+// platforms, the process is always assumed to be running.
+func isRunning(pid int) (ok bool) {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	_ = p.Release()
+
+	return true
+}
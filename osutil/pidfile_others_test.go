@@ -0,0 +1,39 @@
+//go:build !(darwin || freebsd || linux || netbsd || openbsd)
+// +build !darwin,!freebsd,!linux,!netbsd,!openbsd
+
+package osutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/osutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePIDFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "test.pid")
+	wantData := strconv.Itoa(os.Getpid()) + "\n"
+
+	f, err := osutil.CreatePIDFile(path)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, wantData, string(data))
+
+	require.NoError(t, f.Remove())
+	assert.NoFileExists(t, path)
+
+	f, err = osutil.CreatePIDFile(path)
+	require.NoError(t, err)
+
+	require.NoError(t, f.Remove())
+	assert.NoFileExists(t, path)
+}
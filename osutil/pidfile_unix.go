@@ -0,0 +1,110 @@
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package osutil
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// maxPIDFileAttempts is the maximum number of attempts to lock a PID file that
+// is being replaced concurrently.
+const maxPIDFileAttempts = 10
+
+// createPIDFile opens the PID file at path and locks it.  Since the lock is
+// released by the OS when the holding process dies, an unlocked file is
+// considered stale.
+func createPIDFile(path string) (file *os.File, err error) {
+	for i := 0; i < maxPIDFileAttempts; i++ {
+		var retry bool
+		file, retry, err = lockPIDFile(path)
+		if !retry {
+			return file, err
+		}
+	}
+
+	return nil, &PIDFileError{
+		Err:  errors.Error("file keeps being replaced"),
+		Path: path,
+	}
+}
+
+// lockPIDFile opens the PID file at path and locks it.  retry is true if the
+// file has been removed or replaced by another process between opening and
+// locking it, for example by an instance that has been shutting down, in which
+// case the lock would protect a file that no longer exists at path.
+func lockPIDFile(path string) (file *os.File, retry bool, err error) {
+	file, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, false, &PIDFileError{
+			Err:  err,
+			Path: path,
+		}
+	}
+
+	err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if err != nil {
+		return nil, false, lockError(path, errors.WithDeferred(err, file.Close()))
+	}
+
+	same, err := isSameFile(file, path)
+	if err == nil && same {
+		return file, false, nil
+	}
+
+	err = errors.WithDeferred(err, file.Close())
+	if err != nil {
+		return nil, false, &PIDFileError{
+			Err:  err,
+			Path: path,
+		}
+	}
+
+	return nil, true, nil
+}
+
+// lockError returns the error for a failed attempt to lock the PID file at
+// path.
+func lockError(path string, err error) (pidErr *PIDFileError) {
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return &PIDFileError{
+			Err:  ErrAlreadyRunning,
+			Path: path,
+			PID:  readPID(path),
+		}
+	}
+
+	return &PIDFileError{
+		Err:  err,
+		Path: path,
+	}
+}
+
+// isSameFile returns true if file is still the file at path.
+func isSameFile(file *os.File, path string) (ok bool, err error) {
+	fi, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	pathFI, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return os.SameFile(fi, pathFI), nil
+}
+
+// removePIDFile removes the PID file at path and closes file.  The file is
+// removed before it's closed so that another instance doesn't acquire the lock
+// on the file that is about to be removed.
+func removePIDFile(file *os.File, path string) (err error) {
+	err = os.Remove(path)
+
+	return errors.WithDeferred(err, file.Close())
+}
@@ -0,0 +1,61 @@
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package osutil_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/osutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePIDFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "test.pid")
+	wantData := strconv.Itoa(os.Getpid()) + "\n"
+
+	t.Run("stale", func(t *testing.T) {
+		err := os.WriteFile(path, []byte("999999999\n"), 0o644)
+		require.NoError(t, err)
+
+		f, err := osutil.CreatePIDFile(path)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, wantData, string(data))
+
+		require.NoError(t, f.Remove())
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("already_running", func(t *testing.T) {
+		f, err := osutil.CreatePIDFile(path)
+		require.NoError(t, err)
+
+		t.Cleanup(func() { require.NoError(t, f.Remove()) })
+
+		_, err = osutil.CreatePIDFile(path)
+		require.ErrorIs(t, err, osutil.ErrAlreadyRunning)
+
+		pidErr := &osutil.PIDFileError{}
+		require.ErrorAs(t, err, &pidErr)
+
+		assert.Equal(t, os.Getpid(), pidErr.PID)
+		assert.Equal(t, path, pidErr.Path)
+	})
+
+	t.Run("no_dir", func(t *testing.T) {
+		_, err := osutil.CreatePIDFile(filepath.Join(path+"_dir", "test.pid"))
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assert.NotErrorIs(t, err, osutil.ErrAlreadyRunning)
+	})
+}
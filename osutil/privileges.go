@@ -0,0 +1,71 @@
+package osutil
+
+import (
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// ErrPrivilegesRestorable is returned by DropPrivileges when the process is
+// still able to regain the superuser privileges after dropping them.
+const ErrPrivilegesRestorable errors.Error = "privileges can be restored"
+
+// PrivilegeStage is the stage of dropping privileges.
+type PrivilegeStage string
+
+// PrivilegeStage values.
+const (
+	// PrivilegeStageLookup is the stage of looking up the user and the group.
+	PrivilegeStageLookup PrivilegeStage = "lookup"
+
+	// PrivilegeStageSetgroups is the stage of dropping the supplementary
+	// groups.
+	PrivilegeStageSetgroups PrivilegeStage = "setgroups"
+
+	// PrivilegeStageSetgid is the stage of setting the group ID.
+	PrivilegeStageSetgid PrivilegeStage = "setgid"
+
+	// PrivilegeStageSetuid is the stage of setting the user ID.
+	PrivilegeStageSetuid PrivilegeStage = "setuid"
+
+	// PrivilegeStageVerify is the stage of verifying that the privileges
+	// cannot be restored.
+	PrivilegeStageVerify PrivilegeStage = "verify"
+)
+
+// PrivilegeError is returned by DropPrivileges and describes the stage at
+// which dropping privileges failed.
+type PrivilegeError struct {
+	// Err is the underlying error.  It must not be nil.
+	Err error
+
+	// Stage is the stage at which the error occurred.
+	Stage PrivilegeStage
+}
+
+// type check
+var _ errors.Wrapper = (*PrivilegeError)(nil)
+
+// Error implements the error interface for *PrivilegeError.
+func (err *PrivilegeError) Error() (msg string) {
+	return fmt.Sprintf("dropping privileges: %s: %s", err.Stage, err.Err)
+}
+
+// Unwrap implements the errors.Wrapper interface for *PrivilegeError.
+func (err *PrivilegeError) Unwrap() (unwrapped error) {
+	return err.Err
+}
+
+// DropPrivileges changes the user and the group of the current process to the
+// ones with the names userName and groupName and drops all supplementary
+// groups.  If groupName is empty, the primary group of the user is used.  It
+// should be called after all privileged resources, such as sockets on ports
+// below 1024, have been acquired.
+//
+// On success, DropPrivileges also verifies that the superuser privileges cannot
+// be regained.  Any error returned has the type *PrivilegeError.  On platforms
+// that don't support changing the user, it returns an error wrapping
+// errors.ErrUnsupported.
+func DropPrivileges(userName, groupName string) (err error) {
+	return dropPrivileges(userName, groupName)
+}
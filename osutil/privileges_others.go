@@ -0,0 +1,20 @@
+//go:build !(darwin || freebsd || linux || netbsd || openbsd)
+// +build !darwin,!freebsd,!linux,!netbsd,!openbsd
+
+package osutil
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// dropPrivileges is the implementation of DropPrivileges for the platforms that
+// don't support changing the user of a process.
+func dropPrivileges(_, _ string) (err error) {
+	return &PrivilegeError{
+		Err:   fmt.Errorf("%s: %w", runtime.GOOS, errors.ErrUnsupported),
+		Stage: PrivilegeStageSetuid,
+	}
+}
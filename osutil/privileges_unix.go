@@ -0,0 +1,117 @@
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package osutil
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// dropPrivileges is the Unix implementation of DropPrivileges.
+func dropPrivileges(userName, groupName string) (err error) {
+	uid, gid, err := lookupIDs(userName, groupName)
+	if err != nil {
+		return &PrivilegeError{
+			Err:   err,
+			Stage: PrivilegeStageLookup,
+		}
+	}
+
+	// The order is important, since after setting the user ID the process
+	// is no longer able to change its groups.
+	err = syscall.Setgroups([]int{gid})
+	if err != nil {
+		return &PrivilegeError{
+			Err:   err,
+			Stage: PrivilegeStageSetgroups,
+		}
+	}
+
+	err = syscall.Setgid(gid)
+	if err != nil {
+		return &PrivilegeError{
+			Err:   err,
+			Stage: PrivilegeStageSetgid,
+		}
+	}
+
+	err = syscall.Setuid(uid)
+	if err != nil {
+		return &PrivilegeError{
+			Err:   err,
+			Stage: PrivilegeStageSetuid,
+		}
+	}
+
+	err = verifyDropped(uid, gid)
+	if err != nil {
+		return &PrivilegeError{
+			Err:   err,
+			Stage: PrivilegeStageVerify,
+		}
+	}
+
+	return nil
+}
+
+// lookupIDs returns the numeric IDs of the user and the group.  If groupName
+// is empty, the primary group of the user is used.
+func lookupIDs(userName, groupName string) (uid, gid int, err error) {
+	u, err := user.Lookup(userName)
+	if err != nil {
+		return 0, 0, fmt.Errorf("looking up user: %w", err)
+	}
+
+	uid, err = strconv.Atoi(u.Uid)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parsing uid: %w", err)
+	}
+
+	gidStr := u.Gid
+	if groupName != "" {
+		var g *user.Group
+		g, err = user.LookupGroup(groupName)
+		if err != nil {
+			return 0, 0, fmt.Errorf("looking up group: %w", err)
+		}
+
+		gidStr = g.Gid
+	}
+
+	gid, err = strconv.Atoi(gidStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parsing gid: %w", err)
+	}
+
+	return uid, gid, nil
+}
+
+// verifyDropped checks that the real and effective IDs of the process are uid
+// and gid and that the superuser privileges cannot be regained.
+func verifyDropped(uid, gid int) (err error) {
+	if ruid, euid := syscall.Getuid(), syscall.Geteuid(); ruid != uid || euid != uid {
+		return fmt.Errorf("uid is %d, euid is %d, want %d", ruid, euid, uid)
+	}
+
+	if rgid, egid := syscall.Getgid(), syscall.Getegid(); rgid != gid || egid != gid {
+		return fmt.Errorf("gid is %d, egid is %d, want %d", rgid, egid, gid)
+	}
+
+	if uid == 0 {
+		// The target user is the superuser, so there is nothing to verify.
+		return nil
+	}
+
+	if syscall.Setuid(0) == nil {
+		return fmt.Errorf("setuid(0): %w", ErrPrivilegesRestorable)
+	}
+
+	if gid != 0 && syscall.Setgid(0) == nil {
+		return fmt.Errorf("setgid(0): %w", ErrPrivilegesRestorable)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package osutil_test
+
+import (
+	"testing"
+
+	"github.com/AdguardTeam/golibs/osutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDropPrivileges_lookup(t *testing.T) {
+	t.Parallel()
+
+	err := osutil.DropPrivileges("nonexistent-test-user", "")
+	require.Error(t, err)
+
+	privErr := &osutil.PrivilegeError{}
+	require.ErrorAs(t, err, &privErr)
+
+	assert.Equal(t, osutil.PrivilegeStageLookup, privErr.Stage)
+}
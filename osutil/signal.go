@@ -0,0 +1,178 @@
+package osutil
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// SignalEvent is the type of event produced by an OS signal.
+type SignalEvent uint8
+
+// SignalEvent values.
+const (
+	// SignalEventShutdown means that the program should shut down.  It is
+	// produced by SIGINT and SIGTERM, and on Unix also by SIGQUIT.
+	SignalEventShutdown SignalEvent = iota + 1
+
+	// SignalEventReload means that the program should reload its
+	// configuration.  It is produced by SIGHUP on Unix and never on Windows.
+	SignalEventReload
+)
+
+// String implements the fmt.Stringer interface for SignalEvent.
+func (ev SignalEvent) String() (s string) {
+	switch ev {
+	case SignalEventShutdown:
+		return "shutdown"
+	case SignalEventReload:
+		return "reload"
+	default:
+		return fmt.Sprintf("!bad_signal_event_%d", ev)
+	}
+}
+
+// SignalNotifier is the interface for the functions of package os/signal, which
+// allows replacing them in tests.
+type SignalNotifier interface {
+	// Notify starts relaying the signals sig to c, like signal.Notify.
+	Notify(c chan<- os.Signal, sig ...os.Signal)
+
+	// Stop stops relaying the signals to c, like signal.Stop.
+	Stop(c chan<- os.Signal)
+}
+
+// DefaultSignalNotifier is a SignalNotifier that uses package os/signal.
+type DefaultSignalNotifier struct{}
+
+// type check
+var _ SignalNotifier = DefaultSignalNotifier{}
+
+// Notify implements the SignalNotifier interface for DefaultSignalNotifier.
+func (DefaultSignalNotifier) Notify(c chan<- os.Signal, sig ...os.Signal) {
+	signal.Notify(c, sig...)
+}
+
+// Stop implements the SignalNotifier interface for DefaultSignalNotifier.
+func (DefaultSignalNotifier) Stop(c chan<- os.Signal) {
+	signal.Stop(c)
+}
+
+// SignalHandlerFunc handles a signal event.
+type SignalHandlerFunc func(ctx context.Context, ev SignalEvent) (err error)
+
+// CancelOnShutdown returns a SignalHandlerFunc that calls cancel on a shutdown
+// event, which allows canceling a context when the program is asked to shut
+// down.
+func CancelOnShutdown(cancel context.CancelFunc) (f SignalHandlerFunc) {
+	return func(_ context.Context, ev SignalEvent) (err error) {
+		if ev == SignalEventShutdown {
+			cancel()
+		}
+
+		return nil
+	}
+}
+
+// SignalHandlerConfig is the configuration structure for a *SignalHandler.
+type SignalHandlerConfig struct {
+	// Notifier is used to subscribe to the signals.  If it is nil,
+	// DefaultSignalNotifier is used.
+	Notifier SignalNotifier
+
+	// Logger is used to log the signals and the errors of handling reload
+	// events.  If it is nil, slog.Default is used.
+	Logger *slog.Logger
+}
+
+// SignalHandler translates OS signals into signal events and passes them to
+// the registered handlers in the order of registration.
+type SignalHandler struct {
+	notifier SignalNotifier
+	logger   *slog.Logger
+
+	// mu protects handlers.
+	mu       *sync.Mutex
+	handlers []SignalHandlerFunc
+}
+
+// NewSignalHandler returns a new properly initialized *SignalHandler.  c must
+// not be nil.
+func NewSignalHandler(c *SignalHandlerConfig) (h *SignalHandler) {
+	notifier := c.Notifier
+	if notifier == nil {
+		notifier = DefaultSignalNotifier{}
+	}
+
+	logger := c.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SignalHandler{
+		notifier: notifier,
+		logger:   logger,
+		mu:       &sync.Mutex{},
+	}
+}
+
+// Handle registers f to be called on every signal event.
+func (h *SignalHandler) Handle(f SignalHandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.handlers = append(h.handlers, f)
+}
+
+// Run listens for the signals until a shutdown event or until ctx is done.
+// Reload events are passed to the handlers, and their errors are logged.  On a
+// shutdown event, all handlers are called even if some of them fail, and err
+// contains all their errors.  If ctx is done first, err is ctx.Err().
+func (h *SignalHandler) Run(ctx context.Context) (err error) {
+	sigCh := make(chan os.Signal, 1)
+	h.notifier.Notify(sigCh, handledSignals()...)
+	defer h.notifier.Stop(sigCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case sig := <-sigCh:
+			ev, ok := signalEvent(sig)
+			if !ok {
+				continue
+			}
+
+			h.logger.InfoContext(ctx, "received signal", "signal", sig, "event", ev)
+
+			err = h.dispatch(ctx, ev)
+			if ev == SignalEventShutdown {
+				return err
+			} else if err != nil {
+				h.logger.ErrorContext(ctx, "handling signal", "event", ev, slog.Any("err", err))
+			}
+		}
+	}
+}
+
+// dispatch calls all handlers for ev and returns their joined errors.
+func (h *SignalHandler) dispatch(ctx context.Context, ev SignalEvent) (err error) {
+	h.mu.Lock()
+	handlers := append([]SignalHandlerFunc(nil), h.handlers...)
+	h.mu.Unlock()
+
+	var errs []error
+	for i, f := range handlers {
+		hErr := f(ctx, ev)
+		if hErr != nil {
+			errs = append(errs, fmt.Errorf("handler at index %d: %w", i, hErr))
+		}
+	}
+
+	return errors.Join(errs...)
+}
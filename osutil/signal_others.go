@@ -0,0 +1,23 @@
+//go:build js || plan9 || wasip1
+// +build js plan9 wasip1
+
+package osutil
+
+import "os"
+
+// handledSignals returns the signals translated into events.  Only the
+// interrupt is portable across these platforms.
+func handledSignals() (sigs []os.Signal) {
+	return []os.Signal{os.Interrupt}
+}
+
+// signalEvent returns the event for sig.  ok is false if sig doesn't produce
+// any.
+func signalEvent(sig os.Signal) (ev SignalEvent, ok bool) {
+	switch sig {
+	case os.Interrupt:
+		return SignalEventShutdown, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,32 @@
+//go:build !(windows || js || plan9 || wasip1)
+// +build !windows,!js,!plan9,!wasip1
+
+package osutil
+
+import (
+	"os"
+	"syscall"
+)
+
+// handledSignals returns the signals translated into events.
+func handledSignals() (sigs []os.Signal) {
+	return []os.Signal{
+		syscall.SIGINT,
+		syscall.SIGTERM,
+		syscall.SIGQUIT,
+		syscall.SIGHUP,
+	}
+}
+
+// signalEvent returns the event for sig.  ok is false if sig doesn't produce
+// any.
+func signalEvent(sig os.Signal) (ev SignalEvent, ok bool) {
+	switch sig {
+	case syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT:
+		return SignalEventShutdown, true
+	case syscall.SIGHUP:
+		return SignalEventReload, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,126 @@
+//go:build !(windows || js || plan9 || wasip1)
+// +build !windows,!js,!plan9,!wasip1
+
+package osutil_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/osutil"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTimeout is the common timeout for tests.
+const testTimeout = 1 * time.Second
+
+// testNotifier is an osutil.SignalNotifier for tests.
+type testNotifier struct {
+	chCh chan chan<- os.Signal
+}
+
+// type check
+var _ osutil.SignalNotifier = (*testNotifier)(nil)
+
+// Notify implements the osutil.SignalNotifier interface for *testNotifier.
+func (n *testNotifier) Notify(c chan<- os.Signal, _ ...os.Signal) {
+	n.chCh <- c
+}
+
+// Stop implements the osutil.SignalNotifier interface for *testNotifier.
+func (n *testNotifier) Stop(_ chan<- os.Signal) {}
+
+func TestSignalHandler_Run(t *testing.T) {
+	t.Parallel()
+
+	const testErr errors.Error = "test error"
+
+	notifier := &testNotifier{
+		chCh: make(chan chan<- os.Signal, 1),
+	}
+
+	h := osutil.NewSignalHandler(&osutil.SignalHandlerConfig{
+		Notifier: notifier,
+		Logger:   slogutil.NewDiscardLogger(),
+	})
+
+	var got []string
+	h.Handle(func(_ context.Context, ev osutil.SignalEvent) (err error) {
+		got = append(got, "first "+ev.String())
+
+		return testErr
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	h.Handle(osutil.CancelOnShutdown(cancel))
+	h.Handle(func(_ context.Context, ev osutil.SignalEvent) (err error) {
+		got = append(got, "second "+ev.String())
+
+		return nil
+	})
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- h.Run(context.Background())
+	}()
+
+	var sigCh chan<- os.Signal
+	require.Eventually(t, func() (ok bool) {
+		select {
+		case sigCh = <-notifier.chCh:
+			return true
+		default:
+			return false
+		}
+	}, testTimeout, testTimeout/10)
+
+	sigCh <- syscall.SIGHUP
+	sigCh <- syscall.SIGTERM
+
+	var err error
+	require.Eventually(t, func() (ok bool) {
+		select {
+		case err = <-errCh:
+			return true
+		default:
+			return false
+		}
+	}, testTimeout, testTimeout/10)
+
+	testutil.AssertErrorMsg(t, "handler at index 0: test error", err)
+	assert.Equal(t, []string{
+		"first reload",
+		"second reload",
+		"first shutdown",
+		"second shutdown",
+	}, got)
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestSignalHandler_Run_context(t *testing.T) {
+	t.Parallel()
+
+	notifier := &testNotifier{
+		chCh: make(chan chan<- os.Signal, 1),
+	}
+
+	h := osutil.NewSignalHandler(&osutil.SignalHandlerConfig{
+		Notifier: notifier,
+		Logger:   slogutil.NewDiscardLogger(),
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := h.Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,29 @@
+//go:build windows
+// +build windows
+
+package osutil
+
+import (
+	"os"
+	"syscall"
+)
+
+// handledSignals returns the signals translated into events.  Windows has no
+// reload signal, so only the shutdown ones are handled.
+func handledSignals() (sigs []os.Signal) {
+	return []os.Signal{
+		os.Interrupt,
+		syscall.SIGTERM,
+	}
+}
+
+// signalEvent returns the event for sig.  ok is false if sig doesn't produce
+// any.
+func signalEvent(sig os.Signal) (ev SignalEvent, ok bool) {
+	switch sig {
+	case os.Interrupt, syscall.SIGTERM:
+		return SignalEventShutdown, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// GroupConfig is the configuration structure for a *Group.
+type GroupConfig struct {
+	// Logger is used to log the starting and the shutting down of the
+	// services.  If it is nil, slog.Default is used.
+	Logger *slog.Logger
+
+	// StartTimeout is the timeout for starting a single service.  If it is
+	// zero, there is no timeout.
+	StartTimeout time.Duration
+
+	// ShutdownTimeout is the timeout for shutting down a single service.  If
+	// it is zero, there is no timeout.
+	ShutdownTimeout time.Duration
+}
+
+// Group is a service that manages the lifecycle of several services.  It
+// starts them in the order of addition and shuts them down in reverse.
+type Group struct {
+	logger *slog.Logger
+
+	// mu protects services and started.
+	mu       *sync.Mutex
+	services []*groupEntry
+
+	// started is the number of services that have been started.
+	started int
+
+	startTimeout    time.Duration
+	shutdownTimeout time.Duration
+}
+
+// groupEntry is a named service within a group.
+type groupEntry struct {
+	svc  Interface
+	name string
+}
+
+// NewGroup returns a new properly initialized *Group.  c must not be nil.
+func NewGroup(c *GroupConfig) (g *Group) {
+	logger := c.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Group{
+		logger:          logger,
+		mu:              &sync.Mutex{},
+		startTimeout:    c.StartTimeout,
+		shutdownTimeout: c.ShutdownTimeout,
+	}
+}
+
+// type check
+var _ Interface = (*Group)(nil)
+
+// Add adds svc with the name used in logs and errors to g.  It must not be
+// called after g has been started.
+func (g *Group) Add(name string, svc Interface) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.services = append(g.services, &groupEntry{
+		svc:  svc,
+		name: name,
+	})
+}
+
+// Start implements the Interface interface for *Group.  It starts the services
+// in the order of addition.  If one of them fails, the ones already started are
+// shut down in reverse order, and err contains all errors.
+func (g *Group) Start(ctx context.Context) (err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, e := range g.services[g.started:] {
+		g.logger.DebugContext(ctx, "starting service", "name", e.name)
+
+		err = callWithTimeout(ctx, g.startTimeout, e.svc.Start)
+		if err != nil {
+			err = fmt.Errorf("starting service %q: %w", e.name, err)
+
+			// Use a context that is not canceled, since ctx might be
+			// the reason for the failure.
+			shutdownErr := g.shutdownLocked(context.WithoutCancel(ctx))
+
+			return errors.Join(err, shutdownErr)
+		}
+
+		g.started++
+	}
+
+	return nil
+}
+
+// Shutdown implements the Interface interface for *Group.  It shuts down the
+// started services in reverse order.  All services are shut down even if some
+// of them fail, and err contains all errors.
+func (g *Group) Shutdown(ctx context.Context) (err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.shutdownLocked(ctx)
+}
+
+// shutdownLocked shuts down the started services in reverse order.  g.mu must
+// be locked.
+func (g *Group) shutdownLocked(ctx context.Context) (err error) {
+	var errs []error
+	for ; g.started > 0; g.started-- {
+		e := g.services[g.started-1]
+
+		g.logger.DebugContext(ctx, "shutting down service", "name", e.name)
+
+		err = callWithTimeout(ctx, g.shutdownTimeout, e.svc.Shutdown)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("shutting down service %q: %w", e.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run starts g, waits until ctx is done, and shuts g down.  The shutdown isn't
+// affected by the cancelation of ctx; the timeouts of g are used instead.  To
+// shut down on an OS signal, cancel ctx using osutil.CancelOnShutdown.
+func (g *Group) Run(ctx context.Context) (err error) {
+	err = g.Start(ctx)
+	if err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+
+	return g.Shutdown(context.WithoutCancel(ctx))
+}
+
+// callWithTimeout calls f with ctx limited by timeout, if it's positive.
+func callWithTimeout(
+	ctx context.Context,
+	timeout time.Duration,
+	f func(ctx context.Context) (err error),
+) (err error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return f(ctx)
+}
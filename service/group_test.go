@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/logutil/slogutil"
+	"github.com/AdguardTeam/golibs/service"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testService is a service.Interface for tests.
+type testService struct {
+	onStart    func(ctx context.Context) (err error)
+	onShutdown func(ctx context.Context) (err error)
+}
+
+// type check
+var _ service.Interface = (*testService)(nil)
+
+// Start implements the service.Interface interface for *testService.
+func (s *testService) Start(ctx context.Context) (err error) {
+	return s.onStart(ctx)
+}
+
+// Shutdown implements the service.Interface interface for *testService.
+func (s *testService) Shutdown(ctx context.Context) (err error) {
+	return s.onShutdown(ctx)
+}
+
+// newRecordingService returns a *testService that appends its calls to calls
+// and returns startErr and shutdownErr.
+func newRecordingService(
+	name string,
+	calls *[]string,
+	startErr error,
+	shutdownErr error,
+) (s *testService) {
+	return &testService{
+		onStart: func(_ context.Context) (err error) {
+			*calls = append(*calls, "start "+name)
+
+			return startErr
+		},
+		onShutdown: func(_ context.Context) (err error) {
+			*calls = append(*calls, "shutdown "+name)
+
+			return shutdownErr
+		},
+	}
+}
+
+// newTestGroup returns a new *service.Group for tests.
+func newTestGroup() (g *service.Group) {
+	return service.NewGroup(&service.GroupConfig{
+		Logger:          slogutil.NewDiscardLogger(),
+		StartTimeout:    testTimeout,
+		ShutdownTimeout: testTimeout,
+	})
+}
+
+// testTimeout is the common timeout for tests.
+const testTimeout = 1 * time.Second
+
+// testErr is the common error for tests.
+const testErr errors.Error = "test error"
+
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		g := newTestGroup()
+		g.Add("a", newRecordingService("a", &calls, nil, nil))
+		g.Add("b", newRecordingService("b", &calls, nil, testErr))
+		g.Add("c", newRecordingService("c", &calls, nil, nil))
+
+		require.NoError(t, g.Start(context.Background()))
+
+		err := g.Shutdown(context.Background())
+		testutil.AssertErrorMsg(t, `shutting down service "b": test error`, err)
+
+		assert.Equal(t, []string{
+			"start a",
+			"start b",
+			"start c",
+			"shutdown c",
+			"shutdown b",
+			"shutdown a",
+		}, calls)
+	})
+
+	t.Run("start_error", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		g := newTestGroup()
+		g.Add("a", newRecordingService("a", &calls, nil, testErr))
+		g.Add("b", newRecordingService("b", &calls, testErr, nil))
+		g.Add("c", newRecordingService("c", &calls, nil, nil))
+
+		err := g.Start(context.Background())
+		assert.ErrorIs(t, err, testErr)
+
+		assert.Equal(t, []string{
+			"start a",
+			"start b",
+			"shutdown a",
+		}, calls)
+
+		require.NoError(t, g.Shutdown(context.Background()))
+		assert.Len(t, calls, 3)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		g := service.NewGroup(&service.GroupConfig{
+			Logger:       slogutil.NewDiscardLogger(),
+			StartTimeout: testTimeout / 10,
+		})
+		g.Add("slow", &testService{
+			onStart: func(ctx context.Context) (err error) {
+				<-ctx.Done()
+
+				return ctx.Err()
+			},
+		})
+
+		err := g.Start(context.Background())
+		testutil.AssertErrorMsg(t, `starting service "slow": context deadline exceeded`, err)
+	})
+}
+
+func TestGroup_Run(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	g := newTestGroup()
+	g.Add("a", newRecordingService("a", &calls, nil, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.NoError(t, g.Run(ctx))
+	assert.Equal(t, []string{"start a", "shutdown a"}, calls)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// HealthStatus is the health status of a single service.
+type HealthStatus struct {
+	// Updated is the time of the last report.  It is set by the registry.
+	Updated time.Time `json:"updated"`
+
+	// Message is an optional human-readable description of the status, for
+	// example the reason the service is not ready.
+	Message string `json:"message,omitempty"`
+
+	// Alive is true if the service is functioning, even if it is not yet able
+	// to serve.  A service that is not alive should be restarted.
+	Alive bool `json:"alive"`
+
+	// Ready is true if the service is able to serve.
+	Ready bool `json:"ready"`
+}
+
+// HealthSnapshot is the aggregate health status of all services in a registry.
+type HealthSnapshot struct {
+	// Services are the statuses of the individual services by their names.
+	Services map[string]*HealthStatus `json:"services"`
+
+	// Alive is true if all services are alive.
+	Alive bool `json:"alive"`
+
+	// Ready is true if all services are ready.
+	Ready bool `json:"ready"`
+}
+
+// HealthRegistryConfig is the configuration structure for a *HealthRegistry.
+type HealthRegistryConfig struct {
+	// Clock is used to set the update times of the statuses.  If it is nil,
+	// timeutil.SystemClock is used.
+	Clock timeutil.Clock
+}
+
+// HealthRegistry is a registry where services report their health statuses.
+type HealthRegistry struct {
+	clock timeutil.Clock
+
+	// mu protects statuses.
+	mu       *sync.Mutex
+	statuses map[string]*HealthStatus
+}
+
+// NewHealthRegistry returns a new properly initialized *HealthRegistry.  c must
+// not be nil.
+func NewHealthRegistry(c *HealthRegistryConfig) (r *HealthRegistry) {
+	clock := c.Clock
+	if clock == nil {
+		clock = timeutil.SystemClock{}
+	}
+
+	return &HealthRegistry{
+		clock:    clock,
+		mu:       &sync.Mutex{},
+		statuses: map[string]*HealthStatus{},
+	}
+}
+
+// Report sets the status of the service with the given name.  The Updated field
+// of s is ignored.
+func (r *HealthRegistry) Report(name string, s HealthStatus) {
+	s.Updated = r.clock.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.statuses[name] = &s
+}
+
+// Remove removes the status of the service with the given name.
+func (r *HealthRegistry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.statuses, name)
+}
+
+// Snapshot returns the current statuses of all services.  An empty registry is
+// considered alive and ready.
+func (r *HealthRegistry) Snapshot() (s *HealthSnapshot) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s = &HealthSnapshot{
+		Services: make(map[string]*HealthStatus, len(r.statuses)),
+		Alive:    true,
+		Ready:    true,
+	}
+
+	for name, st := range r.statuses {
+		stCopy := *st
+		s.Services[name] = &stCopy
+		s.Alive = s.Alive && st.Alive
+		s.Ready = s.Ready && st.Ready
+	}
+
+	return s
+}
+
+// LivenessHandler returns an HTTP handler that responds with the JSON-encoded
+// snapshot and the status 200 OK if all services are alive and 503 Service
+// Unavailable otherwise, which is suitable for liveness probes.
+func (r *HealthRegistry) LivenessHandler() (h http.Handler) {
+	return r.handler(func(s *HealthSnapshot) (ok bool) { return s.Alive })
+}
+
+// ReadinessHandler is like LivenessHandler but checks that all services are
+// ready, which is suitable for readiness probes.
+func (r *HealthRegistry) ReadinessHandler() (h http.Handler) {
+	return r.handler(func(s *HealthSnapshot) (ok bool) { return s.Ready })
+}
+
+// handler returns an HTTP handler that responds with the snapshot and the
+// status depending on the result of isHealthy.
+func (r *HealthRegistry) handler(isHealthy func(s *HealthSnapshot) (ok bool)) (h http.Handler) {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s := r.Snapshot()
+
+		status := http.StatusOK
+		if !isHealthy(s) {
+			status = http.StatusServiceUnavailable
+		}
+
+		// Don't use httputil.WriteJSON, since package httputil depends on
+		// this one.  The snapshot is always encodable.
+		b, _ := json.Marshal(s)
+
+		hdr := w.Header()
+		hdr.Set(httphdr.ContentType, "application/json")
+		hdr.Set(httphdr.CacheControl, "no-store")
+		w.WriteHeader(status)
+
+		_, _ = w.Write(b)
+	})
+}
@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/httphdr"
+	"github.com/AdguardTeam/golibs/service"
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthRegistry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := service.NewHealthRegistry(&service.HealthRegistryConfig{
+		Clock: timeutil.NewFakeClock(now),
+	})
+
+	assert.Equal(t, &service.HealthSnapshot{
+		Services: map[string]*service.HealthStatus{},
+		Alive:    true,
+		Ready:    true,
+	}, r.Snapshot())
+
+	r.Report("db", service.HealthStatus{Alive: true, Ready: true})
+	r.Report("cache", service.HealthStatus{Message: "warming up", Alive: true})
+
+	assert.Equal(t, &service.HealthSnapshot{
+		Services: map[string]*service.HealthStatus{
+			"db": {
+				Updated: now,
+				Alive:   true,
+				Ready:   true,
+			},
+			"cache": {
+				Updated: now,
+				Message: "warming up",
+				Alive:   true,
+			},
+		},
+		Alive: true,
+		Ready: false,
+	}, r.Snapshot())
+
+	testCases := []struct {
+		handler    http.Handler
+		name       string
+		wantStatus int
+	}{{
+		handler:    r.LivenessHandler(),
+		name:       "liveness",
+		wantStatus: http.StatusOK,
+	}, {
+		handler:    r.ReadinessHandler(),
+		name:       "readiness",
+		wantStatus: http.StatusServiceUnavailable,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rw := httptest.NewRecorder()
+			tc.handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+			assert.Equal(t, tc.wantStatus, rw.Code)
+			assert.Equal(t, "application/json", rw.Header().Get(httphdr.ContentType))
+			assert.JSONEq(t, `{
+				"alive": true,
+				"ready": false,
+				"services": {
+					"cache": {
+						"alive": true,
+						"message": "warming up",
+						"ready": false,
+						"updated": "2023-01-01T00:00:00Z"
+					},
+					"db": {
+						"alive": true,
+						"ready": true,
+						"updated": "2023-01-01T00:00:00Z"
+					}
+				}
+			}`, rw.Body.String())
+		})
+	}
+}
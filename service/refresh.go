@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/AdguardTeam/golibs/mathutil"
+	"github.com/AdguardTeam/golibs/timeutil"
+)
+
+// Refresher is the interface for entities that can update themselves.
+type Refresher interface {
+	// Refresh updates the entity.  ctx is canceled when the refresh times out
+	// or the worker is shut down.
+	Refresh(ctx context.Context) (err error)
+}
+
+// RefresherFunc is an adapter that allows using a function as a Refresher.
+type RefresherFunc func(ctx context.Context) (err error)
+
+// type check
+var _ Refresher = RefresherFunc(nil)
+
+// Refresh implements the Refresher interface for RefresherFunc.
+func (f RefresherFunc) Refresh(ctx context.Context) (err error) {
+	return f(ctx)
+}
+
+// RefreshErrorHandler handles the errors of periodic refreshes.
+type RefreshErrorHandler func(ctx context.Context, err error)
+
+// RefreshWorkerConfig is the configuration structure for a *RefreshWorker.
+type RefreshWorkerConfig struct {
+	// Refresher is called periodically.  It must not be nil.
+	Refresher Refresher
+
+	// Clock is used to wait between the refreshes.  If it is nil,
+	// timeutil.SystemClock is used.
+	Clock timeutil.Clock
+
+	// ErrorHandler is called with the errors of periodic refreshes.  If it is
+	// nil, the errors are logged with Logger.
+	ErrorHandler RefreshErrorHandler
+
+	// Logger is used to log the errors if ErrorHandler is nil.  If it is nil,
+	// slog.Default is used.
+	Logger *slog.Logger
+
+	// Rand is used to calculate the jitter.  If it is nil, math/rand.Float64
+	// is used.
+	Rand mathutil.RandFunc
+
+	// Interval is the base interval between the refreshes.  It must be
+	// positive.
+	Interval time.Duration
+
+	// Timeout is the timeout for a single refresh.  If it is zero, there is
+	// no timeout.
+	Timeout time.Duration
+
+	// JitterPercent is the maximum deviation of the actual interval from
+	// Interval in either direction, in percent.  See
+	// mathutil.JitterPercent.
+	JitterPercent uint
+
+	// RefreshOnStart, if true, makes Start refresh synchronously before
+	// starting the periodic refreshes.
+	RefreshOnStart bool
+}
+
+// RefreshWorker is a service that calls a Refresher periodically.
+type RefreshWorker struct {
+	refr     Refresher
+	clock    timeutil.Clock
+	errH     RefreshErrorHandler
+	rand     mathutil.RandFunc
+	done     chan struct{}
+	stopped  chan struct{}
+	interval time.Duration
+	timeout  time.Duration
+	jitter   uint
+	onStart  bool
+}
+
+// NewRefreshWorker returns a new properly initialized *RefreshWorker.  c must
+// not be nil.
+func NewRefreshWorker(c *RefreshWorkerConfig) (w *RefreshWorker) {
+	clock := c.Clock
+	if clock == nil {
+		clock = timeutil.SystemClock{}
+	}
+
+	errH := c.ErrorHandler
+	if errH == nil {
+		logger := c.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+
+		errH = func(ctx context.Context, err error) {
+			logger.ErrorContext(ctx, "refreshing", slog.Any("err", err))
+		}
+	}
+
+	return &RefreshWorker{
+		refr:     c.Refresher,
+		clock:    clock,
+		errH:     errH,
+		rand:     c.Rand,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+		interval: c.Interval,
+		timeout:  c.Timeout,
+		jitter:   c.JitterPercent,
+		onStart:  c.RefreshOnStart,
+	}
+}
+
+// type check
+var _ Interface = (*RefreshWorker)(nil)
+
+// Start implements the Interface interface for *RefreshWorker.  If the worker
+// is configured to refresh on start, err is the error of that refresh, and the
+// periodic refreshes are not started in that case.  It must only be called
+// once.
+func (w *RefreshWorker) Start(ctx context.Context) (err error) {
+	if w.onStart {
+		err = callWithTimeout(ctx, w.timeout, w.refr.Refresh)
+		if err != nil {
+			return fmt.Errorf("initial refresh: %w", err)
+		}
+	}
+
+	go w.refreshLoop()
+
+	return nil
+}
+
+// Shutdown implements the Interface interface for *RefreshWorker.  It cancels
+// the current refresh, if any, and waits for the worker to stop until ctx is
+// done.
+func (w *RefreshWorker) Shutdown(ctx context.Context) (err error) {
+	close(w.done)
+
+	select {
+	case <-w.stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("shutting down refresh worker: %w", ctx.Err())
+	}
+}
+
+// refreshLoop refreshes periodically until w is shut down.  It is intended to
+// be used as a goroutine.
+func (w *RefreshWorker) refreshLoop() {
+	defer close(w.stopped)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel the refresh in progress on shutdown.
+	go func() {
+		select {
+		case <-w.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	timer := w.clock.NewTimer(w.nextInterval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-timer.C():
+			err := callWithTimeout(ctx, w.timeout, w.refr.Refresh)
+			if err != nil && ctx.Err() == nil {
+				w.errH(ctx, err)
+			}
+
+			timer.Reset(w.nextInterval())
+		}
+	}
+}
+
+// nextInterval returns the interval until the next refresh.
+func (w *RefreshWorker) nextInterval() (d time.Duration) {
+	if w.jitter == 0 {
+		return w.interval
+	}
+
+	return mathutil.JitterPercent(w.interval, w.jitter, w.rand)
+}
@@ -0,0 +1,66 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/service"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRefreshIvl is the refresh interval for tests.
+const testRefreshIvl = 1 * time.Minute
+
+func TestRefreshWorker(t *testing.T) {
+	t.Parallel()
+
+	clock := testutil.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	refrCh := make(chan struct{}, 1)
+	errCh := make(chan error, 1)
+	w := service.NewRefreshWorker(&service.RefreshWorkerConfig{
+		Refresher: service.RefresherFunc(func(_ context.Context) (err error) {
+			refrCh <- struct{}{}
+
+			return testErr
+		}),
+		Clock: clock,
+		ErrorHandler: func(_ context.Context, err error) {
+			errCh <- err
+		},
+		Interval: testRefreshIvl,
+	})
+
+	require.NoError(t, w.Start(context.Background()))
+
+	for i := 0; i < 2; i++ {
+		clock.AdvanceWhenBlocked(t, 1, testRefreshIvl)
+
+		<-refrCh
+		assert.ErrorIs(t, <-errCh, testErr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	t.Cleanup(cancel)
+
+	require.NoError(t, w.Shutdown(ctx))
+	clock.AssertPendingTimers(t, 0)
+}
+
+func TestRefreshWorker_Start_refreshOnStart(t *testing.T) {
+	t.Parallel()
+
+	w := service.NewRefreshWorker(&service.RefreshWorkerConfig{
+		Refresher: service.RefresherFunc(func(_ context.Context) (err error) {
+			return testErr
+		}),
+		Interval:       testRefreshIvl,
+		RefreshOnStart: true,
+	})
+
+	err := w.Start(context.Background())
+	testutil.AssertErrorMsg(t, "initial refresh: test error", err)
+}
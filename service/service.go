@@ -0,0 +1,29 @@
+// Package service defines types and interfaces for long-running services that
+// can be started and shut down, as well as helpers for managing them.
+package service
+
+import "context"
+
+// Interface is the interface for long-running services.
+type Interface interface {
+	// Start starts the service.  ctx is used for cancelation of the starting
+	// itself, so implementations should not keep it for the lifetime of the
+	// service.
+	Start(ctx context.Context) (err error)
+
+	// Shutdown gracefully stops the service.  ctx is used to determine a
+	// deadline for the graceful shutdown.
+	Shutdown(ctx context.Context) (err error)
+}
+
+// Empty is an Interface implementation that does nothing.
+type Empty struct{}
+
+// type check
+var _ Interface = Empty{}
+
+// Start implements the Interface interface for Empty.
+func (Empty) Start(_ context.Context) (err error) { return nil }
+
+// Shutdown implements the Interface interface for Empty.
+func (Empty) Shutdown(_ context.Context) (err error) { return nil }
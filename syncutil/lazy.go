@@ -0,0 +1,63 @@
+package syncutil
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Lazy is a value initialized on the first use.  Unlike with sync.OnceValues,
+// a failed initialization is not cached, and it's retried on the next call to
+// Get.  Lazy is safe for concurrent use.
+type Lazy[T any] struct {
+	init func() (v T, err error)
+
+	// mu serializes the initialization.
+	mu *sync.Mutex
+
+	// val is only valid when done is true.
+	val  T
+	done *atomic.Bool
+}
+
+// NewLazy returns a new *Lazy that initializes its value using init.  init must
+// not be nil.
+func NewLazy[T any](init func() (v T, err error)) (l *Lazy[T]) {
+	return &Lazy[T]{
+		init: init,
+		mu:   &sync.Mutex{},
+		done: &atomic.Bool{},
+	}
+}
+
+// Get returns the value, initializing it if it hasn't been initialized
+// successfully yet.  If the initialization fails, err is the error returned by
+// init, and the next call retries it.  Concurrent calls wait for the ongoing
+// initialization.
+func (l *Lazy[T]) Get() (v T, err error) {
+	if l.done.Load() {
+		return l.val, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.done.Load() {
+		return l.val, nil
+	}
+
+	v, err = l.init()
+	if err != nil {
+		return v, err
+	}
+
+	l.val = v
+	l.done.Store(true)
+
+	return v, nil
+}
+
+// OnceValueErr returns a function that calls f until it succeeds and then
+// returns the value it returned.  The errors are not cached.  See Lazy.
+func OnceValueErr[T any](f func() (v T, err error)) (g func() (v T, err error)) {
+	return NewLazy(f).Get
+}
@@ -0,0 +1,66 @@
+package syncutil_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/syncutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLazy_Get(t *testing.T) {
+	t.Parallel()
+
+	const testErr errors.Error = "test error"
+
+	calls := 0
+	l := syncutil.NewLazy(func() (v int, err error) {
+		calls++
+		if calls == 1 {
+			return 0, testErr
+		}
+
+		return 42, nil
+	})
+
+	_, err := l.Get()
+	assert.ErrorIs(t, err, testErr)
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			v, getErr := l.Get()
+			assert.NoError(t, getErr)
+			assert.Equal(t, 42, v)
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, 2, calls)
+}
+
+func TestOnceValueErr(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	f := syncutil.OnceValueErr(func() (v string, err error) {
+		calls++
+
+		return "value", nil
+	})
+
+	for i := 0; i < 2; i++ {
+		v, err := f()
+		require.NoError(t, err)
+
+		assert.Equal(t, "value", v)
+	}
+
+	assert.Equal(t, 1, calls)
+}
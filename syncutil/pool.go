@@ -0,0 +1,60 @@
+package syncutil
+
+import "sync"
+
+// Pool is a typed wrapper around *sync.Pool.  It stores pointers to values to
+// avoid the allocations that happen when non-pointer values are converted to
+// interfaces.
+type Pool[T any] struct {
+	pool  *sync.Pool
+	reset func(v *T)
+}
+
+// NewPool returns a new *Pool that uses newFunc to create new values.  newFunc
+// must not be nil.
+func NewPool[T any](newFunc func() (v *T)) (p *Pool[T]) {
+	return NewPoolWithReset(newFunc, nil)
+}
+
+// NewPoolWithReset is like NewPool but also calls reset, if it's not nil, on
+// every value put into the pool, so that the values returned by Get are always
+// in their initial state.
+func NewPoolWithReset[T any](newFunc func() (v *T), reset func(v *T)) (p *Pool[T]) {
+	return &Pool[T]{
+		pool: &sync.Pool{
+			New: func() (v any) { return newFunc() },
+		},
+		reset: reset,
+	}
+}
+
+// NewSlicePool returns a new *Pool for slices of length l.  The slices are
+// resliced back to l when put into the pool.
+func NewSlicePool[E any](l int) (p *Pool[[]E]) {
+	return NewPoolWithReset(
+		func() (v *[]E) {
+			s := make([]E, l)
+
+			return &s
+		},
+		func(v *[]E) {
+			*v = (*v)[:l]
+		},
+	)
+}
+
+// Get selects an arbitrary item from the pool, removes it from the pool, and
+// returns it to the caller.  If the pool is empty, a new value is created.
+func (p *Pool[T]) Get() (v *T) {
+	return p.pool.Get().(*T)
+}
+
+// Put resets v, if the pool has a reset function, and adds it to the pool.  v
+// must not be used after that.
+func (p *Pool[T]) Put(v *T) {
+	if p.reset != nil {
+		p.reset(v)
+	}
+
+	p.pool.Put(v)
+}
@@ -0,0 +1,156 @@
+package syncutil
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// Semaphore is a context-aware weighted semaphore.  The waiters are served in
+// the FIFO order, so that large acquisitions are not starved by small ones.
+type Semaphore struct {
+	// mu protects cur and waiters.
+	mu      *sync.Mutex
+	waiters *list.List
+	size    int64
+	cur     int64
+}
+
+// semWaiter is a goroutine waiting to acquire a semaphore.
+type semWaiter struct {
+	ready chan struct{}
+	n     int64
+}
+
+// NewSemaphore returns a new *Semaphore with the given maximum combined weight.
+// size must be positive.
+func NewSemaphore(size int64) (s *Semaphore) {
+	if size <= 0 {
+		panic("syncutil: non-positive size for NewSemaphore")
+	}
+
+	return &Semaphore{
+		mu:      &sync.Mutex{},
+		waiters: list.New(),
+		size:    size,
+	}
+}
+
+// Acquire acquires the semaphore with a weight of n, blocking until the
+// resources are available or ctx is done.  On success, err is nil.  On failure,
+// err is ctx.Err(), and the semaphore is left unchanged.  If n is larger than
+// the size of the semaphore, Acquire blocks until ctx is done.
+func (s *Semaphore) Acquire(ctx context.Context, n int64) (err error) {
+	s.mu.Lock()
+	if s.size-s.cur >= n && s.waiters.Len() == 0 {
+		s.cur += n
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	if n > s.size {
+		s.mu.Unlock()
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+
+	w := &semWaiter{
+		ready: make(chan struct{}),
+		n:     n,
+	}
+	elem := s.waiters.PushBack(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		return s.cancelWaiter(ctx, elem, w)
+	}
+}
+
+// cancelWaiter removes the waiter after ctx is done.  If the waiter has been
+// served concurrently, the acquisition is considered successful.
+func (s *Semaphore) cancelWaiter(
+	ctx context.Context,
+	elem *list.Element,
+	w *semWaiter,
+) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		// Acquired after ctx was done, so pretend that the cancelation
+		// came first.  This is the same as in x/sync/semaphore.
+		err = ctx.Err()
+		s.cur -= w.n
+		s.notifyWaitersLocked()
+
+		return err
+	default:
+		isFront := s.waiters.Front() == elem
+		s.waiters.Remove(elem)
+
+		// If the waiter was at the front and there are free resources, the
+		// next waiters might now be served.
+		if isFront && s.size > s.cur {
+			s.notifyWaitersLocked()
+		}
+
+		return ctx.Err()
+	}
+}
+
+// TryAcquire acquires the semaphore with a weight of n without blocking.  ok is
+// false if the resources are not available, in which case the semaphore is left
+// unchanged.
+func (s *Semaphore) TryAcquire(n int64) (ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ok = s.size-s.cur >= n && s.waiters.Len() == 0
+	if ok {
+		s.cur += n
+	}
+
+	return ok
+}
+
+// Release releases the semaphore with a weight of n.  It panics if more is
+// released than has been acquired.
+func (s *Semaphore) Release(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cur -= n
+	if s.cur < 0 {
+		panic("syncutil: semaphore released more than acquired")
+	}
+
+	s.notifyWaitersLocked()
+}
+
+// notifyWaitersLocked serves the waiters in order while there are enough
+// resources for the first one.  s.mu must be locked.
+func (s *Semaphore) notifyWaitersLocked() {
+	for {
+		next := s.waiters.Front()
+		if next == nil {
+			return
+		}
+
+		w := next.Value.(*semWaiter)
+		if s.size-s.cur < w.n {
+			// Don't serve the smaller waiters behind the front one to
+			// avoid starving it.
+			return
+		}
+
+		s.cur += w.n
+		s.waiters.Remove(next)
+		close(w.ready)
+	}
+}
@@ -0,0 +1,68 @@
+package syncutil_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/syncutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTimeout is the common timeout for tests.
+const testTimeout = 1 * time.Second
+
+func TestSemaphore(t *testing.T) {
+	t.Parallel()
+
+	s := syncutil.NewSemaphore(3)
+
+	require.True(t, s.TryAcquire(2))
+	require.False(t, s.TryAcquire(2))
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	t.Cleanup(cancel)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Acquire(ctx, 3)
+	}()
+
+	// Wait for the goroutine to start waiting; TryAcquire must fail even for
+	// the available weight so that the waiter isn't starved.
+	require.Eventually(t, func() (ok bool) {
+		if s.TryAcquire(1) {
+			s.Release(1)
+
+			return false
+		}
+
+		return true
+	}, testTimeout, testTimeout/100)
+
+	s.Release(2)
+	require.NoError(t, <-errCh)
+
+	assert.False(t, s.TryAcquire(1))
+	s.Release(3)
+	assert.True(t, s.TryAcquire(3))
+}
+
+func TestSemaphore_Acquire_cancel(t *testing.T) {
+	t.Parallel()
+
+	s := syncutil.NewSemaphore(1)
+	require.NoError(t, s.Acquire(context.Background(), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout/10)
+	t.Cleanup(cancel)
+
+	err := s.Acquire(ctx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	s.Release(1)
+	assert.True(t, s.TryAcquire(1))
+
+	assert.Panics(t, func() { s.Release(2) })
+}
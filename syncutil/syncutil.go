@@ -0,0 +1,3 @@
+// Package syncutil contains extensions and utilities for package sync from the
+// standard library.
+package syncutil
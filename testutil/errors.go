@@ -0,0 +1,112 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// ErrorAssertion describes the expected properties of an error for
+// AssertError.  Empty fields are not checked.
+type ErrorAssertion struct {
+	// Is are the errors that must all be found in the error's chain using
+	// errors.Is.
+	Is []error
+
+	// As are the pointers to the targets, each of which must be found in the
+	// error's chain using errors.As.  nil elements are ignored.
+	As []any
+
+	// Msg is the expected error message.
+	Msg string
+
+	// Kind is the expected kind of the error, as returned by errors.KindOf.
+	Kind errors.Kind
+
+	// Code is the expected code of the error, as returned by errors.CodeOf.
+	Code errors.Code
+}
+
+// AssertError asserts that err has all the properties described by want.  If
+// want is nil, AssertError asserts that err is nil instead.  All checks are
+// performed even if some of them fail, so that all mismatches are reported at
+// once.
+func AssertError(t testing.TB, want *ErrorAssertion, err error) (ok bool) {
+	t.Helper()
+
+	if want == nil {
+		return assert.NoError(t, err)
+	}
+
+	if !assert.Error(t, err) {
+		return false
+	}
+
+	ok = true
+	if want.Msg != "" {
+		ok = assert.Equal(t, want.Msg, err.Error())
+	}
+
+	// Don't short-circuit, so that all mismatches are reported.
+	ok = assertErrorChain(t, want, err) && ok
+	ok = assertErrorClass(t, want, err) && ok
+
+	return ok
+}
+
+// assertErrorChain asserts that the targets of want.Is and want.As are found in
+// the chain of err.
+func assertErrorChain(t testing.TB, want *ErrorAssertion, err error) (ok bool) {
+	t.Helper()
+
+	ok = true
+	for _, target := range want.Is {
+		ok = assert.ErrorIs(t, err, target) && ok
+	}
+
+	for _, target := range want.As {
+		if target != nil {
+			ok = assert.ErrorAs(t, err, target) && ok
+		}
+	}
+
+	return ok
+}
+
+// assertErrorClass asserts that the kind and the code of err are equal to the
+// ones in want, if set.
+func assertErrorClass(t testing.TB, want *ErrorAssertion, err error) (ok bool) {
+	t.Helper()
+
+	ok = true
+	if want.Kind != "" {
+		ok = assert.Equalf(t, want.Kind, errors.KindOf(err), "error kind") && ok
+	}
+
+	if want.Code != "" {
+		ok = assert.Equalf(t, want.Code, errors.CodeOf(err), "error code") && ok
+	}
+
+	return ok
+}
+
+// AssertErrorChain asserts that the error is not nil, that its message is
+// equal to msg, and that each of targets is found in its chain using
+// errors.As.  nil targets are ignored.  If msg is an empty string,
+// AssertErrorChain asserts that the error is nil instead, which allows using it
+// in table tests with both good and bad cases:
+//
+//   testutil.AssertErrorChain(t, tc.wantErrMsg, err, tc.wantErrAs)
+func AssertErrorChain(t testing.TB, msg string, err error, targets ...any) (ok bool) {
+	t.Helper()
+
+	if msg == "" {
+		return assert.NoError(t, err)
+	}
+
+	return AssertError(t, &ErrorAssertion{
+		As:  targets,
+		Msg: msg,
+	}, err)
+}
@@ -0,0 +1,59 @@
+package testutil_test
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertError(t *testing.T) {
+	t.Parallel()
+
+	const errTest errors.Error = testErrMsg
+
+	err := fmt.Errorf("wrapped: %w", errors.WithCode(errors.NotFound(&fs.PathError{
+		Op:   "open",
+		Path: "hosts",
+		Err:  errTest,
+	}), errors.CodeNotFound))
+
+	t.Run("success", func(t *testing.T) {
+		ok := testutil.AssertError(t, &testutil.ErrorAssertion{
+			Is:   []error{errTest},
+			As:   []any{new(*fs.PathError), nil},
+			Msg:  "wrapped: open hosts: test error",
+			Kind: errors.KindNotFound,
+			Code: errors.CodeNotFound,
+		}, err)
+		assert.True(t, ok)
+
+		assert.True(t, testutil.AssertError(t, nil, nil))
+		assert.True(t, testutil.AssertErrorChain(t, "", nil))
+		assert.True(t, testutil.AssertErrorChain(t, err.Error(), err, new(*fs.PathError), nil))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		numErrorf := 0
+		tt := &testTB{
+			onErrorf: func(_ string, _ ...interface{}) { numErrorf++ },
+			onHelper: func() {},
+			onName:   func() (name string) { return testName },
+		}
+
+		ok := testutil.AssertError(tt, &testutil.ErrorAssertion{
+			Is:   []error{fs.ErrNotExist},
+			As:   []any{new(*fs.PathError), new(*errors.KindError)},
+			Msg:  "other message",
+			Kind: errors.KindTimeout,
+			Code: errors.CodeTimeout,
+		}, err)
+		assert.False(t, ok)
+
+		// Four failed checks: the message, Is, Kind, and Code.
+		assert.Equal(t, 4, numErrorf)
+	})
+}
@@ -0,0 +1,65 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// BlockTimeout is the time FakeClock.BlockUntil waits for the timers before
+// failing the test.
+const BlockTimeout = 1 * time.Second
+
+// FakeClock is a timeutil.Clock with manually controlled time, which also
+// helps to coordinate tests with the goroutines that wait on its timers.  See
+// timeutil.FakeClock for the details of how the timers are fired.
+type FakeClock struct {
+	*timeutil.FakeClock
+}
+
+// type check
+var _ timeutil.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a new *FakeClock with the current time set to now.
+func NewFakeClock(now time.Time) (c *FakeClock) {
+	return &FakeClock{
+		FakeClock: timeutil.NewFakeClock(now),
+	}
+}
+
+// BlockUntil blocks until there are at least n pending timers and tickers.  It
+// fails the test using t if that doesn't happen within BlockTimeout.  It is
+// useful to make sure that the code under test has started waiting before
+// moving the time forward.
+func (c *FakeClock) BlockUntil(t testing.TB, n int) {
+	t.Helper()
+
+	require.Eventuallyf(t, func() (ok bool) {
+		return c.PendingTimers() >= n
+	}, BlockTimeout, time.Millisecond, "waiting for %d pending timers", n)
+}
+
+// AdvanceWhenBlocked waits for at least n pending timers and tickers, like
+// BlockUntil, and then advances the clock by d.  For example:
+//
+//   go worker.Run(ctx)
+//
+//   // Wait for the worker to start its refresh timer and fire it.
+//   c.AdvanceWhenBlocked(t, 1, refreshIvl)
+func (c *FakeClock) AdvanceWhenBlocked(t testing.TB, n int, d time.Duration) {
+	t.Helper()
+
+	c.BlockUntil(t, n)
+	c.Advance(d)
+}
+
+// AssertPendingTimers asserts that there are exactly n pending timers and
+// tickers.
+func (c *FakeClock) AssertPendingTimers(t testing.TB, n int) (ok bool) {
+	t.Helper()
+
+	return assert.Equalf(t, n, c.PendingTimers(), "pending timers")
+}
@@ -0,0 +1,32 @@
+package testutil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClock(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := testutil.NewFakeClock(start)
+
+	done := make(chan time.Time, 1)
+	go func() {
+		done <- <-c.After(time.Minute)
+	}()
+
+	c.AdvanceWhenBlocked(t, 1, time.Minute)
+	assert.Equal(t, start.Add(time.Minute), <-done)
+	c.AssertPendingTimers(t, 0)
+
+	ticker := c.NewTicker(time.Second)
+	c.BlockUntil(t, 1)
+	c.AssertPendingTimers(t, 1)
+
+	ticker.Stop()
+	c.AssertPendingTimers(t, 0)
+}
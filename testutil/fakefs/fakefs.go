@@ -0,0 +1,342 @@
+// Package fakefs contains a writable in-memory file system for tests.
+package fakefs
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing/fstest"
+)
+
+// Op is the type of a file system operation, for which an error can be
+// injected.  The values are the same as the ones used by package os in the
+// Op field of *fs.PathError.
+type Op string
+
+// Op values.
+const (
+	OpOpen    Op = "open"
+	OpStat    Op = "stat"
+	OpReadDir Op = "readdir"
+	OpWrite   Op = "write"
+	OpRename  Op = "rename"
+	OpRemove  Op = "remove"
+	OpMkdir   Op = "mkdir"
+)
+
+// opKey is the key of the injected errors.
+type opKey struct {
+	op   Op
+	name string
+}
+
+// FS is an in-memory file system that supports writing, renaming, and removing
+// files as well as injecting errors for particular operations and paths.  The
+// directories are created implicitly.  All methods are safe for concurrent use.
+type FS struct {
+	// mu protects files, errs, and limits.
+	mu     *sync.Mutex
+	files  fstest.MapFS
+	errs   map[opKey]error
+	limits map[string]int
+}
+
+// type check
+var (
+	_ fs.FS         = (*FS)(nil)
+	_ fs.ReadFileFS = (*FS)(nil)
+	_ fs.ReadDirFS  = (*FS)(nil)
+	_ fs.StatFS     = (*FS)(nil)
+)
+
+// New returns a new empty *FS.
+func New() (fsys *FS) {
+	return &FS{
+		mu:     &sync.Mutex{},
+		files:  fstest.MapFS{},
+		errs:   map[opKey]error{},
+		limits: map[string]int{},
+	}
+}
+
+// SetError makes all subsequent operations op on the path name fail with an
+// *fs.PathError wrapping err.  If err is nil, the injected error is removed.
+func (fsys *FS) SetError(op Op, name string, err error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	k := opKey{op: op, name: name}
+	if err == nil {
+		delete(fsys.errs, k)
+	} else {
+		fsys.errs[k] = err
+	}
+}
+
+// SetWriteLimit makes all subsequent writes to the path name only write the
+// first n bytes of data and fail with io.ErrShortWrite, simulating a partial
+// write.  If n is negative, the limit is removed.
+func (fsys *FS) SetWriteLimit(name string, n int) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if n < 0 {
+		delete(fsys.limits, name)
+	} else {
+		fsys.limits[name] = n
+	}
+}
+
+// injected returns the error injected for op on name, if any.  fsys.mu must be
+// locked.
+func (fsys *FS) injected(op Op, name string) (err error) {
+	injErr, ok := fsys.errs[opKey{op: op, name: name}]
+	if !ok {
+		return nil
+	}
+
+	return &fs.PathError{Op: string(op), Path: name, Err: injErr}
+}
+
+// Open implements the fs.FS interface for *FS.
+func (fsys *FS) Open(name string) (f fs.File, err error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if err = fsys.injected(OpOpen, name); err != nil {
+		return nil, err
+	}
+
+	return fsys.files.Open(name)
+}
+
+// ReadFile implements the fs.ReadFileFS interface for *FS.
+func (fsys *FS) ReadFile(name string) (b []byte, err error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if err = fsys.injected(OpOpen, name); err != nil {
+		return nil, err
+	}
+
+	return fsys.files.ReadFile(name)
+}
+
+// ReadDir implements the fs.ReadDirFS interface for *FS.
+func (fsys *FS) ReadDir(name string) (entries []fs.DirEntry, err error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if err = fsys.injected(OpReadDir, name); err != nil {
+		return nil, err
+	}
+
+	return fsys.files.ReadDir(name)
+}
+
+// Stat implements the fs.StatFS interface for *FS.
+func (fsys *FS) Stat(name string) (fi fs.FileInfo, err error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if err = fsys.injected(OpStat, name); err != nil {
+		return nil, err
+	}
+
+	return fsys.files.Stat(name)
+}
+
+// WriteFile writes data to the file name, creating it and its parent
+// directories if necessary.  If a write limit is set for name, only a part of
+// data is written and the error wraps io.ErrShortWrite.
+func (fsys *FS) WriteFile(name string, data []byte, perm fs.FileMode) (err error) {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: string(OpWrite), Path: name, Err: fs.ErrInvalid}
+	}
+
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if err = fsys.injected(OpWrite, name); err != nil {
+		return err
+	}
+
+	if fsys.isDir(name) {
+		return &fs.PathError{Op: string(OpWrite), Path: name, Err: fs.ErrExist}
+	} else if fsys.hasFileParent(name) {
+		return &fs.PathError{Op: string(OpWrite), Path: name, Err: fs.ErrInvalid}
+	}
+
+	if n, ok := fsys.limits[name]; ok && n < len(data) {
+		data = data[:n]
+		err = &fs.PathError{Op: string(OpWrite), Path: name, Err: io.ErrShortWrite}
+	}
+
+	// Copy the data, since the files that are already opened may still
+	// reference the previous contents.
+	fsys.files[name] = &fstest.MapFile{
+		Data: append([]byte(nil), data...),
+		Mode: perm.Perm(),
+	}
+
+	return err
+}
+
+// Rename renames the file or the directory oldName to newName, replacing the
+// file newName if it exists.  A directory can't be renamed into itself or its
+// own subdirectory.
+func (fsys *FS) Rename(oldName, newName string) (err error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if err = fsys.injected(OpRename, oldName); err != nil {
+		return err
+	}
+
+	if !fs.ValidPath(oldName) || !fs.ValidPath(newName) || oldName == "." || newName == "." {
+		err = fs.ErrInvalid
+	} else if f, ok := fsys.files[oldName]; ok && !f.Mode.IsDir() {
+		err = fsys.renameFile(f, oldName, newName)
+	} else {
+		err = fsys.renameDir(oldName, newName)
+	}
+
+	if err != nil {
+		return &os.LinkError{Op: string(OpRename), Old: oldName, New: newName, Err: err}
+	}
+
+	return nil
+}
+
+// renameFile renames the regular file f from oldName to newName.  fsys.mu must
+// be locked.
+func (fsys *FS) renameFile(f *fstest.MapFile, oldName, newName string) (err error) {
+	if fsys.isDir(newName) || fsys.hasFileParent(newName) {
+		return fs.ErrExist
+	}
+
+	delete(fsys.files, oldName)
+	fsys.files[newName] = f
+
+	return nil
+}
+
+// renameDir renames the directory oldName along with its contents to newName.
+// fsys.mu must be locked.
+func (fsys *FS) renameDir(oldName, newName string) (err error) {
+	if !fsys.isDir(oldName) {
+		return fs.ErrNotExist
+	} else if strings.HasPrefix(newName, oldName+"/") {
+		return fs.ErrInvalid
+	} else if _, ok := fsys.files[newName]; ok || fsys.isDir(newName) || fsys.hasFileParent(newName) {
+		return fs.ErrExist
+	}
+
+	renamed := map[string]*fstest.MapFile{}
+	for name, f := range fsys.files {
+		if name == oldName {
+			renamed[newName] = f
+		} else if rest, ok := strings.CutPrefix(name, oldName+"/"); ok {
+			renamed[path.Join(newName, rest)] = f
+		} else {
+			continue
+		}
+
+		delete(fsys.files, name)
+	}
+
+	for name, f := range renamed {
+		fsys.files[name] = f
+	}
+
+	return nil
+}
+
+// Remove removes the file or the empty directory name.
+func (fsys *FS) Remove(name string) (err error) {
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if err = fsys.injected(OpRemove, name); err != nil {
+		return err
+	}
+
+	if f, ok := fsys.files[name]; ok && !f.Mode.IsDir() {
+		delete(fsys.files, name)
+
+		return nil
+	}
+
+	if !fsys.isDir(name) {
+		return &fs.PathError{Op: string(OpRemove), Path: name, Err: fs.ErrNotExist}
+	}
+
+	for n := range fsys.files {
+		if strings.HasPrefix(n, name+"/") {
+			return &fs.PathError{Op: string(OpRemove), Path: name, Err: fs.ErrExist}
+		}
+	}
+
+	delete(fsys.files, name)
+
+	return nil
+}
+
+// MkdirAll creates the directory name along with any parent directories.
+func (fsys *FS) MkdirAll(name string, perm fs.FileMode) (err error) {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: string(OpMkdir), Path: name, Err: fs.ErrInvalid}
+	}
+
+	fsys.mu.Lock()
+	defer fsys.mu.Unlock()
+
+	if err = fsys.injected(OpMkdir, name); err != nil {
+		return err
+	} else if fsys.isDir(name) {
+		return nil
+	} else if _, ok := fsys.files[name]; ok || fsys.hasFileParent(name) {
+		return &fs.PathError{Op: string(OpMkdir), Path: name, Err: fs.ErrExist}
+	}
+
+	fsys.files[name] = &fstest.MapFile{
+		Mode: fs.ModeDir | perm.Perm(),
+	}
+
+	return nil
+}
+
+// isDir returns true if name is an explicit or an implicit directory.
+// fsys.mu must be locked.
+func (fsys *FS) isDir(name string) (ok bool) {
+	if name == "." {
+		return true
+	}
+
+	if f, ok := fsys.files[name]; ok {
+		return f.Mode.IsDir()
+	}
+
+	for n := range fsys.files {
+		if strings.HasPrefix(n, name+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasFileParent returns true if any of the parent directories of name is
+// actually a file.  fsys.mu must be locked.
+func (fsys *FS) hasFileParent(name string) (ok bool) {
+	for p := path.Dir(name); p != "."; p = path.Dir(p) {
+		if f, ok := fsys.files[p]; ok && !f.Mode.IsDir() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,137 @@
+package fakefs_test
+
+import (
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/AdguardTeam/golibs/testutil/fakefs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testData is the common data for tests.
+const testData = "127.0.0.1 localhost\n"
+
+func TestFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fakefs.New()
+	require.NoError(t, fsys.WriteFile("etc/hosts", []byte(testData), 0o644))
+	require.NoError(t, fsys.WriteFile("etc/hosts.d/extra", nil, 0o644))
+	require.NoError(t, fsys.MkdirAll("var/empty", 0o755))
+
+	err := fstest.TestFS(fsys, "etc/hosts", "etc/hosts.d/extra", "var/empty")
+	require.NoError(t, err)
+
+	b, err := fs.ReadFile(fsys, "etc/hosts")
+	require.NoError(t, err)
+
+	assert.Equal(t, testData, string(b))
+}
+
+func TestFS_Rename(t *testing.T) {
+	t.Parallel()
+
+	fsys := fakefs.New()
+	require.NoError(t, fsys.WriteFile("a/b/c", []byte("c"), 0o644))
+	require.NoError(t, fsys.WriteFile("a/tmp", []byte("new"), 0o644))
+	require.NoError(t, fsys.WriteFile("a/file", []byte("old"), 0o644))
+
+	require.NoError(t, fsys.Rename("a/tmp", "a/file"))
+
+	b, err := fsys.ReadFile("a/file")
+	require.NoError(t, err)
+
+	assert.Equal(t, "new", string(b))
+
+	require.NoError(t, fsys.Rename("a/b", "d"))
+
+	_, err = fsys.Stat("a/b/c")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	b, err = fsys.ReadFile("d/c")
+	require.NoError(t, err)
+
+	assert.Equal(t, "c", string(b))
+
+	err = fsys.Rename("nonexistent", "x")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	err = fsys.Rename("a/file", "d")
+	assert.ErrorIs(t, err, fs.ErrExist)
+
+	err = fsys.Rename("d", "d/e")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+
+	b, err = fsys.ReadFile("d/c")
+	require.NoError(t, err)
+
+	assert.Equal(t, "c", string(b))
+}
+
+func TestFS_Remove(t *testing.T) {
+	t.Parallel()
+
+	fsys := fakefs.New()
+	require.NoError(t, fsys.WriteFile("dir/file", []byte(testData), 0o644))
+
+	err := fsys.Remove("dir")
+	assert.ErrorIs(t, err, fs.ErrExist)
+
+	require.NoError(t, fsys.Remove("dir/file"))
+
+	err = fsys.Remove("dir/file")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+
+	require.NoError(t, fsys.MkdirAll("dir", 0o755))
+	require.NoError(t, fsys.Remove("dir"))
+
+	_, err = fsys.Stat("dir")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestFS_SetError(t *testing.T) {
+	t.Parallel()
+
+	fsys := fakefs.New()
+	require.NoError(t, fsys.WriteFile("etc/hosts", []byte(testData), 0o644))
+
+	fsys.SetError(fakefs.OpOpen, "etc/hosts", fs.ErrPermission)
+
+	_, err := fs.ReadFile(fsys, "etc/hosts")
+	assert.ErrorIs(t, err, fs.ErrPermission)
+	testutil.AssertErrorMsg(t, "open etc/hosts: permission denied", err)
+
+	fsys.SetError(fakefs.OpOpen, "etc/hosts", nil)
+
+	_, err = fs.ReadFile(fsys, "etc/hosts")
+	require.NoError(t, err)
+
+	const testErr errors.Error = "test error"
+	fsys.SetError(fakefs.OpRename, "etc/hosts", testErr)
+
+	err = fsys.Rename("etc/hosts", "etc/hosts.bak")
+	assert.ErrorIs(t, err, testErr)
+}
+
+func TestFS_SetWriteLimit(t *testing.T) {
+	t.Parallel()
+
+	fsys := fakefs.New()
+	fsys.SetWriteLimit("config.yaml", 4)
+
+	err := fsys.WriteFile("config.yaml", []byte(testData), 0o644)
+	assert.ErrorIs(t, err, io.ErrShortWrite)
+
+	b, err := fsys.ReadFile("config.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, testData[:4], string(b))
+
+	fsys.SetWriteLimit("config.yaml", -1)
+	require.NoError(t, fsys.WriteFile("config.yaml", []byte(testData), 0o644))
+}
@@ -0,0 +1,240 @@
+package fakenet
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Conn is a fake net.Conn.  A Conn must not be copied after first use.
+type Conn struct {
+	recorder
+
+	OnRead             func(b []byte) (n int, err error)
+	OnWrite            func(b []byte) (n int, err error)
+	OnClose            func() (err error)
+	OnLocalAddr        func() (laddr net.Addr)
+	OnRemoteAddr       func() (raddr net.Addr)
+	OnSetDeadline      func(t time.Time) (err error)
+	OnSetReadDeadline  func(t time.Time) (err error)
+	OnSetWriteDeadline func(t time.Time) (err error)
+}
+
+// type check
+var _ net.Conn = (*Conn)(nil)
+
+// Read implements the net.Conn interface for *Conn.
+func (c *Conn) Read(b []byte) (n int, err error) {
+	c.record("Read", len(b))
+	if c.OnRead == nil {
+		notImplemented("Conn", "Read")
+	}
+
+	return c.OnRead(b)
+}
+
+// Write implements the net.Conn interface for *Conn.
+func (c *Conn) Write(b []byte) (n int, err error) {
+	c.record("Write", b)
+	if c.OnWrite == nil {
+		notImplemented("Conn", "Write")
+	}
+
+	return c.OnWrite(b)
+}
+
+// Close implements the net.Conn interface for *Conn.
+func (c *Conn) Close() (err error) {
+	c.record("Close")
+	if c.OnClose == nil {
+		notImplemented("Conn", "Close")
+	}
+
+	return c.OnClose()
+}
+
+// LocalAddr implements the net.Conn interface for *Conn.
+func (c *Conn) LocalAddr() (laddr net.Addr) {
+	c.record("LocalAddr")
+	if c.OnLocalAddr == nil {
+		notImplemented("Conn", "LocalAddr")
+	}
+
+	return c.OnLocalAddr()
+}
+
+// RemoteAddr implements the net.Conn interface for *Conn.
+func (c *Conn) RemoteAddr() (raddr net.Addr) {
+	c.record("RemoteAddr")
+	if c.OnRemoteAddr == nil {
+		notImplemented("Conn", "RemoteAddr")
+	}
+
+	return c.OnRemoteAddr()
+}
+
+// SetDeadline implements the net.Conn interface for *Conn.
+func (c *Conn) SetDeadline(t time.Time) (err error) {
+	c.record("SetDeadline", t)
+	if c.OnSetDeadline == nil {
+		notImplemented("Conn", "SetDeadline")
+	}
+
+	return c.OnSetDeadline(t)
+}
+
+// SetReadDeadline implements the net.Conn interface for *Conn.
+func (c *Conn) SetReadDeadline(t time.Time) (err error) {
+	c.record("SetReadDeadline", t)
+	if c.OnSetReadDeadline == nil {
+		notImplemented("Conn", "SetReadDeadline")
+	}
+
+	return c.OnSetReadDeadline(t)
+}
+
+// SetWriteDeadline implements the net.Conn interface for *Conn.
+func (c *Conn) SetWriteDeadline(t time.Time) (err error) {
+	c.record("SetWriteDeadline", t)
+	if c.OnSetWriteDeadline == nil {
+		notImplemented("Conn", "SetWriteDeadline")
+	}
+
+	return c.OnSetWriteDeadline(t)
+}
+
+// Written returns the concatenation of the data from all recorded Write calls,
+// including the failed ones.
+func (c *Conn) Written() (b []byte) {
+	for _, call := range c.Calls() {
+		if call.Method == "Write" {
+			b = append(b, call.Args[0].([]byte)...)
+		}
+	}
+
+	return b
+}
+
+// NewScriptedConn returns a *Conn that returns the data from reads, one
+// element per Read call, followed by io.EOF.  If an element is longer than the
+// buffer, the rest of it is returned by the next call.  Writes and the first
+// Close succeed; reads and writes after Close return net.ErrClosed.  Reads and
+// writes made when the corresponding deadline has already passed return
+// os.ErrDeadlineExceeded.  The addresses are local and remote addr.  Any of the
+// function fields may be replaced after the creation.
+func NewScriptedConn(laddr, raddr net.Addr, reads ...[]byte) (c *Conn) {
+	sc := &scriptedConn{
+		mu:    &sync.Mutex{},
+		reads: reads,
+	}
+
+	return &Conn{
+		OnRead:             sc.read,
+		OnWrite:            sc.write,
+		OnClose:            sc.close,
+		OnLocalAddr:        func() (a net.Addr) { return laddr },
+		OnRemoteAddr:       func() (a net.Addr) { return raddr },
+		OnSetDeadline:      sc.setDeadline,
+		OnSetReadDeadline:  sc.setReadDeadline,
+		OnSetWriteDeadline: sc.setWriteDeadline,
+	}
+}
+
+// scriptedConn is the state of a *Conn returned by NewScriptedConn.
+type scriptedConn struct {
+	// mu protects all fields below.
+	mu            *sync.Mutex
+	cur           *bytes.Reader
+	readDeadline  time.Time
+	writeDeadline time.Time
+	reads         [][]byte
+	closed        bool
+}
+
+// read is the OnRead function of the connection.
+func (sc *scriptedConn) read(b []byte) (n int, err error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.closed {
+		return 0, net.ErrClosed
+	} else if isExpired(sc.readDeadline) {
+		return 0, os.ErrDeadlineExceeded
+	}
+
+	for sc.cur == nil || sc.cur.Len() == 0 {
+		if len(sc.reads) == 0 {
+			return 0, io.EOF
+		}
+
+		sc.cur, sc.reads = bytes.NewReader(sc.reads[0]), sc.reads[1:]
+	}
+
+	return sc.cur.Read(b)
+}
+
+// write is the OnWrite function of the connection.
+func (sc *scriptedConn) write(b []byte) (n int, err error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.closed {
+		return 0, net.ErrClosed
+	} else if isExpired(sc.writeDeadline) {
+		return 0, os.ErrDeadlineExceeded
+	}
+
+	return len(b), nil
+}
+
+// close is the OnClose function of the connection.
+func (sc *scriptedConn) close() (err error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.closed {
+		return net.ErrClosed
+	}
+
+	sc.closed = true
+
+	return nil
+}
+
+// setDeadline is the OnSetDeadline function of the connection.
+func (sc *scriptedConn) setDeadline(t time.Time) (err error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.readDeadline, sc.writeDeadline = t, t
+
+	return nil
+}
+
+// setReadDeadline is the OnSetReadDeadline function of the connection.
+func (sc *scriptedConn) setReadDeadline(t time.Time) (err error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.readDeadline = t
+
+	return nil
+}
+
+// setWriteDeadline is the OnSetWriteDeadline function of the connection.
+func (sc *scriptedConn) setWriteDeadline(t time.Time) (err error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	sc.writeDeadline = t
+
+	return nil
+}
+
+// isExpired returns true if the deadline is set and has passed.
+func isExpired(deadline time.Time) (ok bool) {
+	return !deadline.IsZero() && !time.Now().Before(deadline)
+}
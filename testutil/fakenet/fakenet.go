@@ -0,0 +1,61 @@
+// Package fakenet contains fake implementations of the network interfaces for
+// tests.
+//
+// The fakes call the corresponding On* function fields and record all calls.
+// A method the function field of which is nil panics, which makes unexpected
+// calls easy to spot.  Like the types in package sync, the fakes must not be
+// copied after first use.
+package fakenet
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Call is a recorded method call.
+type Call struct {
+	// Method is the name of the method, for example "Read".
+	Method string
+
+	// Args are the arguments of the call.  Byte slices are copied, so they
+	// reflect the data at the time of the call.
+	Args []any
+}
+
+// recorder records method calls.  It is safe for concurrent use.  Its zero
+// value is ready for use, so that the fakes can be created with composite
+// literals, which is why mu isn't a pointer.  A recorder must not be copied
+// after first use.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// record records the call of method with args.
+func (r *recorder) record(method string, args ...any) {
+	for i, a := range args {
+		if b, ok := a.([]byte); ok {
+			args[i] = append([]byte(nil), b...)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns a copy of all recorded calls in the order in which they were
+// made.
+func (r *recorder) Calls() (calls []Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Call(nil), r.calls...)
+}
+
+// notImplemented panics with a message about an unexpected call of method of
+// the fake typ.
+func notImplemented(typ, method string) {
+	panic(fmt.Errorf("fakenet: unexpected call to %s.%s: On%[2]s is nil", typ, method))
+}
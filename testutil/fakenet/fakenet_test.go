@@ -0,0 +1,171 @@
+package fakenet_test
+
+import (
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/testutil/fakenet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Common addresses for tests.
+var (
+	testLocalAddr  = net.UDPAddrFromAddrPort(netip.MustParseAddrPort("127.0.0.1:53"))
+	testRemoteAddr = net.UDPAddrFromAddrPort(netip.MustParseAddrPort("127.0.0.2:1234"))
+)
+
+func TestNewScriptedConn(t *testing.T) {
+	t.Parallel()
+
+	c := fakenet.NewScriptedConn(testLocalAddr, testRemoteAddr, []byte("hello"), []byte("!"))
+
+	buf := make([]byte, 3)
+	n, err := c.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "hel", string(buf[:n]))
+
+	n, err = c.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "lo", string(buf[:n]))
+
+	n, err = c.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "!", string(buf[:n]))
+
+	_, err = c.Read(buf)
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = c.Write([]byte("abc"))
+	require.NoError(t, err)
+
+	_, err = c.Write([]byte("def"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "abcdef", string(c.Written()))
+	assert.Equal(t, testRemoteAddr, c.RemoteAddr())
+
+	require.NoError(t, c.Close())
+
+	_, err = c.Write([]byte("ghi"))
+	assert.ErrorIs(t, err, net.ErrClosed)
+
+	assert.ErrorIs(t, c.Close(), net.ErrClosed)
+}
+
+func TestNewScriptedConn_deadline(t *testing.T) {
+	t.Parallel()
+
+	c := fakenet.NewScriptedConn(testLocalAddr, testRemoteAddr, []byte("hello"))
+
+	past := time.Now().Add(-time.Second)
+	require.NoError(t, c.SetReadDeadline(past))
+
+	buf := make([]byte, 5)
+	_, err := c.Read(buf)
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	// Writes are still allowed.
+	_, err = c.Write([]byte("abc"))
+	require.NoError(t, err)
+
+	require.NoError(t, c.SetDeadline(past))
+
+	_, err = c.Write([]byte("def"))
+	assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	var netErr net.Error
+	require.ErrorAs(t, err, &netErr)
+
+	assert.True(t, netErr.Timeout())
+
+	// Resetting the deadline allows reading the data that hasn't been read.
+	require.NoError(t, c.SetDeadline(time.Time{}))
+
+	n, err := c.Read(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello", string(buf[:n]))
+}
+
+func TestConn(t *testing.T) {
+	t.Parallel()
+
+	deadline := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &fakenet.Conn{
+		OnSetReadDeadline: func(_ time.Time) (err error) {
+			return net.ErrClosed
+		},
+	}
+
+	err := c.SetReadDeadline(deadline)
+	assert.ErrorIs(t, err, net.ErrClosed)
+
+	assert.Panics(t, func() { _ = c.Close() })
+
+	assert.Equal(t, []fakenet.Call{{
+		Method: "SetReadDeadline",
+		Args:   []any{deadline},
+	}, {
+		Method: "Close",
+		Args:   nil,
+	}}, c.Calls())
+}
+
+func TestPacketConn(t *testing.T) {
+	t.Parallel()
+
+	c := &fakenet.PacketConn{
+		OnReadFrom: func(b []byte) (n int, addr net.Addr, err error) {
+			return copy(b, "query"), testRemoteAddr, nil
+		},
+		OnWriteTo: func(b []byte, _ net.Addr) (n int, err error) {
+			return len(b), nil
+		},
+	}
+
+	buf := make([]byte, 16)
+	n, addr, err := c.ReadFrom(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "query", string(buf[:n]))
+	assert.Equal(t, testRemoteAddr, addr)
+
+	data := []byte("resp")
+	_, err = c.WriteTo(data, addr)
+	require.NoError(t, err)
+
+	// Make sure that the recorded data isn't affected by the changes.
+	data[0] = 'X'
+
+	calls := c.Calls()
+	require.Len(t, calls, 2)
+
+	assert.Equal(t, []any{[]byte("resp"), net.Addr(testRemoteAddr)}, calls[1].Args)
+}
+
+func TestNewListener(t *testing.T) {
+	t.Parallel()
+
+	conn := fakenet.NewScriptedConn(testLocalAddr, testRemoteAddr)
+	l := fakenet.NewListener(testLocalAddr, conn)
+
+	c, err := l.Accept()
+	require.NoError(t, err)
+
+	assert.Same(t, conn, c)
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, acceptErr := l.Accept()
+		errCh <- acceptErr
+	}()
+
+	require.NoError(t, l.Close())
+	assert.ErrorIs(t, <-errCh, net.ErrClosed)
+	assert.ErrorIs(t, l.Close(), net.ErrClosed)
+}
@@ -0,0 +1,87 @@
+package fakenet
+
+import (
+	"net"
+	"sync"
+)
+
+// Listener is a fake net.Listener.  A Listener must not be copied after first use.
+type Listener struct {
+	recorder
+
+	OnAccept func() (c net.Conn, err error)
+	OnAddr   func() (addr net.Addr)
+	OnClose  func() (err error)
+}
+
+// type check
+var _ net.Listener = (*Listener)(nil)
+
+// Accept implements the net.Listener interface for *Listener.
+func (l *Listener) Accept() (c net.Conn, err error) {
+	l.record("Accept")
+	if l.OnAccept == nil {
+		notImplemented("Listener", "Accept")
+	}
+
+	return l.OnAccept()
+}
+
+// Addr implements the net.Listener interface for *Listener.
+func (l *Listener) Addr() (addr net.Addr) {
+	l.record("Addr")
+	if l.OnAddr == nil {
+		notImplemented("Listener", "Addr")
+	}
+
+	return l.OnAddr()
+}
+
+// Close implements the net.Listener interface for *Listener.
+func (l *Listener) Close() (err error) {
+	l.record("Close")
+	if l.OnClose == nil {
+		notImplemented("Listener", "Close")
+	}
+
+	return l.OnClose()
+}
+
+// NewListener returns a *Listener that returns conns from Accept one by one and
+// then blocks until it is closed, after which Accept returns net.ErrClosed.
+func NewListener(addr net.Addr, conns ...net.Conn) (l *Listener) {
+	ch := make(chan net.Conn, len(conns))
+	for _, c := range conns {
+		ch <- c
+	}
+
+	done := make(chan struct{})
+	closeOnce := &sync.Once{}
+
+	return &Listener{
+		OnAccept: func() (c net.Conn, err error) {
+			select {
+			case <-done:
+				return nil, net.ErrClosed
+			default:
+			}
+
+			select {
+			case c = <-ch:
+				return c, nil
+			case <-done:
+				return nil, net.ErrClosed
+			}
+		},
+		OnAddr: func() (a net.Addr) { return addr },
+		OnClose: func() (err error) {
+			err = net.ErrClosed
+			closeOnce.Do(func() {
+				close(done)
+				err = nil
+			})
+
+			return err
+		},
+	}
+}
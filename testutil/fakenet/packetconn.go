@@ -0,0 +1,92 @@
+package fakenet
+
+import (
+	"net"
+	"time"
+)
+
+// PacketConn is a fake net.PacketConn.  A PacketConn must not be copied after first use.
+type PacketConn struct {
+	recorder
+
+	OnReadFrom         func(b []byte) (n int, addr net.Addr, err error)
+	OnWriteTo          func(b []byte, addr net.Addr) (n int, err error)
+	OnClose            func() (err error)
+	OnLocalAddr        func() (laddr net.Addr)
+	OnSetDeadline      func(t time.Time) (err error)
+	OnSetReadDeadline  func(t time.Time) (err error)
+	OnSetWriteDeadline func(t time.Time) (err error)
+}
+
+// type check
+var _ net.PacketConn = (*PacketConn)(nil)
+
+// ReadFrom implements the net.PacketConn interface for *PacketConn.
+func (c *PacketConn) ReadFrom(b []byte) (n int, addr net.Addr, err error) {
+	c.record("ReadFrom", len(b))
+	if c.OnReadFrom == nil {
+		notImplemented("PacketConn", "ReadFrom")
+	}
+
+	return c.OnReadFrom(b)
+}
+
+// WriteTo implements the net.PacketConn interface for *PacketConn.
+func (c *PacketConn) WriteTo(b []byte, addr net.Addr) (n int, err error) {
+	c.record("WriteTo", b, addr)
+	if c.OnWriteTo == nil {
+		notImplemented("PacketConn", "WriteTo")
+	}
+
+	return c.OnWriteTo(b, addr)
+}
+
+// Close implements the net.PacketConn interface for *PacketConn.
+func (c *PacketConn) Close() (err error) {
+	c.record("Close")
+	if c.OnClose == nil {
+		notImplemented("PacketConn", "Close")
+	}
+
+	return c.OnClose()
+}
+
+// LocalAddr implements the net.PacketConn interface for *PacketConn.
+func (c *PacketConn) LocalAddr() (laddr net.Addr) {
+	c.record("LocalAddr")
+	if c.OnLocalAddr == nil {
+		notImplemented("PacketConn", "LocalAddr")
+	}
+
+	return c.OnLocalAddr()
+}
+
+// SetDeadline implements the net.PacketConn interface for *PacketConn.
+func (c *PacketConn) SetDeadline(t time.Time) (err error) {
+	c.record("SetDeadline", t)
+	if c.OnSetDeadline == nil {
+		notImplemented("PacketConn", "SetDeadline")
+	}
+
+	return c.OnSetDeadline(t)
+}
+
+// SetReadDeadline implements the net.PacketConn interface for *PacketConn.
+func (c *PacketConn) SetReadDeadline(t time.Time) (err error) {
+	c.record("SetReadDeadline", t)
+	if c.OnSetReadDeadline == nil {
+		notImplemented("PacketConn", "SetReadDeadline")
+	}
+
+	return c.OnSetReadDeadline(t)
+}
+
+// SetWriteDeadline implements the net.PacketConn interface for *PacketConn.
+func (c *PacketConn) SetWriteDeadline(t time.Time) (err error) {
+	c.record("SetWriteDeadline", t)
+	if c.OnSetWriteDeadline == nil {
+		notImplemented("PacketConn", "SetWriteDeadline")
+	}
+
+	return c.OnSetWriteDeadline(t)
+}
@@ -0,0 +1,122 @@
+package testutil
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// UpdateGoldenFlag is the name of the flag that makes AssertGolden write the
+// golden files instead of comparing against them.  It is prefixed to avoid
+// conflicts with the flags defined by the tests themselves.  Use it like this:
+//
+//   go test ./... -golden.update
+const UpdateGoldenFlag = "golden.update"
+
+// updateGolden is the value of the UpdateGoldenFlag flag.
+var updateGolden = flag.Bool(UpdateGoldenFlag, false, "update the golden files in testdata")
+
+// GoldenDir is the directory where the golden files are stored, relative to
+// the directory of the package under test.
+const GoldenDir = "testdata"
+
+// GoldenNormalizer transforms the data before it is compared with or written
+// to a golden file.  It is used to remove the parts of the output which change
+// from run to run, such as timestamps.
+type GoldenNormalizer func(b []byte) (res []byte)
+
+// NormalizeRegexp returns a GoldenNormalizer that replaces all matches of re
+// with repl, which may contain the references to the submatches, as in
+// regexp.Regexp.ReplaceAll.
+func NormalizeRegexp(re *regexp.Regexp, repl string) (n GoldenNormalizer) {
+	return func(b []byte) (res []byte) {
+		return re.ReplaceAll(b, []byte(repl))
+	}
+}
+
+// timestampRe matches RFC 3339 timestamps with optional fractional seconds.
+var timestampRe = regexp.MustCompile(
+	`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?`,
+)
+
+// NormalizeTimestamps is a GoldenNormalizer that replaces all RFC 3339
+// timestamps with the string "<timestamp>".
+func NormalizeTimestamps(b []byte) (res []byte) {
+	return timestampRe.ReplaceAll(b, []byte("<timestamp>"))
+}
+
+// NormalizeLineOrder is a GoldenNormalizer that sorts the lines of the data.
+// It is useful for the output with no defined order, such as the one produced
+// by iterating over a map.
+func NormalizeLineOrder(b []byte) (res []byte) {
+	hasNL := bytes.HasSuffix(b, []byte("\n"))
+	lines := bytes.Split(bytes.TrimSuffix(b, []byte("\n")), []byte("\n"))
+	sort.Slice(lines, func(i, j int) (less bool) {
+		return bytes.Compare(lines[i], lines[j]) < 0
+	})
+
+	res = bytes.Join(lines, []byte("\n"))
+	if hasNL {
+		res = append(res, '\n')
+	}
+
+	return res
+}
+
+// NormalizeLineEndings is a GoldenNormalizer that replaces CRLF line endings
+// with LF ones.
+func NormalizeLineEndings(b []byte) (res []byte) {
+	return bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))
+}
+
+// AssertGolden asserts that got, transformed by the normalizers, is equal to
+// the contents of the golden file GoldenDir/name.golden.  If the test is run
+// with the -golden.update flag, it writes the normalized data into the file instead.
+// The golden file is normalized as well before comparison, so that the files
+// written on different systems are still accepted.
+func AssertGolden(t testing.TB, name string, got []byte, normalizers ...GoldenNormalizer) (ok bool) {
+	t.Helper()
+
+	for _, n := range normalizers {
+		got = n(got)
+	}
+
+	fileName := filepath.Join(GoldenDir, name+".golden")
+	if *updateGolden {
+		err := os.MkdirAll(filepath.Dir(fileName), 0o755)
+		require.NoError(t, err)
+
+		err = os.WriteFile(fileName, got, 0o644)
+		require.NoError(t, err)
+
+		return true
+	}
+
+	want, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Errorf("golden file %q does not exist; run the test with -golden.update to create it", fileName)
+
+		return false
+	}
+	require.NoError(t, err)
+
+	for _, n := range normalizers {
+		want = n(want)
+	}
+
+	return assert.Equalf(
+		t,
+		string(want),
+		string(got),
+		"output differs from golden file %q; run the test with -golden.update to update it",
+		fileName,
+	)
+}
@@ -0,0 +1,55 @@
+package testutil_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssertGolden(t *testing.T) {
+	t.Parallel()
+
+	got := []byte("b=2 time=2023-01-01T00:00:01Z\r\na=1 time=2023-01-01T00:00:00.123+03:00\r\n")
+	testutil.AssertGolden(
+		t,
+		"golden",
+		got,
+		testutil.NormalizeLineEndings,
+		testutil.NormalizeTimestamps,
+		testutil.NormalizeLineOrder,
+	)
+
+	t.Run("mismatch", func(t *testing.T) {
+		var errorfCalled bool
+		tt := &testTB{
+			onErrorf: func(_ string, _ ...interface{}) { errorfCalled = true },
+			onHelper: func() {},
+			onName:   func() (name string) { return testName },
+		}
+
+		ok := testutil.AssertGolden(tt, "golden", []byte("a=1\n"))
+		assert.False(t, ok)
+		assert.True(t, errorfCalled)
+	})
+
+	t.Run("not_exist", func(t *testing.T) {
+		var gotFormat string
+		tt := &testTB{
+			onErrorf: func(format string, _ ...interface{}) { gotFormat = format },
+			onHelper: func() {},
+		}
+
+		ok := testutil.AssertGolden(tt, "nonexistent", nil)
+		assert.False(t, ok)
+		assert.Contains(t, gotFormat, "-"+testutil.UpdateGoldenFlag)
+	})
+}
+
+func TestNormalizeRegexp(t *testing.T) {
+	t.Parallel()
+
+	n := testutil.NormalizeRegexp(regexp.MustCompile(`id=(\d+)`), "id=<$1>")
+	assert.Equal(t, "a id=<12> b", string(n([]byte("a id=12 b"))))
+}
@@ -0,0 +1,176 @@
+package timeutil
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// Jitter is the type of randomization applied to the delays of a *Backoff.
+type Jitter uint8
+
+// Jitter values.
+const (
+	// JitterNone means that the delays are not randomized.
+	JitterNone Jitter = iota
+
+	// JitterFull means that the delays are chosen uniformly from the range
+	// from zero to the computed delay.
+	JitterFull
+
+	// JitterEqual means that the delays are chosen uniformly from the range
+	// from a half of the computed delay to the computed delay.
+	JitterEqual
+)
+
+// BackoffConfig is the configuration for a *Backoff.
+type BackoffConfig struct {
+	// Clock is used by Retry to wait between attempts.  If nil, SystemClock is
+	// used.
+	Clock Clock
+
+	// Rand returns a pseudo-random number in the half-open interval [0.0, 1.0)
+	// and is used to compute jitter.  If nil, math/rand.Float64 is used.
+	Rand func() (f float64)
+
+	// Initial is the delay before the first retry.  It must be positive.
+	Initial time.Duration
+
+	// Max is the maximum delay.  If zero, the delay is not limited.
+	Max time.Duration
+
+	// Multiplier is the factor by which the delay grows after every attempt.
+	// If zero, 2 is used.  It must not be less than 1.
+	Multiplier float64
+
+	// MaxAttempts is the maximum number of attempts Retry makes, including the
+	// first one.  If zero, the number of attempts is not limited.
+	MaxAttempts int
+
+	// Jitter is the type of randomization of the delays.
+	Jitter Jitter
+}
+
+// Backoff computes exponentially growing delays between attempts.  It is not
+// safe for concurrent use.
+type Backoff struct {
+	clock       Clock
+	rand        func() (f float64)
+	initial     time.Duration
+	max         time.Duration
+	multiplier  float64
+	maxAttempts int
+	jitter      Jitter
+
+	// attempt is the number of delays returned since the last reset.
+	attempt int
+}
+
+// NewBackoff returns a new properly initialized *Backoff.  c must not be nil.
+func NewBackoff(c *BackoffConfig) (b *Backoff) {
+	b = &Backoff{
+		clock:       c.Clock,
+		rand:        c.Rand,
+		initial:     c.Initial,
+		max:         c.Max,
+		multiplier:  c.Multiplier,
+		maxAttempts: c.MaxAttempts,
+		jitter:      c.Jitter,
+	}
+
+	if b.clock == nil {
+		b.clock = SystemClock{}
+	}
+
+	if b.rand == nil {
+		b.rand = rand.Float64
+	}
+
+	if b.multiplier == 0 {
+		b.multiplier = 2
+	}
+
+	return b
+}
+
+// Next returns the next delay and advances the backoff.
+func (b *Backoff) Next() (d time.Duration) {
+	f := float64(b.initial) * math.Pow(b.multiplier, float64(b.attempt))
+	b.attempt++
+
+	if b.max > 0 && f > float64(b.max) {
+		f = float64(b.max)
+	}
+
+	switch b.jitter {
+	case JitterFull:
+		f *= b.rand()
+	case JitterEqual:
+		f = f/2 + f/2*b.rand()
+	default:
+		// Go on.
+	}
+
+	// float64(math.MaxInt64) is actually 2^63, which overflows the conversion,
+	// so compare inclusively.
+	if f >= float64(math.MaxInt64) {
+		return time.Duration(math.MaxInt64)
+	}
+
+	return time.Duration(f)
+}
+
+// Attempt returns the number of delays returned by Next since the last reset.
+func (b *Backoff) Attempt() (n int) {
+	return b.attempt
+}
+
+// Reset resets the backoff to the initial delay.
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
+
+// ErrRetriesExhausted is returned by Retry, wrapped together with the last
+// error of f, when the maximum number of attempts has been reached.
+const ErrRetriesExhausted errors.Error = "retries exhausted"
+
+// Retry resets b and calls f until it succeeds, the maximum number of attempts
+// of b is reached, or ctx is done, waiting for the delays computed by b between
+// the attempts.  Errors marked as permanent with errors.MarkPermanent are not
+// retried and are returned as is.  If the attempts are exhausted, the returned
+// error wraps both ErrRetriesExhausted and the last error of f.  If ctx is
+// done, the returned error wraps both the context error and the last error of
+// f.
+func Retry(ctx context.Context, b *Backoff, f func(ctx context.Context) (err error)) (err error) {
+	b.Reset()
+
+	for attempt := 1; ; attempt++ {
+		err = f(ctx)
+		if err == nil {
+			return nil
+		}
+
+		var r errors.Retryable
+		if errors.As(err, &r) && !r.Retryable() {
+			return err
+		}
+
+		if b.maxAttempts > 0 && attempt >= b.maxAttempts {
+			return fmt.Errorf("%w after %d attempts: %w", ErrRetriesExhausted, attempt, err)
+		}
+
+		timer := b.clock.NewTimer(b.Next())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return fmt.Errorf("waiting for attempt %d: %w: %w", attempt+1, ctx.Err(), err)
+		case <-timer.C():
+			// Go on.
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package timeutil_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff_Next(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		want   []time.Duration
+		jitter timeutil.Jitter
+	}{{
+		name:   "none",
+		want:   []time.Duration{1, 2, 4, 8, 10, 10},
+		jitter: timeutil.JitterNone,
+	}, {
+		name:   "full",
+		want:   []time.Duration{0, 1, 2, 4, 5, 5},
+		jitter: timeutil.JitterFull,
+	}, {
+		name:   "equal",
+		want:   []time.Duration{0, 1, 3, 6, 7, 7},
+		jitter: timeutil.JitterEqual,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			b := timeutil.NewBackoff(&timeutil.BackoffConfig{
+				Rand:    func() (f float64) { return 0.5 },
+				Initial: time.Second,
+				Max:     10 * time.Second,
+				Jitter:  tc.jitter,
+			})
+
+			got := make([]time.Duration, 0, len(tc.want))
+			for range tc.want {
+				got = append(got, b.Next()/time.Second)
+			}
+
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, len(tc.want), b.Attempt())
+
+			b.Reset()
+			assert.Equal(t, 0, b.Attempt())
+		})
+	}
+}
+
+func TestBackoff_Next_unlimited(t *testing.T) {
+	t.Parallel()
+
+	for _, j := range []timeutil.Jitter{
+		timeutil.JitterNone,
+		timeutil.JitterFull,
+		timeutil.JitterEqual,
+	} {
+		b := timeutil.NewBackoff(&timeutil.BackoffConfig{
+			Rand:    func() (f float64) { return 0.9999999999999999 },
+			Initial: time.Second,
+			Jitter:  j,
+		})
+
+		prev := time.Duration(0)
+		for i := 0; i < 2000; i++ {
+			d := b.Next()
+			require.GreaterOrEqualf(t, d, prev, "jitter %d, attempt %d", j, i)
+
+			prev = d
+		}
+
+		assert.Greater(t, prev, time.Duration(math.MaxInt64/2))
+	}
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	const testErr errors.Error = "test error"
+
+	clock := testutil.NewFakeClock(testStart)
+	newBackoff := func(maxAttempts int) (b *timeutil.Backoff) {
+		return timeutil.NewBackoff(&timeutil.BackoffConfig{
+			Clock:       clock,
+			Initial:     time.Second,
+			MaxAttempts: maxAttempts,
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		calls := 0
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- timeutil.Retry(context.Background(), newBackoff(0), func(_ context.Context) (err error) {
+				calls++
+				if calls < 3 {
+					return testErr
+				}
+
+				return nil
+			})
+		}()
+
+		clock.AdvanceWhenBlocked(t, 1, time.Second)
+		clock.AdvanceWhenBlocked(t, 1, 2*time.Second)
+
+		require.NoError(t, <-errCh)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- timeutil.Retry(context.Background(), newBackoff(2), func(_ context.Context) (err error) {
+				return testErr
+			})
+		}()
+
+		clock.AdvanceWhenBlocked(t, 1, time.Second)
+
+		err := <-errCh
+		testutil.AssertErrorMsg(t, "retries exhausted after 2 attempts: test error", err)
+		assert.ErrorIs(t, err, timeutil.ErrRetriesExhausted)
+		assert.ErrorIs(t, err, testErr)
+	})
+
+	t.Run("permanent", func(t *testing.T) {
+		calls := 0
+		err := timeutil.Retry(context.Background(), newBackoff(0), func(_ context.Context) (err error) {
+			calls++
+
+			return errors.MarkPermanent(testErr)
+		})
+
+		assert.ErrorIs(t, err, testErr)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- timeutil.Retry(ctx, newBackoff(0), func(_ context.Context) (err error) {
+				return testErr
+			})
+		}()
+
+		clock.BlockUntil(t, 1)
+
+		cancel()
+
+		err := <-errCh
+		testutil.AssertErrorMsg(t, "waiting for attempt 2: context canceled: test error", err)
+		assert.ErrorIs(t, err, context.Canceled)
+		clock.AssertPendingTimers(t, 0)
+	})
+}
@@ -0,0 +1,104 @@
+package timeutil
+
+import (
+	"time"
+)
+
+// Clock is the interface for the source of the current time as well as timers
+// and tickers.  It allows replacing the real time with a controllable one in
+// tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() (now time.Time)
+
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) (c <-chan time.Time)
+
+	// NewTimer creates a new Timer that sends the current time on its channel
+	// after at least duration d.
+	NewTimer(d time.Duration) (t Timer)
+
+	// NewTicker returns a new Ticker that sends the current time on its
+	// channel with the period d.  d must be greater than zero.
+	NewTicker(d time.Duration) (t Ticker)
+}
+
+// Timer is the interface for timers created by a Clock.  See time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() (c <-chan time.Time)
+
+	// Stop prevents the timer from firing.  It returns true if the call stops
+	// the timer and false if the timer has already expired or been stopped.
+	Stop() (ok bool)
+
+	// Reset changes the timer to expire after duration d.  It returns true if
+	// the timer had been active.
+	Reset(d time.Duration) (ok bool)
+}
+
+// Ticker is the interface for tickers created by a Clock.  See time.Ticker.
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() (c <-chan time.Time)
+
+	// Stop turns off the ticker.
+	Stop()
+
+	// Reset stops the ticker and resets its period to d.  d must be greater
+	// than zero.
+	Reset(d time.Duration)
+}
+
+// SystemClock is a Clock that uses the real time from package time.
+type SystemClock struct{}
+
+// type check
+var _ Clock = SystemClock{}
+
+// Now implements the Clock interface for SystemClock.
+func (SystemClock) Now() (now time.Time) {
+	return time.Now()
+}
+
+// After implements the Clock interface for SystemClock.
+func (SystemClock) After(d time.Duration) (c <-chan time.Time) {
+	return time.After(d)
+}
+
+// NewTimer implements the Clock interface for SystemClock.
+func (SystemClock) NewTimer(d time.Duration) (t Timer) {
+	return systemTimer{Timer: time.NewTimer(d)}
+}
+
+// NewTicker implements the Clock interface for SystemClock.
+func (SystemClock) NewTicker(d time.Duration) (t Ticker) {
+	return systemTicker{Ticker: time.NewTicker(d)}
+}
+
+// systemTimer is a Timer that wraps a *time.Timer.
+type systemTimer struct {
+	*time.Timer
+}
+
+// type check
+var _ Timer = systemTimer{}
+
+// C implements the Timer interface for systemTimer.
+func (t systemTimer) C() (c <-chan time.Time) {
+	return t.Timer.C
+}
+
+// systemTicker is a Ticker that wraps a *time.Ticker.
+type systemTicker struct {
+	*time.Ticker
+}
+
+// type check
+var _ Ticker = systemTicker{}
+
+// C implements the Ticker interface for systemTicker.
+func (t systemTicker) C() (c <-chan time.Time) {
+	return t.Ticker.C
+}
@@ -3,6 +3,10 @@
 package timeutil
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/AdguardTeam/golibs/errors"
@@ -60,13 +64,71 @@ func (d Duration) MarshalText() (text []byte, err error) {
 }
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface for
-// *Duration.
-//
-// TODO(e.burkov): Make it able to parse larger units like days.
+// *Duration.  It accepts the format of ParseDuration.  For backwards
+// compatibility, it also accepts plain integers, which are interpreted as
+// seconds.
 func (d *Duration) UnmarshalText(b []byte) (err error) {
 	defer func() { err = errors.Annotate(err, "unmarshaling duration: %w") }()
 
-	d.Duration, err = time.ParseDuration(string(b))
+	d.Duration, err = parseDuration(string(b))
+
+	return err
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for *Duration.  It
+// accepts JSON strings in the format of UnmarshalText as well as JSON integer
+// numbers, which are interpreted as seconds.  JSON null is a no-op, as
+// required by json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) (err error) {
+	if string(b) == "null" {
+		return nil
+	}
+
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		err = json.Unmarshal(b, &s)
+		if err != nil {
+			return fmt.Errorf("unmarshaling duration: %w", err)
+		}
+
+		return d.UnmarshalText([]byte(s))
+	}
+
+	defer func() { err = errors.Annotate(err, "unmarshaling duration: %w") }()
+
+	d.Duration, err = secondsToDuration(string(b))
 
 	return err
 }
+
+// parseDuration parses s as either a duration string in the format of
+// ParseDuration or an integer number of seconds.
+func parseDuration(s string) (d time.Duration, err error) {
+	d, err = ParseDuration(s)
+	if err == nil {
+		return d, nil
+	}
+
+	if _, intErr := strconv.ParseInt(s, 10, 64); intErr != nil {
+		// Return the original error, since it is more helpful for non-integer
+		// values.
+		return 0, err
+	}
+
+	return secondsToDuration(s)
+}
+
+// secondsToDuration parses s as an integer number of seconds.
+func secondsToDuration(s string) (d time.Duration, err error) {
+	secs, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad number of seconds %q: want an integer", s)
+	}
+
+	const maxSecs = int64(math.MaxInt64 / time.Second)
+	if secs > maxSecs || secs < -maxSecs {
+		return 0, fmt.Errorf("number of seconds %d out of range", secs)
+	}
+
+	return time.Duration(secs) * time.Second, nil
+}
@@ -1,12 +1,14 @@
 package timeutil_test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/AdguardTeam/golibs/testutil"
 	"github.com/AdguardTeam/golibs/timeutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDuration_String(t *testing.T) {
@@ -71,3 +73,129 @@ func TestDuration_encoding(t *testing.T) {
 	testutil.AssertMarshalText(t, "1ms", v)
 	testutil.AssertUnmarshalText(t, "1ms", v)
 }
+
+func TestDuration_UnmarshalText_seconds(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		in      string
+		wantErr string
+		want    time.Duration
+	}{{
+		name:    "duration",
+		in:      "1h30m",
+		wantErr: "",
+		want:    time.Hour + 30*time.Minute,
+	}, {
+		name:    "days",
+		in:      "1d12h",
+		wantErr: "",
+		want:    36 * time.Hour,
+	}, {
+		name:    "seconds",
+		in:      "90",
+		wantErr: "",
+		want:    90 * time.Second,
+	}, {
+		name:    "zero",
+		in:      "0",
+		wantErr: "",
+		want:    0,
+	}, {
+		name:    "bad",
+		in:      "1x",
+		wantErr: `unmarshaling duration: bad duration "1x": bad token "1x": unknown unit "x"`,
+		want:    0,
+	}, {
+		name:    "overflow",
+		in:      "10000000000",
+		wantErr: "unmarshaling duration: number of seconds 10000000000 out of range",
+		want:    0,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d := &timeutil.Duration{}
+			err := d.UnmarshalText([]byte(tc.in))
+			testutil.AssertErrorMsg(t, tc.wantErr, err)
+
+			assert.Equal(t, tc.want, d.Duration)
+		})
+	}
+}
+
+func TestDuration_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		in      string
+		wantErr string
+		want    time.Duration
+	}{{
+		name:    "string",
+		in:      `{"timeout":"1h30m"}`,
+		wantErr: "",
+		want:    time.Hour + 30*time.Minute,
+	}, {
+		name:    "string_seconds",
+		in:      `{"timeout":"10"}`,
+		wantErr: "",
+		want:    10 * time.Second,
+	}, {
+		name:    "integer",
+		in:      `{"timeout":10}`,
+		wantErr: "",
+		want:    10 * time.Second,
+	}, {
+		name:    "float",
+		in:      `{"timeout":1.5}`,
+		wantErr: `unmarshaling duration: bad number of seconds "1.5": want an integer`,
+		want:    0,
+	}, {
+		name:    "bad_string",
+		in:      `{"timeout":"1x"}`,
+		wantErr: `unmarshaling duration: bad duration "1x": bad token "1x": unknown unit "x"`,
+		want:    0,
+	}, {
+		name:    "null",
+		in:      `{"timeout":null}`,
+		wantErr: "",
+		want:    0,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := &struct {
+				Timeout timeutil.Duration `json:"timeout"`
+			}{}
+
+			err := json.Unmarshal([]byte(tc.in), v)
+			testutil.AssertErrorMsg(t, tc.wantErr, err)
+
+			assert.Equal(t, tc.want, v.Timeout.Duration)
+		})
+	}
+}
+
+func TestDuration_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	v := &struct {
+		Timeout timeutil.Duration `json:"timeout"`
+	}{
+		Timeout: timeutil.Duration{Duration: time.Hour + 30*time.Minute},
+	}
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"timeout":"1h30m"}`, string(b))
+}
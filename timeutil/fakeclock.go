@@ -0,0 +1,252 @@
+package timeutil
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a Clock with manually controlled time, intended for tests.  Its
+// timers and tickers only fire when the time is moved forward with Advance or
+// Set.  Like with package time, the channels of timers and tickers have a
+// buffer of one value, and ticks are dropped if the reader falls behind.
+type FakeClock struct {
+	// mu protects now and timers.
+	mu     *sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// type check
+var _ Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a new *FakeClock with the current time set to now.
+func NewFakeClock(now time.Time) (c *FakeClock) {
+	return &FakeClock{
+		mu:  &sync.Mutex{},
+		now: now,
+	}
+}
+
+// Now implements the Clock interface for *FakeClock.
+func (c *FakeClock) Now() (now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After implements the Clock interface for *FakeClock.
+func (c *FakeClock) After(d time.Duration) (ch <-chan time.Time) {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer implements the Clock interface for *FakeClock.
+func (c *FakeClock) NewTimer(d time.Duration) (t Timer) {
+	ft := &fakeTimer{
+		clock: c,
+		c:     make(chan time.Time, 1),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.schedule(ft, d)
+
+	return ft
+}
+
+// NewTicker implements the Clock interface for *FakeClock.  It panics if d is
+// not positive, like time.NewTicker.
+func (c *FakeClock) NewTicker(d time.Duration) (t Ticker) {
+	if d <= 0 {
+		panic("timeutil: non-positive interval for FakeClock.NewTicker")
+	}
+
+	ft := &fakeTimer{
+		clock:  c,
+		c:      make(chan time.Time, 1),
+		period: d,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.schedule(ft, d)
+
+	return fakeTicker{fakeTimer: ft}
+}
+
+// Advance moves the current time forward by d, firing all timers and tickers
+// that expire within that interval in the order of their expiration.  d must
+// not be negative.
+func (c *FakeClock) Advance(d time.Duration) {
+	if d < 0 {
+		panic("timeutil: negative duration for FakeClock.Advance")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(c.now.Add(d))
+}
+
+// Set sets the current time to now.  If now is after the current time, all
+// timers and tickers that expire within that interval are fired, as with
+// Advance.
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Before(c.now) {
+		c.now = now
+
+		return
+	}
+
+	c.advanceTo(now)
+}
+
+// PendingTimers returns the number of active timers and tickers.
+func (c *FakeClock) PendingTimers() (n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+// advanceTo fires the timers that expire before or at end and sets the current
+// time to end.  c.mu must be locked.
+func (c *FakeClock) advanceTo(end time.Time) {
+	for {
+		t := c.next()
+		if t == nil || t.when.After(end) {
+			break
+		}
+
+		c.now = t.when
+		t.fire()
+		if t.period > 0 {
+			t.when = t.when.Add(t.period)
+		} else {
+			c.unschedule(t)
+		}
+	}
+
+	c.now = end
+}
+
+// next returns the timer that expires first or nil if there are none.  c.mu
+// must be locked.
+func (c *FakeClock) next() (t *fakeTimer) {
+	for _, ft := range c.timers {
+		if t == nil || ft.when.Before(t.when) {
+			t = ft
+		}
+	}
+
+	return t
+}
+
+// schedule makes t active and sets it to expire after d.  c.mu must be locked.
+func (c *FakeClock) schedule(t *fakeTimer, d time.Duration) {
+	t.when = c.now.Add(d)
+	if !t.active {
+		t.active = true
+		c.timers = append(c.timers, t)
+	}
+
+	if d <= 0 && t.period == 0 {
+		// Fire immediately, as time.Timer does.
+		c.advanceTo(c.now)
+	}
+}
+
+// unschedule makes t inactive and reports whether it has been active.  c.mu
+// must be locked.
+func (c *FakeClock) unschedule(t *fakeTimer) (ok bool) {
+	if !t.active {
+		return false
+	}
+
+	t.active = false
+	for i, ft := range c.timers {
+		if ft == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+
+			break
+		}
+	}
+
+	return true
+}
+
+// fakeTimer is a Timer created by a *FakeClock.
+type fakeTimer struct {
+	clock  *FakeClock
+	c      chan time.Time
+	when   time.Time
+	period time.Duration
+	active bool
+}
+
+// type check
+var _ Timer = (*fakeTimer)(nil)
+
+// C implements the Timer interface for *fakeTimer.
+func (t *fakeTimer) C() (c <-chan time.Time) {
+	return t.c
+}
+
+// Stop implements the Timer interface for *fakeTimer.
+func (t *fakeTimer) Stop() (ok bool) {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.clock.unschedule(t)
+}
+
+// Reset implements the Timer interface for *fakeTimer.
+func (t *fakeTimer) Reset(d time.Duration) (ok bool) {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	ok = t.active
+	t.clock.schedule(t, d)
+
+	return ok
+}
+
+// fire sends the current time of the clock on the channel, dropping it if the
+// channel is full.  t.clock.mu must be locked.
+func (t *fakeTimer) fire() {
+	select {
+	case t.c <- t.clock.now:
+	default:
+	}
+}
+
+// fakeTicker is a Ticker created by a *FakeClock.
+type fakeTicker struct {
+	*fakeTimer
+}
+
+// type check
+var _ Ticker = fakeTicker{}
+
+// Stop implements the Ticker interface for fakeTicker.
+func (t fakeTicker) Stop() {
+	_ = t.fakeTimer.Stop()
+}
+
+// Reset implements the Ticker interface for fakeTicker.
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("timeutil: non-positive interval for Ticker.Reset")
+	}
+
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	t.period = d
+	t.clock.schedule(t.fakeTimer, d)
+}
@@ -0,0 +1,109 @@
+package timeutil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStart is the common start time for tests.
+var testStart = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// receive returns the value from c, if there is one.
+func receive(c <-chan time.Time) (t time.Time, ok bool) {
+	select {
+	case t = <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFakeClock_timer(t *testing.T) {
+	t.Parallel()
+
+	c := timeutil.NewFakeClock(testStart)
+	timer := c.NewTimer(time.Second)
+	require.Equal(t, 1, c.PendingTimers())
+
+	c.Advance(999 * time.Millisecond)
+	_, ok := receive(timer.C())
+	assert.False(t, ok)
+
+	c.Advance(time.Millisecond)
+	got, ok := receive(timer.C())
+	require.True(t, ok)
+
+	assert.Equal(t, testStart.Add(time.Second), got)
+	assert.Equal(t, 0, c.PendingTimers())
+	assert.False(t, timer.Stop())
+
+	assert.False(t, timer.Reset(time.Second))
+	assert.True(t, timer.Stop())
+
+	c.Advance(time.Hour)
+	_, ok = receive(timer.C())
+	assert.False(t, ok)
+}
+
+func TestFakeClock_ticker(t *testing.T) {
+	t.Parallel()
+
+	c := timeutil.NewFakeClock(testStart)
+	ticker := c.NewTicker(time.Second)
+
+	c.Advance(time.Second)
+	got, ok := receive(ticker.C())
+	require.True(t, ok)
+
+	assert.Equal(t, testStart.Add(time.Second), got)
+
+	// Only one tick is buffered, the rest are dropped.
+	c.Advance(3 * time.Second)
+	got, ok = receive(ticker.C())
+	require.True(t, ok)
+
+	assert.Equal(t, testStart.Add(2*time.Second), got)
+
+	_, ok = receive(ticker.C())
+	assert.False(t, ok)
+
+	ticker.Reset(time.Minute)
+	c.Advance(time.Minute)
+	got, ok = receive(ticker.C())
+	require.True(t, ok)
+
+	assert.Equal(t, testStart.Add(4*time.Second+time.Minute), got)
+
+	ticker.Stop()
+	assert.Equal(t, 0, c.PendingTimers())
+
+	assert.Panics(t, func() { c.NewTicker(0) })
+}
+
+func TestFakeClock_After(t *testing.T) {
+	t.Parallel()
+
+	c := timeutil.NewFakeClock(testStart)
+	ch := c.After(0)
+
+	got, ok := receive(ch)
+	require.True(t, ok)
+
+	assert.Equal(t, testStart, got)
+
+	ch = c.After(time.Hour)
+	c.Set(testStart.Add(2 * time.Hour))
+
+	got, ok = receive(ch)
+	require.True(t, ok)
+
+	assert.Equal(t, testStart.Add(time.Hour), got)
+	assert.Equal(t, testStart.Add(2*time.Hour), c.Now())
+
+	c.Set(testStart)
+	assert.Equal(t, testStart, c.Now())
+}
@@ -0,0 +1,205 @@
+package timeutil
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/mathutil"
+)
+
+// Week is the duration of one week.
+const Week time.Duration = 7 * Day
+
+// errOverflow is returned when a duration or a part of it doesn't fit into
+// time.Duration.
+const errOverflow errors.Error = "overflow"
+
+// ParseDuration parses a duration string like time.ParseDuration does, but
+// additionally supports the units "d" for days and "w" for weeks, for example
+// "7d" or "2w12h".  A day is always 24 hours long.  The returned errors name the
+// invalid part of the string.
+func ParseDuration(s string) (d time.Duration, err error) {
+	orig := s
+
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	if s == "0" {
+		return 0, nil
+	} else if s == "" {
+		return 0, fmt.Errorf("bad duration %q: empty", orig)
+	}
+
+	// The absolute value of math.MinInt64 is greater than math.MaxInt64 by one.
+	limit := uint64(math.MaxInt64)
+	if neg {
+		limit++
+	}
+
+	abs, err := parseDurationAbs(s, limit)
+	if err != nil {
+		return 0, fmt.Errorf("bad duration %q: %w", orig, err)
+	}
+
+	// Converting 1<<63 produces math.MinInt64, the negation of which is also
+	// math.MinInt64, which is the correct result.
+	d = time.Duration(abs)
+	if neg {
+		d = -d
+	}
+
+	return d, nil
+}
+
+// parseDurationAbs parses the absolute value of a duration string without the
+// sign.  The result must not be greater than limit.
+func parseDurationAbs(s string, limit uint64) (abs uint64, err error) {
+	for s != "" {
+		var tok, num, unit string
+		tok, num, unit, s = nextDurationToken(s)
+		if num == "" {
+			return 0, fmt.Errorf("bad token %q: no number", tok)
+		}
+
+		var part time.Duration
+		part, err = parseDurationToken(num, unit)
+		if err != nil {
+			return 0, fmt.Errorf("bad token %q: %w", tok, err)
+		}
+
+		var overflow bool
+		abs, overflow = mathutil.CheckedAdd(abs, uint64(part))
+		if overflow || abs > limit {
+			return 0, errOverflow
+		}
+	}
+
+	return abs, nil
+}
+
+// nextDurationToken splits the next token consisting of a number and a unit
+// from s.
+func nextDurationToken(s string) (tok, num, unit, rest string) {
+	i := strings.IndexFunc(s, func(r rune) (ok bool) {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		return s, s, "", ""
+	}
+
+	num = s[:i]
+
+	j := strings.IndexFunc(s[i:], func(r rune) (ok bool) {
+		return (r >= '0' && r <= '9') || r == '.'
+	})
+	if j < 0 {
+		return s, num, s[i:], ""
+	}
+
+	return s[:i+j], num, s[i : i+j], s[i+j:]
+}
+
+// parseDurationToken parses a single number with a unit.
+func parseDurationToken(num, unit string) (d time.Duration, err error) {
+	switch unit {
+	case "":
+		return 0, errors.Error("missing unit")
+	case "ns", "us", "µs", "μs", "ms", "s", "m", "h":
+		// Use package time for the standard units, since it doesn't lose
+		// precision for large numbers of small units.
+		d, err = time.ParseDuration(num + unit)
+		if err != nil {
+			// The number consists of digits and dots only and the unit is
+			// valid, so this can only be an overflow or a bad number.
+			return 0, standardUnitError(num)
+		}
+
+		return d, nil
+	case "d":
+		return parseLongUnit(num, Day)
+	case "w":
+		return parseLongUnit(num, Week)
+	default:
+		return 0, fmt.Errorf("unknown unit %q", unit)
+	}
+}
+
+// standardUnitError returns the error for a number with a standard unit that
+// package time has failed to parse.
+func standardUnitError(num string) (err error) {
+	if strings.Count(num, ".") > 1 || num == "." {
+		return fmt.Errorf("bad number %q", num)
+	}
+
+	return errOverflow
+}
+
+// parseLongUnit parses num, which may have a fractional part, as a number of
+// units without using floating-point arithmetic, so that no precision is lost.
+// The fractional part is truncated to whole nanoseconds.
+func parseLongUnit(num string, unit time.Duration) (d time.Duration, err error) {
+	intStr, fracStr, _ := strings.Cut(num, ".")
+	if intStr == "" && fracStr == "" {
+		return 0, fmt.Errorf("bad number %q", num)
+	}
+
+	if intStr != "" {
+		var whole int64
+		whole, err = strconv.ParseInt(intStr, 10, 64)
+		if err != nil {
+			return 0, longUnitError(num, err)
+		}
+
+		var overflow bool
+		d, overflow = mathutil.CheckedMul(time.Duration(whole), unit)
+		if overflow {
+			return 0, errOverflow
+		}
+	}
+
+	frac, err := parseFraction(fracStr, unit)
+	if err != nil {
+		return 0, fmt.Errorf("bad number %q", num)
+	}
+
+	d, overflow := mathutil.CheckedAdd(d, frac)
+	if overflow {
+		return 0, errOverflow
+	}
+
+	return d, nil
+}
+
+// longUnitError converts an error from strconv.ParseInt into an error for
+// parseLongUnit.
+func longUnitError(num string, err error) (res error) {
+	if errors.Is(err, strconv.ErrRange) {
+		return errOverflow
+	}
+
+	return fmt.Errorf("bad number %q", num)
+}
+
+// parseFraction returns the part of unit represented by the decimal digits of
+// the fractional part of a number.  The result is truncated to whole
+// nanoseconds.
+func parseFraction(fracStr string, unit time.Duration) (d time.Duration, err error) {
+	scale := unit
+	for _, c := range fracStr {
+		if c < '0' || c > '9' {
+			return 0, errors.Error("not a digit")
+		}
+
+		scale /= 10
+		d += time.Duration(c-'0') * scale
+	}
+
+	return d, nil
+}
@@ -0,0 +1,144 @@
+package timeutil_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		in      string
+		wantErr string
+		want    time.Duration
+	}{{
+		name:    "zero",
+		in:      "0",
+		wantErr: "",
+		want:    0,
+	}, {
+		name:    "standard",
+		in:      "1h30m",
+		wantErr: "",
+		want:    time.Hour + 30*time.Minute,
+	}, {
+		name:    "days",
+		in:      "7d",
+		wantErr: "",
+		want:    7 * timeutil.Day,
+	}, {
+		name:    "weeks_hours",
+		in:      "2w12h",
+		wantErr: "",
+		want:    2*timeutil.Week + 12*time.Hour,
+	}, {
+		name:    "fraction",
+		in:      "1.5d",
+		wantErr: "",
+		want:    36 * time.Hour,
+	}, {
+		name:    "negative",
+		in:      "-1d1ms",
+		wantErr: "",
+		want:    -(timeutil.Day + time.Millisecond),
+	}, {
+		name:    "micro",
+		in:      "1d1µs",
+		wantErr: "",
+		want:    timeutil.Day + time.Microsecond,
+	}, {
+		name:    "empty",
+		in:      "",
+		wantErr: `bad duration "": empty`,
+		want:    0,
+	}, {
+		name:    "sign_only",
+		in:      "-",
+		wantErr: `bad duration "-": empty`,
+		want:    0,
+	}, {
+		name:    "no_unit",
+		in:      "1d12",
+		wantErr: `bad duration "1d12": bad token "12": missing unit`,
+		want:    0,
+	}, {
+		name:    "no_number",
+		in:      "d",
+		wantErr: `bad duration "d": bad token "d": no number`,
+		want:    0,
+	}, {
+		name:    "bad_unit",
+		in:      "1d2y",
+		wantErr: `bad duration "1d2y": bad token "2y": unknown unit "y"`,
+		want:    0,
+	}, {
+		name:    "bad_number",
+		in:      "1.2.3d",
+		wantErr: `bad duration "1.2.3d": bad token "1.2.3d": bad number "1.2.3"`,
+		want:    0,
+	}, {
+		name:    "overflow",
+		in:      "100000w",
+		wantErr: `bad duration "100000w": bad token "100000w": overflow`,
+		want:    0,
+	}, {
+		name:    "overflow_standard",
+		in:      "10000000000h",
+		wantErr: `bad duration "10000000000h": bad token "10000000000h": overflow`,
+		want:    0,
+	}, {
+		name:    "overflow_total",
+		in:      "15000w15000w",
+		wantErr: `bad duration "15000w15000w": overflow`,
+		want:    0,
+	}, {
+		name:    "max",
+		in:      "2562047h47m16.854775807s",
+		wantErr: "",
+		want:    math.MaxInt64,
+	}, {
+		name:    "max_overflow",
+		in:      "2562047h47m16.854775808s",
+		wantErr: `bad duration "2562047h47m16.854775808s": overflow`,
+		want:    0,
+	}, {
+		name:    "min",
+		in:      "-2562047h47m16.854775808s",
+		wantErr: "",
+		want:    math.MinInt64,
+	}, {
+		name:    "max_days",
+		in:      "106751d23h47m16.854775807s",
+		wantErr: "",
+		want:    math.MaxInt64,
+	}, {
+		name:    "days_exact",
+		in:      "200d1ns",
+		wantErr: "",
+		want:    200*timeutil.Day + 1,
+	}, {
+		name:    "weeks_fraction_exact",
+		in:      "1000.000000000000001w",
+		wantErr: "",
+		want:    1000 * timeutil.Week,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			d, err := timeutil.ParseDuration(tc.in)
+			testutil.AssertErrorMsg(t, tc.wantErr, err)
+
+			assert.Equal(t, tc.want, d)
+		})
+	}
+}
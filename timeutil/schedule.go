@@ -0,0 +1,315 @@
+package timeutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+// DayRange is a range of wall-clock time within a single day.  Start and End
+// are the offsets from midnight, Start is inclusive and End is exclusive.  The
+// zero DayRange is empty and contains no time.
+type DayRange struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// IsZero returns true if r is an empty range.
+func (r DayRange) IsZero() (ok bool) {
+	return r == DayRange{}
+}
+
+// Validate returns an error if r is not a valid range.  The zero DayRange is
+// valid.
+func (r DayRange) Validate() (err error) {
+	switch {
+	case r.IsZero():
+		return nil
+	case r.Start < 0:
+		return fmt.Errorf("start %s is negative", r.Start)
+	case r.End > Day:
+		return fmt.Errorf("end %s is greater than %s", r.End, Day)
+	case r.Start >= r.End:
+		return fmt.Errorf("start %s is not less than end %s", r.Start, r.End)
+	default:
+		return nil
+	}
+}
+
+// contains returns true if the offset from midnight is within r.
+func (r DayRange) contains(offset time.Duration) (ok bool) {
+	return offset >= r.Start && offset < r.End
+}
+
+// String implements the fmt.Stringer interface for DayRange.  The format is
+// "HH:MM-HH:MM", with seconds added if there are any.
+func (r DayRange) String() (s string) {
+	b := appendTimeOfDay(nil, r.Start)
+	b = append(b, '-')
+	b = appendTimeOfDay(b, r.End)
+
+	return string(b)
+}
+
+// appendTimeOfDay appends the offset from midnight to b in the "HH:MM" or
+// "HH:MM:SS" format.
+func appendTimeOfDay(b []byte, d time.Duration) (res []byte) {
+	h, m, s := d/time.Hour, d%time.Hour/time.Minute, d%time.Minute/time.Second
+
+	b = appendTwoDigits(b, int(h))
+	b = append(b, ':')
+	b = appendTwoDigits(b, int(m))
+	if s != 0 {
+		b = append(b, ':')
+		b = appendTwoDigits(b, int(s))
+	}
+
+	return b
+}
+
+// appendTwoDigits appends n to b, padded with a leading zero if necessary.
+func appendTwoDigits(b []byte, n int) (res []byte) {
+	if n < 10 {
+		b = append(b, '0')
+	}
+
+	return strconv.AppendInt(b, int64(n), 10)
+}
+
+// parseDayRange parses a range in the format of DayRange.String.
+func parseDayRange(s string) (r DayRange, err error) {
+	startStr, endStr, ok := strings.Cut(s, "-")
+	if !ok {
+		return DayRange{}, fmt.Errorf("bad day range %q: no separator", s)
+	}
+
+	r.Start, err = parseTimeOfDay(startStr)
+	if err != nil {
+		return DayRange{}, fmt.Errorf("bad day range %q: start: %w", s, err)
+	}
+
+	r.End, err = parseTimeOfDay(endStr)
+	if err != nil {
+		return DayRange{}, fmt.Errorf("bad day range %q: end: %w", s, err)
+	}
+
+	err = r.Validate()
+	if err != nil {
+		return DayRange{}, fmt.Errorf("bad day range %q: %w", s, err)
+	}
+
+	return r, nil
+}
+
+// parseTimeOfDay parses an offset from midnight in the "HH:MM" or "HH:MM:SS"
+// format.  "24:00" is accepted as the end of the day.
+func parseTimeOfDay(s string) (d time.Duration, err error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, fmt.Errorf("bad time of day %q: want HH:MM or HH:MM:SS", s)
+	}
+
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	limits := []int{24, 59, 59}
+	for i, p := range parts {
+		if len(p) != 2 {
+			return 0, fmt.Errorf("bad time of day %q: want two digits, got %q", s, p)
+		}
+
+		var n int
+		n, err = strconv.Atoi(p)
+		if err != nil || n < 0 || n > limits[i] {
+			return 0, fmt.Errorf("bad time of day %q: bad value %q", s, p)
+		}
+
+		d += time.Duration(n) * units[i]
+	}
+
+	if d > Day {
+		return 0, fmt.Errorf("bad time of day %q: after end of day", s)
+	}
+
+	return d, nil
+}
+
+// weekdayNames are the short names of the days of week as used in the text
+// representation of a Schedule.
+var weekdayNames = [7]string{
+	time.Sunday:    "sun",
+	time.Monday:    "mon",
+	time.Tuesday:   "tue",
+	time.Wednesday: "wed",
+	time.Thursday:  "thu",
+	time.Friday:    "fri",
+	time.Saturday:  "sat",
+}
+
+// parseWeekday parses the short name of a weekday.
+func parseWeekday(s string) (wd time.Weekday, err error) {
+	for i, name := range weekdayNames {
+		if name == s {
+			return time.Weekday(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("bad weekday %q", s)
+}
+
+// Schedule is a weekly schedule, which contains a range of wall-clock time for
+// every day of week in a certain time zone.  Since the ranges are wall-clock,
+// a range like 09:00-17:00 covers exactly those hours of the local time even
+// on the days when the daylight saving time starts or ends.  Ranges crossing
+// midnight should be split between the two days.
+//
+// The zero Schedule is empty and uses UTC.  Its methods must not be called
+// concurrently with SetDay or UnmarshalText.
+type Schedule struct {
+	location *time.Location
+	days     [7]DayRange
+}
+
+// NewSchedule returns a new empty *Schedule in the location loc.  If loc is nil,
+// time.UTC is used.
+func NewSchedule(loc *time.Location) (s *Schedule) {
+	return &Schedule{
+		location: loc,
+	}
+}
+
+// Location returns the location of the schedule.
+func (s *Schedule) Location() (loc *time.Location) {
+	if s.location == nil {
+		return time.UTC
+	}
+
+	return s.location
+}
+
+// Day returns the range for the weekday wd.
+func (s *Schedule) Day(wd time.Weekday) (r DayRange) {
+	return s.days[wd]
+}
+
+// SetDay validates r and sets it as the range for the weekday wd.
+func (s *Schedule) SetDay(wd time.Weekday, r DayRange) (err error) {
+	if wd < time.Sunday || wd > time.Saturday {
+		return fmt.Errorf("bad weekday %d", wd)
+	}
+
+	err = r.Validate()
+	if err != nil {
+		return fmt.Errorf("range for %s: %w", wd, err)
+	}
+
+	s.days[wd] = r
+
+	return nil
+}
+
+// Validate returns an error if any range in s is invalid.
+func (s *Schedule) Validate() (err error) {
+	var errs []error
+	for wd, r := range s.days {
+		err = r.Validate()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("range for %s: %w", time.Weekday(wd), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Contains returns true if t is within the range of its weekday in the location
+// of s.
+func (s *Schedule) Contains(t time.Time) (ok bool) {
+	t = t.In(s.Location())
+	r := s.days[t.Weekday()]
+	if r.IsZero() {
+		return false
+	}
+
+	h, m, sec := t.Clock()
+	offset := time.Duration(h)*time.Hour +
+		time.Duration(m)*time.Minute +
+		time.Duration(sec)*time.Second +
+		time.Duration(t.Nanosecond())
+
+	return r.contains(offset)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for Schedule.
+// The format is the name of the location followed by the non-empty ranges,
+// separated by spaces, for example:
+//
+//   Europe/Berlin mon=09:00-17:00 sat=10:00-12:30
+//
+// Since Schedule implements encoding.TextMarshaler, it is encoded as a JSON
+// string.
+func (s Schedule) MarshalText() (text []byte, err error) {
+	text = append(text, s.Location().String()...)
+	for wd, r := range s.days {
+		if r.IsZero() {
+			continue
+		}
+
+		text = append(text, ' ')
+		text = append(text, weekdayNames[wd]...)
+		text = append(text, '=')
+		text = append(text, r.String()...)
+	}
+
+	return text, nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for
+// *Schedule.  See MarshalText for the format.  The weekdays that are not
+// mentioned get empty ranges.
+func (s *Schedule) UnmarshalText(b []byte) (err error) {
+	defer func() { err = errors.Annotate(err, "unmarshaling schedule: %w") }()
+
+	fields := strings.Fields(string(b))
+	if len(fields) == 0 {
+		return errors.Error("empty schedule")
+	}
+
+	loc, err := time.LoadLocation(fields[0])
+	if err != nil {
+		return fmt.Errorf("loading location: %w", err)
+	}
+
+	sch := Schedule{
+		location: loc,
+	}
+
+	var seen [7]bool
+	for _, f := range fields[1:] {
+		dayStr, rangeStr, ok := strings.Cut(f, "=")
+		if !ok {
+			return fmt.Errorf("bad day %q: no separator", f)
+		}
+
+		var wd time.Weekday
+		wd, err = parseWeekday(dayStr)
+		if err != nil {
+			return err
+		}
+
+		if seen[wd] {
+			return fmt.Errorf("duplicate weekday %q", dayStr)
+		}
+
+		seen[wd] = true
+
+		sch.days[wd], err = parseDayRange(rangeStr)
+		if err != nil {
+			return fmt.Errorf("%s: %w", dayStr, err)
+		}
+	}
+
+	*s = sch
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package timeutil_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/AdguardTeam/golibs/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSchedule returns a schedule in Europe/Berlin with Monday and Sunday
+// ranges.
+func newTestSchedule(t *testing.T) (s *timeutil.Schedule) {
+	t.Helper()
+
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	s = timeutil.NewSchedule(loc)
+
+	err = s.SetDay(time.Monday, timeutil.DayRange{
+		Start: 9 * time.Hour,
+		End:   17*time.Hour + 30*time.Minute,
+	})
+	require.NoError(t, err)
+
+	// 2023-03-26 is a Sunday, when the daylight saving time starts in Berlin.
+	err = s.SetDay(time.Sunday, timeutil.DayRange{
+		Start: 1 * time.Hour,
+		End:   4 * time.Hour,
+	})
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestSchedule_Contains(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSchedule(t)
+
+	testCases := []struct {
+		time time.Time
+		name string
+		want bool
+	}{{
+		time: time.Date(2023, 3, 20, 8, 0, 0, 0, time.UTC),
+		name: "monday_inside",
+		want: true,
+	}, {
+		time: time.Date(2023, 3, 20, 7, 59, 59, 0, time.UTC),
+		name: "monday_before",
+		want: false,
+	}, {
+		time: time.Date(2023, 3, 20, 16, 29, 59, 0, time.UTC),
+		name: "monday_last_second",
+		want: true,
+	}, {
+		time: time.Date(2023, 3, 20, 16, 30, 0, 0, time.UTC),
+		name: "monday_end",
+		want: false,
+	}, {
+		time: time.Date(2023, 3, 21, 10, 0, 0, 0, time.UTC),
+		name: "tuesday_empty",
+		want: false,
+	}, {
+		// 00:30 UTC is 01:30 CET.
+		time: time.Date(2023, 3, 26, 0, 30, 0, 0, time.UTC),
+		name: "dst_before_switch",
+		want: true,
+	}, {
+		// 01:30 UTC is 03:30 CEST.
+		time: time.Date(2023, 3, 26, 1, 30, 0, 0, time.UTC),
+		name: "dst_after_switch",
+		want: true,
+	}, {
+		// 02:00 UTC is 04:00 CEST, although only two hours have passed since
+		// 01:00 CET.
+		time: time.Date(2023, 3, 26, 2, 0, 0, 0, time.UTC),
+		name: "dst_end",
+		want: false,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.want, s.Contains(tc.time))
+		})
+	}
+}
+
+func TestSchedule_encoding(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSchedule(t)
+
+	const text = "Europe/Berlin sun=01:00-04:00 mon=09:00-17:30"
+
+	testutil.AssertMarshalText(t, text, s)
+	testutil.AssertUnmarshalText(t, text, s)
+
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	assert.Equal(t, `"`+text+`"`, string(b))
+}
+
+func TestSchedule_UnmarshalText(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		in      string
+		wantErr string
+	}{{
+		name:    "utc_whole_day",
+		in:      "UTC sat=00:00-24:00",
+		wantErr: "",
+	}, {
+		name:    "seconds",
+		in:      "UTC sat=00:00:30-12:00",
+		wantErr: "",
+	}, {
+		name:    "empty",
+		in:      "",
+		wantErr: "unmarshaling schedule: empty schedule",
+	}, {
+		name:    "bad_location",
+		in:      "Mars/Olympus",
+		wantErr: "unmarshaling schedule: loading location: unknown time zone Mars/Olympus",
+	}, {
+		name:    "bad_weekday",
+		in:      "UTC xyz=09:00-10:00",
+		wantErr: `unmarshaling schedule: bad weekday "xyz"`,
+	}, {
+		name:    "duplicate",
+		in:      "UTC mon=09:00-10:00 mon=11:00-12:00",
+		wantErr: `unmarshaling schedule: duplicate weekday "mon"`,
+	}, {
+		name:    "no_day_separator",
+		in:      "UTC mon",
+		wantErr: `unmarshaling schedule: bad day "mon": no separator`,
+	}, {
+		name:    "no_range_separator",
+		in:      "UTC mon=09:00",
+		wantErr: `unmarshaling schedule: mon: bad day range "09:00": no separator`,
+	}, {
+		name: "bad_time",
+		in:   "UTC mon=9:00-10:00",
+		wantErr: `unmarshaling schedule: mon: bad day range "9:00-10:00": start: ` +
+			`bad time of day "9:00": want two digits, got "9"`,
+	}, {
+		name: "bad_minutes",
+		in:   "UTC mon=09:60-10:00",
+		wantErr: `unmarshaling schedule: mon: bad day range "09:60-10:00": start: ` +
+			`bad time of day "09:60": bad value "60"`,
+	}, {
+		name: "after_end_of_day",
+		in:   "UTC mon=09:00-24:01",
+		wantErr: `unmarshaling schedule: mon: bad day range "09:00-24:01": end: ` +
+			`bad time of day "24:01": after end of day`,
+	}, {
+		name: "reversed",
+		in:   "UTC mon=10:00-09:00",
+		wantErr: `unmarshaling schedule: mon: bad day range "10:00-09:00": ` +
+			`start 10h0m0s is not less than end 9h0m0s`,
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &timeutil.Schedule{}
+			err := s.UnmarshalText([]byte(tc.in))
+			testutil.AssertErrorMsg(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestSchedule_SetDay(t *testing.T) {
+	t.Parallel()
+
+	s := timeutil.NewSchedule(nil)
+	assert.Equal(t, time.UTC, s.Location())
+
+	err := s.SetDay(time.Monday, timeutil.DayRange{Start: -time.Hour, End: time.Hour})
+	testutil.AssertErrorMsg(t, "range for Monday: start -1h0m0s is negative", err)
+
+	err = s.SetDay(7, timeutil.DayRange{})
+	testutil.AssertErrorMsg(t, "bad weekday 7", err)
+
+	assert.NoError(t, s.Validate())
+}
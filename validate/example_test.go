@@ -0,0 +1,35 @@
+package validate_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AdguardTeam/golibs/errors"
+	"github.com/AdguardTeam/golibs/validate"
+)
+
+func Example() {
+	type config struct {
+		Name     string
+		Upstream []string
+		Timeout  time.Duration
+		Port     uint16
+	}
+
+	c := &config{
+		Upstream: []string{"1.1.1.1", ""},
+		Port:     53,
+	}
+
+	err := errors.Join(
+		validate.NotEmpty("name", c.Name),
+		validate.EachSliceElem("upstream", c.Upstream, validate.NotEmpty[string]),
+		validate.Positive("timeout", c.Timeout),
+		validate.InRange("port", c.Port, 1, 65535),
+	)
+
+	fmt.Println(err)
+
+	// Output:
+	// 3 errors: "name: empty value", "upstream[1]: empty value", "timeout: not positive: 0s"
+}
@@ -0,0 +1,114 @@
+// Package validate contains functions for validating values, for example the
+// fields of configuration structures.
+//
+// The functions return errors that contain the name of the value, so that they
+// can be collected and joined using errors.Join, for example:
+//
+//	func (c *Config) Validate() (err error) {
+//		return errors.Join(
+//			validate.NotEmpty("name", c.Name),
+//			validate.Positive("timeout", c.Timeout),
+//			validate.InRange("port", c.Port, 1, 65535),
+//		)
+//	}
+package validate
+
+import (
+	"cmp"
+	"fmt"
+
+	"github.com/AdguardTeam/golibs/errors"
+)
+
+const (
+	// ErrEmptyValue is returned when a value must not be empty.
+	ErrEmptyValue errors.Error = "empty value"
+
+	// ErrNilValue is returned when a value must not be nil.
+	ErrNilValue errors.Error = "nil value"
+
+	// ErrNotPositive is returned when a value must be positive.
+	ErrNotPositive errors.Error = "not positive"
+
+	// ErrOutOfRange is returned when a value is outside of the allowed range.
+	ErrOutOfRange errors.Error = "out of range"
+)
+
+// NotEmpty returns an error wrapping ErrEmptyValue if v is the zero value of
+// its type.
+func NotEmpty[T comparable](name string, v T) (err error) {
+	var zero T
+	if v == zero {
+		return fmt.Errorf("%s: %w", name, ErrEmptyValue)
+	}
+
+	return nil
+}
+
+// NotEmptySlice returns an error wrapping ErrEmptyValue if s is empty.
+func NotEmptySlice[T any](name string, s []T) (err error) {
+	if len(s) == 0 {
+		return fmt.Errorf("%s: %w", name, ErrEmptyValue)
+	}
+
+	return nil
+}
+
+// NotNil returns an error wrapping ErrNilValue if v is nil.
+func NotNil[T any](name string, v *T) (err error) {
+	if v == nil {
+		return fmt.Errorf("%s: %w", name, ErrNilValue)
+	}
+
+	return nil
+}
+
+// Positive returns an error wrapping ErrNotPositive if v is less than or equal
+// to zero.
+func Positive[T cmp.Ordered](name string, v T) (err error) {
+	var zero T
+	if v <= zero {
+		return fmt.Errorf("%s: %w: %v", name, ErrNotPositive, v)
+	}
+
+	return nil
+}
+
+// NoGreaterThan returns an error wrapping ErrOutOfRange if v is greater than
+// maxVal.
+func NoGreaterThan[T cmp.Ordered](name string, v, maxVal T) (err error) {
+	if v > maxVal {
+		return fmt.Errorf("%s: %w: must be no greater than %v, got %v", name, ErrOutOfRange, maxVal, v)
+	}
+
+	return nil
+}
+
+// InRange returns an error wrapping ErrOutOfRange if v is outside of the closed
+// interval [minVal, maxVal].
+func InRange[T cmp.Ordered](name string, v, minVal, maxVal T) (err error) {
+	if v < minVal || v > maxVal {
+		return fmt.Errorf(
+			"%s: %w: must be within [%v, %v], got %v",
+			name,
+			ErrOutOfRange,
+			minVal,
+			maxVal,
+			v,
+		)
+	}
+
+	return nil
+}
+
+// EachSliceElem validates each element of s using f and returns all errors
+// joined.  The name of each element is its index within name, for example
+// "upstreams[1]".
+func EachSliceElem[T any](name string, s []T, f func(name string, v T) (err error)) (err error) {
+	var errs []error
+	for i, v := range s {
+		errs = append(errs, f(fmt.Sprintf("%s[%d]", name, i), v))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,93 @@
+package validate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AdguardTeam/golibs/testutil"
+	"github.com/AdguardTeam/golibs/validate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	var nilPtr *int
+	num := 1
+
+	testCases := []struct {
+		err     error
+		name    string
+		wantErr string
+	}{{
+		err:     validate.NotEmpty("name", ""),
+		name:    "not_empty_bad",
+		wantErr: "name: empty value",
+	}, {
+		err:     validate.NotEmpty("name", "value"),
+		name:    "not_empty_good",
+		wantErr: "",
+	}, {
+		err:     validate.NotEmptySlice("list", []int{}),
+		name:    "not_empty_slice_bad",
+		wantErr: "list: empty value",
+	}, {
+		err:     validate.NotNil("ptr", nilPtr),
+		name:    "not_nil_bad",
+		wantErr: "ptr: nil value",
+	}, {
+		err:     validate.NotNil("ptr", &num),
+		name:    "not_nil_good",
+		wantErr: "",
+	}, {
+		err:     validate.Positive("timeout", time.Duration(0)),
+		name:    "positive_bad",
+		wantErr: "timeout: not positive: 0s",
+	}, {
+		err:     validate.Positive("timeout", time.Second),
+		name:    "positive_good",
+		wantErr: "",
+	}, {
+		err:     validate.NoGreaterThan("size", 11, 10),
+		name:    "no_greater_than_bad",
+		wantErr: "size: out of range: must be no greater than 10, got 11",
+	}, {
+		err:     validate.NoGreaterThan("size", 10, 10),
+		name:    "no_greater_than_good",
+		wantErr: "",
+	}, {
+		err:     validate.InRange("port", 0, 1, 65535),
+		name:    "in_range_bad",
+		wantErr: "port: out of range: must be within [1, 65535], got 0",
+	}, {
+		err:     validate.InRange("port", 53, 1, 65535),
+		name:    "in_range_good",
+		wantErr: "",
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			testutil.AssertErrorMsg(t, tc.wantErr, tc.err)
+		})
+	}
+}
+
+func TestEachSliceElem(t *testing.T) {
+	t.Parallel()
+
+	err := validate.EachSliceElem("ports", []int{53, 0, 70000}, func(name string, v int) (err error) {
+		return validate.InRange(name, v, 1, 65535)
+	})
+
+	testutil.AssertErrorMsg(
+		t,
+		`2 errors: `+
+			`"ports[1]: out of range: must be within [1, 65535], got 0", `+
+			`"ports[2]: out of range: must be within [1, 65535], got 70000"`,
+		err,
+	)
+	assert.ErrorIs(t, err, validate.ErrOutOfRange)
+}